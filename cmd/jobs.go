@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/scheduler"
+	"github.com/jampa_trip/pkg/util"
+)
+
+const (
+	defaultPaymentReconcileInterval = 15 * time.Minute
+	defaultPaymentReconcileMinAge   = 30 * time.Minute
+	defaultPaymentReconcileLimit    = 500
+	defaultPIXExpirationInterval    = time.Minute
+	defaultPIXExpirationLimit       = 200
+	defaultImageGCInterval          = 24 * time.Hour
+	defaultImageGCMaxAge            = 7 * 24 * time.Hour
+	defaultImageGCLimit             = 500
+)
+
+// ConfigureJobs - registra as tarefas executadas periodicamente
+func ConfigureJobs(s *scheduler.Scheduler) {
+
+	// PAYMENTS
+	s.Register(scheduler.Job{
+		Name:     "reconcile-payments",
+		Interval: util.ParseDurationOrDefault(database.Config.PaymentReconcileInterval, defaultPaymentReconcileInterval),
+		Run: func(ctx context.Context) error {
+			minAge := util.ParseDurationOrDefault(database.Config.PaymentReconcileMinAge, defaultPaymentReconcileMinAge)
+			report, err := service.PagamentoServiceNew(database.DB).ReconcilePayments(ctx, minAge, defaultPaymentReconcileLimit)
+			if err != nil {
+				return err
+			}
+			log.Printf("conciliação de pagamentos: %d analisados, %d atualizados, %d falhas", report.Scanned, report.Updated, report.Failed)
+			return nil
+		},
+	})
+
+	s.Register(scheduler.Job{
+		Name:     "expire-pix-payments",
+		Interval: util.ParseDurationOrDefault(database.Config.PIXExpirationInterval, defaultPIXExpirationInterval),
+		Run: func(ctx context.Context) error {
+			expired, err := service.PagamentoServiceNew(database.DB).ExpirePIXPayments(ctx, defaultPIXExpirationLimit)
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				log.Printf("expiração de PIX: %d pagamentos cancelados", expired)
+			}
+			return nil
+		},
+	})
+
+	// IMAGES
+	s.Register(scheduler.Job{
+		Name:     "gc-images",
+		Interval: util.ParseDurationOrDefault(database.Config.ImageGCInterval, defaultImageGCInterval),
+		Run: func(ctx context.Context) error {
+			maxAge := util.ParseDurationOrDefault(database.Config.ImageGCMaxAge, defaultImageGCMaxAge)
+			report, err := service.ImageServiceNew(database.DB).CollectGarbage(ctx, maxAge, defaultImageGCLimit, false)
+			if err != nil {
+				return err
+			}
+			log.Printf("coleta de imagens: %d imagens sem passeio excluídas (%s), %d arquivos órfãos removidos (%s), %d imagens sem original, %d versões sem arquivo, %d falhas",
+				report.UnusedImages, util.FormatBytes(report.UnusedBytes), report.OrphanedFiles, util.FormatBytes(report.OrphanedBytes),
+				report.MissingOriginals, report.MissingRenditions, report.Failed)
+			return nil
+		},
+	})
+}
+
+// RunCommand - executa uma tarefa sob demanda a partir da linha de comando
+func RunCommand(ctx context.Context, args []string) error {
+
+	switch args[0] {
+	case "reconcile-payments":
+		flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		minAge := flags.Duration("min-age", util.ParseDurationOrDefault(database.Config.PaymentReconcileMinAge, defaultPaymentReconcileMinAge), "idade mínima dos pagamentos analisados")
+		limit := flags.Int("limit", defaultPaymentReconcileLimit, "quantidade de pagamentos buscados por página")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		report, err := service.PagamentoServiceNew(database.DB).ReconcilePayments(ctx, *minAge, *limit)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+
+	case "expire-pix-payments":
+		flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		limit := flags.Int("limit", defaultPIXExpirationLimit, "quantidade máxima de pagamentos cancelados")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		expired, err := service.PagamentoServiceNew(database.DB).ExpirePIXPayments(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		return printJSON(map[string]int{"expired": expired})
+
+	case "gc-images":
+		flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		dryRun := flags.Bool("dry-run", false, "apenas lista o que seria removido, sem alterar banco ou armazenamento")
+		maxAge := flags.Duration("max-age", util.ParseDurationOrDefault(database.Config.ImageGCMaxAge, defaultImageGCMaxAge), "tempo mínimo sem passeio das imagens públicas excluídas")
+		limit := flags.Int("limit", defaultImageGCLimit, "quantidade máxima de imagens sem passeio excluídas")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		report, err := service.ImageServiceNew(database.DB).CollectGarbage(ctx, *maxAge, *limit, *dryRun)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+
+	case "set-commission":
+		flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		companyID := flags.Int("company-id", 0, "ID da empresa")
+		rate := flags.Float64("rate", service.DefaultCommissionRate, "percentual de comissão da plataforma")
+		sellerTokenStdin := flags.Bool("seller-token-stdin", false, "lê da entrada padrão o access token OAuth da empresa no marketplace do Mercado Pago")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *companyID < 1 {
+			return fmt.Errorf("informe --company-id")
+		}
+
+		sellerToken, err := readSellerToken(*sellerTokenStdin)
+		if err != nil {
+			return err
+		}
+
+		response, err := service.LedgerServiceNew(database.DB).SetCommission(*companyID, *rate, sellerToken)
+		if err != nil {
+			return err
+		}
+		return printJSON(response)
+
+	case "record-payout":
+		flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		companyID := flags.Int("company-id", 0, "ID da empresa")
+		amount := flags.Float64("amount", 0, "valor repassado à empresa")
+		reference := flags.String("reference", "", "identificação da transferência (ex.: ID do PIX)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *companyID < 1 {
+			return fmt.Errorf("informe --company-id")
+		}
+
+		response, err := service.LedgerServiceNew(database.DB).RecordPayout(*companyID, *amount, *reference)
+		if err != nil {
+			return err
+		}
+		return printJSON(response)
+
+	default:
+		return fmt.Errorf("comando desconhecido: %s", args[0])
+	}
+}
+
+// readSellerToken - lê o access token da empresa da entrada padrão ou de MERCADO_PAGO_SELLER_TOKEN, para que ele
+// não fique no histórico do shell nem na lista de processos
+func readSellerToken(fromStdin bool) (string, error) {
+
+	if !fromStdin {
+		return strings.TrimSpace(os.Getenv("MERCADO_PAGO_SELLER_TOKEN")), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("erro ao ler o access token da entrada padrão: %w", err)
+	}
+
+	token := strings.TrimSpace(line)
+	if token == "" {
+		return "", fmt.Errorf("access token não informado na entrada padrão")
+	}
+
+	return token, nil
+}
+
+// printJSON - imprime o resultado de um comando na saída padrão
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
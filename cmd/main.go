@@ -8,9 +8,11 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/jampa_trip/internal/service"
 	"github.com/jampa_trip/pkg/config"
 	"github.com/jampa_trip/pkg/database"
 	"github.com/jampa_trip/pkg/middleware"
+	"github.com/jampa_trip/pkg/scheduler"
 	"github.com/jampa_trip/pkg/util"
 	"github.com/jampa_trip/pkg/webserver"
 	"github.com/swaggo/swag"
@@ -66,6 +68,13 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if len(os.Args) > 1 {
+		if err := RunCommand(ctx, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	server := webserver.EchoWebServerNew().Init(webserver.EchoWebServerConfig{
 		Debug:        database.Config.Debug,
 		ReadTimeout:  database.Config.HTTPServerReadTimeout,
@@ -77,6 +86,12 @@ func main() {
 
 	ConfigureRoutes(server)
 
+	jobs := scheduler.SchedulerNew(database.RedisClient)
+	ConfigureJobs(jobs)
+	jobs.Start(ctx)
+
+	service.ImageWorkerPoolNew(database.DB).Start(ctx)
+
 	log.Printf("📚 Documentação da API disponível em: http://localhost%s/docs/", database.Config.HTTPServerPort)
 
 	go func() {
@@ -24,6 +24,13 @@ func ConfigureRoutes(e *echo.Echo) {
 	e.POST("/jampa-trip/api/v1/companies", handler.CompanyHandler{}.Create)
 	e.POST("/jampa-trip/api/v1/clients", handler.ClientHandler{}.Create)
 
+	// MEDIA
+	e.GET("/jampa-trip/api/v1/media/*", handler.MediaHandler{}.Serve)
+	e.HEAD("/jampa-trip/api/v1/media/*", handler.MediaHandler{}.Serve)
+
+	// WEBHOOKS
+	e.POST("/jampa-trip/api/v1/webhooks/mercadopago", handler.WebhookHandler{}.MercadoPago)
+
 	// PROTECTED GROUP – all routes below require JWT authentication
 	protected := e.Group("/jampa-trip/api/v1")
 	protected.Use(middleware.JWTMiddleware())
@@ -32,6 +39,16 @@ func ConfigureRoutes(e *echo.Echo) {
 	protected.PATCH("/companies/:id", handler.CompanyHandler{}.Update)
 	protected.GET("/companies", handler.CompanyHandler{}.List)
 	protected.GET("/companies/:id", handler.CompanyHandler{}.Get)
+	protected.GET("/companies/me/commission", handler.CompanyHandler{}.GetCommission)
+	protected.GET("/companies/me/balance", handler.LedgerHandler{}.GetBalance)
+	protected.GET("/companies/me/statements", handler.LedgerHandler{}.GetStatement)
+	protected.GET("/companies/me/statements/:period", handler.LedgerHandler{}.GetStatement)
+	protected.GET("/companies/me/invoices", handler.PaymentHandler{}.ListInvoices)
+	protected.GET("/companies/me/disputes", handler.DisputeHandler{}.List)
+	protected.GET("/companies/me/disputes/:id", handler.DisputeHandler{}.Get)
+	protected.POST("/companies/me/disputes/:id/evidence", handler.DisputeHandler{}.UploadEvidence)
+	protected.GET("/companies/me/notifications", handler.NotificationHandler{}.List)
+	protected.PUT("/companies/me/notifications/:id/read", handler.NotificationHandler{}.MarkAsRead)
 
 	// CLIENTS
 	protected.PATCH("/clients/:id", handler.ClientHandler{}.Update)
@@ -39,20 +56,30 @@ func ConfigureRoutes(e *echo.Echo) {
 	protected.GET("/clients/:id", handler.ClientHandler{}.Get)
 
 	// CARDS
-	protected.POST("/clients/:customer_id/cards", handler.CardHandler{}.Create)
-	protected.GET("/clients/:customer_id/cards", handler.CardHandler{}.List)
-	protected.GET("/clients/:customer_id/cards/:card_id", handler.CardHandler{}.Get)
-	protected.PUT("/clients/:customer_id/cards/:card_id", handler.CardHandler{}.Update)
-	protected.DELETE("/clients/:customer_id/cards/:card_id", handler.CardHandler{}.Delete)
+	protected.POST("/clients/me/cards", handler.CardHandler{}.Create)
+	protected.GET("/clients/me/cards", handler.CardHandler{}.List)
+	protected.GET("/clients/me/cards/:card_id", handler.CardHandler{}.Get)
+	protected.PUT("/clients/me/cards/:card_id", handler.CardHandler{}.Update)
+	protected.PUT("/clients/me/cards/:card_id/default", handler.CardHandler{}.SetDefault)
+	protected.DELETE("/clients/me/cards/:card_id", handler.CardHandler{}.Delete)
 
 	// PAYMENT METHODS
 	protected.POST("/payments/credit-card", handler.PaymentHandler{}.CreateCreditCardPayment)
 	protected.POST("/payments/debit-card", handler.PaymentHandler{}.CreateDebitCardPayment)
 	protected.POST("/payments/pix", handler.PaymentHandler{}.CreatePIXPayment)
+	protected.POST("/payments/boleto", handler.PaymentHandler{}.CreateBoletoPayment)
 	protected.GET("/payments", handler.PaymentHandler{}.List)
+	protected.GET("/payments/installments", handler.PaymentHandler{}.ListInstallments)
 	protected.GET("/payments/:id", handler.PaymentHandler{}.Get)
+	protected.GET("/payments/:id/receipt.pdf", handler.PaymentHandler{}.Receipt)
 	protected.PUT("/payments/:id", handler.PaymentHandler{}.Update)
 
+	// CHECKOUT
+	protected.POST("/checkout", handler.CheckoutHandler{}.Checkout)
+	protected.POST("/checkout/:order_id/capture", handler.CheckoutHandler{}.Capture)
+	protected.POST("/checkout/:order_id/cancel", handler.CheckoutHandler{}.Cancel)
+	protected.POST("/checkout/:order_id/refund", handler.CheckoutHandler{}.Refund)
+
 	// TOURS
 	protected.POST("/tours", handler.TourHandler{}.Create)
 	protected.GET("/tours", handler.TourHandler{}.List)
@@ -63,6 +90,7 @@ func ConfigureRoutes(e *echo.Echo) {
 	// IMAGE UPLOAD
 	protected.POST("/upload/images", handler.ImageHandler{}.UploadImages)
 	protected.GET("/upload/images", handler.ImageHandler{}.ListImages)
+	protected.GET("/upload/images/quota", handler.ImageHandler{}.GetQuota)
 	protected.DELETE("/upload/images/:id", handler.ImageHandler{}.DeleteImage)
 	protected.PUT("/upload/images/:id", handler.ImageHandler{}.UpdateImage)
 	protected.POST("/upload/images/reorder", handler.ImageHandler{}.ReorderImages)
@@ -20,10 +20,13 @@ CREATE TABLE IF NOT EXISTS companies (
     cnpj VARCHAR(255) NOT NULL UNIQUE,
     phone VARCHAR(255) NOT NULL,
     address VARCHAR(255) NOT NULL,
+    plan VARCHAR(30) NOT NULL DEFAULT 'basico',
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+COMMENT ON COLUMN companies.plan IS 'Plano contratado; define a cota de armazenamento de imagens via IMAGE_QUOTA_PLANS';
+
 -- =============================================================================
 -- INDEXES FOR COMPANIES
 -- =============================================================================
@@ -44,6 +47,7 @@ CREATE TABLE clients (
     cpf VARCHAR(14) UNIQUE NOT NULL,
     phone VARCHAR(15) NOT NULL,
     birth_date DATE NOT NULL,
+    mercado_pago_customer_id VARCHAR(255) UNIQUE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -70,7 +74,6 @@ CREATE TABLE IF NOT EXISTS tours (
     arrival_time VARCHAR(10),
     max_people INTEGER DEFAULT 1,
     description TEXT,
-    images TEXT[],
     price DECIMAL(10,2) DEFAULT 0,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW(),
@@ -98,6 +101,7 @@ CREATE TABLE IF NOT EXISTS images (
     original_name VARCHAR(255),
     url VARCHAR(500) NOT NULL,
     thumbnail_url VARCHAR(500),
+    thumbnail_size INTEGER NOT NULL DEFAULT 0,
     size INTEGER NOT NULL,
     width INTEGER,
     height INTEGER,
@@ -107,7 +111,20 @@ CREATE TABLE IF NOT EXISTS images (
     is_primary BOOLEAN DEFAULT FALSE,
     sort_order INTEGER DEFAULT 0,
     uploaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    captured_at TIMESTAMP,
+    content_hash CHAR(64),
+    status VARCHAR(20) NOT NULL DEFAULT 'ready',
+    visibility VARCHAR(10) NOT NULL DEFAULT 'public',
+    blurhash VARCHAR(100) NOT NULL DEFAULT '',
+    dominant_color VARCHAR(7) NOT NULL DEFAULT '',
+    focal_x REAL,
+    focal_y REAL,
+    crop_x INTEGER,
+    crop_y INTEGER,
+    crop_width INTEGER,
+    crop_height INTEGER,
+    unattached_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 -- =============================================================================
@@ -121,6 +138,19 @@ CREATE INDEX IF NOT EXISTS idx_images_uploaded_at ON images(uploaded_at);
 CREATE INDEX IF NOT EXISTS idx_images_is_primary ON images(is_primary);
 CREATE INDEX IF NOT EXISTS idx_images_sort_order ON images(sort_order);
 
+COMMENT ON COLUMN images.url IS 'Chave da imagem original no armazenamento (ex.: images/1/arquivo.jpg); a URL pública é montada com PUBLIC_MEDIA_BASE_URL';
+COMMENT ON COLUMN images.thumbnail_url IS 'Chave do thumbnail no armazenamento';
+COMMENT ON COLUMN images.thumbnail_size IS 'Tamanho do thumbnail em bytes, somado ao uso de armazenamento';
+COMMENT ON COLUMN images.content_hash IS 'SHA-256 do arquivo enviado; uploads idênticos do mesmo usuário reaproveitam os arquivos já gravados';
+COMMENT ON COLUMN images.blurhash IS 'BlurHash da imagem, calculado no processamento, para o placeholder exibido enquanto ela carrega';
+COMMENT ON COLUMN images.dominant_color IS 'Cor predominante da imagem (#rrggbb), calculada no processamento';
+COMMENT ON COLUMN images.focal_x IS 'Ponto focal em fração da largura do original (0 a 1); o thumbnail é o quadrado do recorte centrado nele';
+COMMENT ON COLUMN images.crop_x IS 'Recorte aplicado ao thumbnail e às versões, em pixels do original; nulo quando a imagem é usada inteira';
+COMMENT ON COLUMN images.unattached_at IS 'Desde quando a imagem está sem passeio, mantido por trigger; a coleta exclui as imagens públicas sem passeio há mais de IMAGE_GC_MAX_AGE';
+COMMENT ON COLUMN images.status IS 'Situação do processamento: processing (aguardando thumbnail e versões), ready ou failed';
+COMMENT ON COLUMN images.visibility IS 'public (servida pela rota de mídia) ou private (apenas por URL assinada com validade)';
+COMMENT ON COLUMN images.captured_at IS 'Data da captura lida do EXIF (DateTimeOriginal) antes da remoção dos metadados, em UTC';
+
 -- =============================================================================
 -- COMPOSITE INDEXES FOR IMAGES
 -- =============================================================================
@@ -128,6 +158,58 @@ CREATE INDEX IF NOT EXISTS idx_images_sort_order ON images(sort_order);
 CREATE INDEX IF NOT EXISTS idx_images_user_tour ON images(user_id, tour_id);
 CREATE INDEX IF NOT EXISTS idx_images_user_uploaded ON images(user_id, uploaded_at);
 CREATE INDEX IF NOT EXISTS idx_images_tour_sort ON images(tour_id, sort_order);
+CREATE INDEX IF NOT EXISTS idx_images_user_content_hash ON images(user_id, content_hash);
+CREATE INDEX IF NOT EXISTS idx_images_url ON images(url);
+CREATE INDEX IF NOT EXISTS idx_images_thumbnail_url ON images(thumbnail_url);
+CREATE INDEX IF NOT EXISTS idx_images_unused ON images(unattached_at) WHERE tour_id IS NULL AND visibility = 'public';
+
+-- =============================================================================
+-- IMAGE RENDITIONS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS image_renditions (
+    id SERIAL PRIMARY KEY,
+    image_id INTEGER NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    size INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_image_renditions_width_format UNIQUE (image_id, width, format)
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_renditions_image_id ON image_renditions(image_id);
+CREATE INDEX IF NOT EXISTS idx_image_renditions_storage_key ON image_renditions(storage_key);
+
+COMMENT ON TABLE image_renditions IS 'Versões redimensionadas das imagens usadas no srcset';
+COMMENT ON COLUMN image_renditions.format IS 'Formato da versão: jpg, png ou webp';
+COMMENT ON COLUMN image_renditions.storage_key IS 'Chave da versão no armazenamento';
+
+-- =============================================================================
+-- IMAGE JOBS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS image_jobs (
+    id SERIAL PRIMARY KEY,
+    image_id INTEGER NOT NULL UNIQUE REFERENCES images(id) ON DELETE CASCADE,
+    source_key VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    run_after TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_image_jobs_status CHECK (status IN ('pending', 'running', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_jobs_pending ON image_jobs(run_after) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_image_jobs_running ON image_jobs(locked_at) WHERE status = 'running';
+
+COMMENT ON TABLE image_jobs IS 'Fila de processamento das imagens enviadas; jobs concluídos são removidos';
+COMMENT ON COLUMN image_jobs.source_key IS 'Chave do arquivo enviado, guardado fora de images/ até o processamento';
+COMMENT ON COLUMN image_jobs.locked_at IS 'Início da execução; jobs em execução há mais de 10 minutos são reassumidos por outro worker';
 
 -- =============================================================================
 -- CONSTRAINTS FOR IMAGES
@@ -138,6 +220,8 @@ ALTER TABLE images ADD CONSTRAINT chk_images_width CHECK (width > 0);
 ALTER TABLE images ADD CONSTRAINT chk_images_height CHECK (height > 0);
 ALTER TABLE images ADD CONSTRAINT chk_images_format CHECK (format IN ('jpg', 'jpeg', 'png', 'gif', 'webp'));
 ALTER TABLE images ADD CONSTRAINT chk_images_sort_order CHECK (sort_order >= 0);
+ALTER TABLE images ADD CONSTRAINT chk_images_status CHECK (status IN ('processing', 'ready', 'failed'));
+ALTER TABLE images ADD CONSTRAINT chk_images_visibility CHECK (visibility IN ('public', 'private'));
 
 -- =============================================================================
 -- FUNCTIONS FOR IMAGES
@@ -169,6 +253,20 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql;
 
+-- Function to track since when an image has no tour, including when the tour is deleted (ON DELETE SET NULL)
+CREATE OR REPLACE FUNCTION track_images_unattached_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.tour_id IS NOT NULL THEN
+        NEW.unattached_at = NULL;
+    ELSIF TG_OP = 'INSERT' OR OLD.tour_id IS NOT NULL THEN
+        NEW.unattached_at = CURRENT_TIMESTAMP;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
 -- =============================================================================
 -- TRIGGERS FOR IMAGES
 -- =============================================================================
@@ -185,6 +283,12 @@ CREATE TRIGGER trigger_ensure_single_primary_image
     FOR EACH ROW
     EXECUTE FUNCTION ensure_single_primary_image();
 
+-- Trigger to track since when an image has no tour
+CREATE TRIGGER trigger_track_images_unattached_at
+    BEFORE INSERT OR UPDATE OF tour_id ON images
+    FOR EACH ROW
+    EXECUTE FUNCTION track_images_unattached_at();
+
 -- =============================================================================
 -- VIEWS FOR IMAGES
 -- =============================================================================
@@ -202,7 +306,17 @@ SELECT
     COUNT(CASE WHEN format = 'jpg' OR format = 'jpeg' THEN 1 END) as jpg_count,
     COUNT(CASE WHEN format = 'png' THEN 1 END) as png_count,
     COUNT(CASE WHEN format = 'gif' THEN 1 END) as gif_count,
-    COUNT(CASE WHEN format = 'webp' THEN 1 END) as webp_count
+    COUNT(CASE WHEN format = 'webp' THEN 1 END) as webp_count,
+    -- original, thumbnail e versões; arquivos compartilhados por imagens duplicadas são contados uma única vez
+    (SELECT COALESCE(SUM(d.size), 0) FROM (
+        SELECT f.storage_key, MAX(f.size) AS size FROM (
+            SELECT s.url AS storage_key, s.size FROM images s WHERE s.user_id = images.user_id
+            UNION ALL
+            SELECT s.thumbnail_url, s.thumbnail_size FROM images s WHERE s.user_id = images.user_id AND s.thumbnail_url <> ''
+            UNION ALL
+            SELECT r.storage_key, r.size FROM image_renditions r INNER JOIN images s ON s.id = r.image_id WHERE s.user_id = images.user_id
+        ) f GROUP BY f.storage_key
+    ) d) as storage_used
 FROM images
 GROUP BY user_id;
 
@@ -218,6 +332,233 @@ LEFT JOIN tours t ON i.tour_id = t.id
 LEFT JOIN companies c ON t.company_id = c.id
 WHERE i.tour_id IS NOT NULL;
 
+-- =============================================================================
+-- PAGAMENTOS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS pagamentos (
+    id SERIAL PRIMARY KEY,
+    cliente_id INTEGER NOT NULL,
+    empresa_id INTEGER NOT NULL,
+    mercado_pago_order_id VARCHAR(255),
+    mercado_pago_payment_id VARCHAR(255),
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    status_detail VARCHAR(255),
+    valor DECIMAL(10,2) NOT NULL,
+    moeda VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    metodo_pagamento VARCHAR(50) NOT NULL,
+    descricao TEXT,
+    numero_parcelas INTEGER DEFAULT 1,
+    token_cartao VARCHAR(255),
+    chave_pix VARCHAR(255),
+    qr_code TEXT,
+    pix_end_to_end_id VARCHAR(64),
+    codigo_barras VARCHAR(255),
+    linha_digitavel VARCHAR(255),
+    boleto_url TEXT,
+    last_four_digits VARCHAR(4),
+    first_six_digits VARCHAR(6),
+    payment_method_id VARCHAR(50),
+    issuer_id VARCHAR(50),
+    cardholder_name VARCHAR(255),
+    captured BOOLEAN DEFAULT FALSE,
+    transaction_amount_refunded DECIMAL(10,2) DEFAULT 0,
+    taxa_plataforma DECIMAL(10,2) DEFAULT 0,
+    split_marketplace BOOLEAN DEFAULT FALSE,
+    momento_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    momento_atualizacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    momento_aprovacao TIMESTAMP,
+    momento_cancelamento TIMESTAMP,
+    momento_autorizacao TIMESTAMP,
+    momento_captura TIMESTAMP,
+    expira_em TIMESTAMP,
+    FOREIGN KEY (cliente_id) REFERENCES clients(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    FOREIGN KEY (empresa_id) REFERENCES companies(id) ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+-- =============================================================================
+-- INDEXES FOR PAGAMENTOS
+-- =============================================================================
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pagamentos_mercado_pago_order_id ON pagamentos(mercado_pago_order_id) WHERE mercado_pago_order_id <> '';
+CREATE INDEX IF NOT EXISTS idx_pagamentos_mercado_pago_payment_id ON pagamentos(mercado_pago_payment_id);
+CREATE INDEX IF NOT EXISTS idx_pagamentos_cliente_id ON pagamentos(cliente_id);
+CREATE INDEX IF NOT EXISTS idx_pagamentos_empresa_id ON pagamentos(empresa_id);
+CREATE INDEX IF NOT EXISTS idx_pagamentos_status_criacao ON pagamentos(status, momento_criacao);
+CREATE INDEX IF NOT EXISTS idx_pagamentos_empresa_aprovacao ON pagamentos(empresa_id, momento_aprovacao) WHERE momento_aprovacao IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pagamentos_expira_em ON pagamentos(expira_em) WHERE expira_em IS NOT NULL;
+
+COMMENT ON COLUMN pagamentos.expira_em IS 'Data limite para pagamento (PIX e boleto); PIX expirado é cancelado e a reserva liberada';
+COMMENT ON COLUMN pagamentos.pix_end_to_end_id IS 'Identificador end-to-end do PIX no Banco Central, exibido no recibo';
+COMMENT ON COLUMN pagamentos.linha_digitavel IS 'Linha digitável do boleto; aprovação chega pelo webhook após a compensação';
+COMMENT ON COLUMN pagamentos.taxa_plataforma IS 'Comissão da plataforma (application_fee) calculada na criação do pagamento';
+
+-- =============================================================================
+-- RESERVAS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS reservas (
+    id SERIAL PRIMARY KEY,
+    cliente_id INTEGER NOT NULL REFERENCES clients(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    empresa_id INTEGER NOT NULL REFERENCES companies(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    tour_id INTEGER NOT NULL REFERENCES tours(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    pagamento_id INTEGER REFERENCES pagamentos(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pendente' CHECK (status IN ('pendente', 'confirmada', 'cancelada', 'concluida')),
+    data_reserva TIMESTAMP NOT NULL,
+    data_passeio TIMESTAMP NOT NULL,
+    quantidade_pessoas INTEGER NOT NULL DEFAULT 1 CHECK (quantidade_pessoas > 0),
+    valor_total DECIMAL(10,2) NOT NULL,
+    observacoes TEXT,
+    momento_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    momento_atualizacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    momento_cancelamento TIMESTAMP
+);
+
+-- =============================================================================
+-- INDEXES FOR RESERVAS
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_reservas_cliente_id ON reservas(cliente_id);
+CREATE INDEX IF NOT EXISTS idx_reservas_empresa_id ON reservas(empresa_id);
+CREATE INDEX IF NOT EXISTS idx_reservas_tour_status ON reservas(tour_id, status);
+CREATE INDEX IF NOT EXISTS idx_reservas_pagamento_id ON reservas(pagamento_id);
+
+COMMENT ON COLUMN reservas.tour_id IS 'Passeio reservado; o valor cobrado é o preço do passeio multiplicado pela quantidade de pessoas';
+
+-- =============================================================================
+-- COMPANY COMMISSIONS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS company_commissions (
+    company_id INTEGER PRIMARY KEY REFERENCES companies(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    rate DECIMAL(5,2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    mercado_pago_seller_token TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE company_commissions IS 'Comissão da plataforma por empresa; empresas sem registro usam PLATFORM_COMMISSION_RATE';
+COMMENT ON COLUMN company_commissions.mercado_pago_seller_token IS 'Access token OAuth da empresa no marketplace, cifrado com AES-256-GCM (prefixo enc:v1:)';
+
+-- =============================================================================
+-- LEDGER ENTRIES TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id SERIAL PRIMARY KEY,
+    pagamento_id INTEGER REFERENCES pagamentos(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('sale', 'refund', 'chargeback', 'chargeback_won', 'split_settlement', 'payout')),
+    description VARCHAR(255),
+    gross_amount DECIMAL(10,2) NOT NULL,
+    fee_amount DECIMAL(10,2) NOT NULL,
+    net_amount DECIMAL(10,2) NOT NULL,
+    commission_rate DECIMAL(5,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- =============================================================================
+-- INDEXES FOR LEDGER ENTRIES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_company_id ON ledger_entries(company_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_created_at ON ledger_entries(created_at);
+
+-- cada reembolso parcial gera um estorno próprio; os demais lançamentos são únicos por pagamento
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_pagamento_id_type_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_pagamento_type ON ledger_entries(pagamento_id, type) WHERE type <> 'refund';
+
+COMMENT ON TABLE ledger_entries IS 'Lançamentos por pagamento com valor bruto, comissão da plataforma e valor líquido da empresa';
+
+-- =============================================================================
+-- LEDGER POSTINGS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id SERIAL PRIMARY KEY,
+    ledger_entry_id INTEGER NOT NULL REFERENCES ledger_entries(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    account VARCHAR(50) NOT NULL CHECK (account IN ('cash', 'company_payable', 'platform_revenue')),
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- =============================================================================
+-- INDEXES FOR LEDGER POSTINGS
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry ON ledger_postings(ledger_entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_company_account ON ledger_postings(company_id, account, created_at);
+
+COMMENT ON TABLE ledger_postings IS 'Partidas dobradas dos lançamentos: a soma dos débitos é igual à soma dos créditos de cada lançamento';
+
+-- =============================================================================
+-- DISPUTES TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id SERIAL PRIMARY KEY,
+    pagamento_id INTEGER NOT NULL REFERENCES pagamentos(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    mercado_pago_chargeback_id VARCHAR(255) UNIQUE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('chargeback', 'mediation')),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'evidence_submitted', 'won', 'lost')),
+    reason VARCHAR(255),
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    documentation_required BOOLEAN NOT NULL DEFAULT TRUE,
+    documentation_status VARCHAR(50),
+    evidence_deadline TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS dispute_evidences (
+    id SERIAL PRIMARY KEY,
+    dispute_id INTEGER NOT NULL REFERENCES disputes(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('tour_manifest', 'check_in', 'image', 'other')),
+    description VARCHAR(500),
+    file_name VARCHAR(255) NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    file_size BIGINT NOT NULL,
+    submitted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- =============================================================================
+-- INDEXES FOR DISPUTES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_disputes_pagamento_id ON disputes(pagamento_id);
+CREATE INDEX IF NOT EXISTS idx_disputes_company_status ON disputes(company_id, status);
+CREATE INDEX IF NOT EXISTS idx_dispute_evidences_dispute_id ON dispute_evidences(dispute_id);
+
+COMMENT ON TABLE disputes IS 'Chargebacks e mediações abertos pelos clientes, alimentados pelos webhooks do Mercado Pago';
+COMMENT ON COLUMN disputes.evidence_deadline IS 'Prazo para a empresa enviar evidências; após ele o envio é recusado';
+COMMENT ON COLUMN dispute_evidences.file_path IS 'Chave do arquivo no armazenamento (prefixo disputes/, que a rota de mídia não serve)';
+
+-- =============================================================================
+-- NOTIFICATIONS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT,
+    reference_id INTEGER,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_company_created ON notifications(company_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(company_id) WHERE read_at IS NULL;
+
 -- =============================================================================
 -- FEEDBACKS TABLE
 -- =============================================================================
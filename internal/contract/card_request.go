@@ -14,6 +14,7 @@ type CreateCartaoRequest struct {
 	IssuerID        string            `json:"issuer_id,omitempty"`
 	Cardholder      CardholderRequest `json:"cardholder" validate:"required"`
 	Metadata        map[string]string `json:"metadata,omitempty"`
+	Default         bool              `json:"default,omitempty"`
 }
 
 // CardholderRequest - representa o portador do cartão
@@ -59,7 +60,7 @@ func (r *CreateCartaoRequest) Validate() error {
 func (r *UpdateCartaoRequest) Validate() error {
 
 	if r.Cardholder.Name == "" && r.Cardholder.Identification.Type == "" &&
-		r.Cardholder.Identification.Number == "" && len(r.Metadata) == 0 {
+		r.Cardholder.Identification.Number == "" && len(r.Metadata) == 0 && !r.Default {
 		return util.WrapError("pelo menos um campo deve ser fornecido para atualização", nil, http.StatusUnprocessableEntity)
 	}
 
@@ -15,6 +15,7 @@ type CartaoResponse struct {
 	DateCreated     string                  `json:"date_created"`
 	DateLastUpdated string                  `json:"date_last_updated"`
 	Metadata        map[string]string       `json:"metadata,omitempty"`
+	IsDefault       bool                    `json:"is_default"`
 }
 
 // SecurityCodeInfo - representa informações do código de segurança
@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// CheckoutRequest - representa a requisição de checkout de um carrinho de reservas via order do Mercado Pago
+type CheckoutRequest struct {
+	ClienteID       int          `json:"-"`
+	ReservaIDs      []int        `json:"reserva_ids"`
+	PaymentMethodID string       `json:"payment_method_id"`
+	PaymentType     string       `json:"payment_type"`
+	Token           string       `json:"token"`
+	Installments    int          `json:"installments"`
+	ManualCapture   bool         `json:"manual_capture"`
+	Description     string       `json:"description"`
+	Payer           PayerRequest `json:"payer"`
+}
+
+// Validate - valida os campos da requisição
+func (r *CheckoutRequest) Validate() error {
+	paymentMethodRules := []validation.Rule{validation.In("visa", "master", "amex", "elo", "hipercard", "cabal", "naranja", "tarshop")}
+	tokenRules := []validation.Rule{validation.Length(1, 500)}
+	if r.PaymentType == "credit_card" || r.PaymentType == "debit_card" {
+		paymentMethodRules = append([]validation.Rule{validation.Required}, paymentMethodRules...)
+		tokenRules = append([]validation.Rule{validation.Required}, tokenRules...)
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.ReservaIDs, validation.Required, validation.Length(1, 20), validation.Each(validation.Min(1))),
+		validation.Field(&r.PaymentType, validation.Required, validation.In("credit_card", "debit_card", "pix")),
+		validation.Field(&r.PaymentMethodID, paymentMethodRules...),
+		validation.Field(&r.Token, tokenRules...),
+		validation.Field(&r.Installments, validation.Min(0), validation.Max(12)),
+		validation.Field(&r.Description, validation.Length(0, 500)),
+	)
+	if err == nil {
+		// campos aninhados são validados na própria struct; o ValidateStruct só encontra os campos diretos
+		err = validation.ValidateStruct(&r.Payer, validation.Field(&r.Payer.Email, validation.Required))
+	}
+	if err != nil {
+		return util.WrapError(util.FormatarErroValidacao(err).Error(), err, http.StatusUnprocessableEntity)
+	}
+
+	if r.ManualCapture && r.PaymentType != "credit_card" {
+		return util.WrapError("captura manual disponível apenas para cartão de crédito", nil, http.StatusUnprocessableEntity)
+	}
+
+	return nil
+}
+
+// OrderOperationRequest - representa a requisição de captura, cancelamento ou reembolso de uma order
+type OrderOperationRequest struct {
+	OrderID  string
+	UserID   int
+	UserType string
+}
+
+// Validate - valida os campos da requisição
+func (r *OrderOperationRequest) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.OrderID, validation.Required, validation.Length(1, 255)),
+	)
+	if err != nil {
+		return util.WrapError(util.FormatarErroValidacao(err).Error(), err, http.StatusUnprocessableEntity)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package contract
+
+// CheckoutResponse - representa a resposta do checkout via order do Mercado Pago
+type CheckoutResponse struct {
+	OrderID      string                 `json:"order_id"`
+	OrderStatus  string                 `json:"order_status"`
+	Pagamento    PaymentResponse        `json:"pagamento"`
+	Items        []CheckoutItemResponse `json:"items"`
+	Message      string                 `json:"message"`
+	QRCode       string                 `json:"qr_code,omitempty"`
+	QRCodeBase64 string                 `json:"qr_code_base64,omitempty"`
+	TicketURL    string                 `json:"ticket_url,omitempty"`
+}
+
+// CheckoutItemResponse - representa uma reserva do carrinho
+type CheckoutItemResponse struct {
+	ReservaID         int     `json:"reserva_id"`
+	TourID            int     `json:"tour_id"`
+	TourName          string  `json:"tour_name"`
+	QuantidadePessoas int     `json:"quantidade_pessoas"`
+	PrecoUnitario     float64 `json:"preco_unitario"`
+	Valor             float64 `json:"valor"`
+}
+
+// OrderOperationResponse - representa a resposta de captura, cancelamento ou reembolso de uma order
+type OrderOperationResponse struct {
+	OrderID     string          `json:"order_id"`
+	OrderStatus string          `json:"order_status"`
+	Pagamento   PaymentResponse `json:"pagamento"`
+	Message     string          `json:"message"`
+}
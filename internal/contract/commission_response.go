@@ -0,0 +1,9 @@
+package contract
+
+// CompanyCommissionResponse - representa a comissão da plataforma aplicada a uma empresa
+type CompanyCommissionResponse struct {
+	CompanyID int     `json:"company_id"`
+	Rate      float64 `json:"rate"`
+	IsDefault bool    `json:"is_default"`
+	HasSplit  bool    `json:"has_split"`
+}
@@ -0,0 +1,43 @@
+package contract
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// ListDisputesRequest - representa os filtros da listagem de disputas
+type ListDisputesRequest struct {
+	Status string `query:"status"`
+}
+
+// Validate - valida os campos da requisição
+func (r *ListDisputesRequest) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Status, validation.In("open", "evidence_submitted", "won", "lost")),
+	)
+	if err != nil {
+		return util.WrapError(util.FormatarErroValidacao(err).Error(), err, 422)
+	}
+	return nil
+}
+
+// UploadDisputeEvidenceRequest - representa os metadados das evidências enviadas em multipart
+type UploadDisputeEvidenceRequest struct {
+	DisputeID   int
+	CompanyID   int
+	Kind        string
+	Description string
+}
+
+// Validate - valida os campos da requisição
+func (r *UploadDisputeEvidenceRequest) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.DisputeID, validation.Required, validation.Min(1)),
+		validation.Field(&r.Kind, validation.Required, validation.In("tour_manifest", "check_in", "image", "other")),
+		validation.Field(&r.Description, validation.Length(0, 500)),
+	)
+	if err != nil {
+		return util.WrapError(util.FormatarErroValidacao(err).Error(), err, 422)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package contract
+
+import "time"
+
+// DisputeResponse - representa uma disputa de pagamento
+type DisputeResponse struct {
+	ID                      int                       `json:"id"`
+	PagamentoID             int                       `json:"pagamento_id"`
+	MercadoPagoChargebackID string                    `json:"mercado_pago_chargeback_id,omitempty"`
+	Type                    string                    `json:"type"`
+	Status                  string                    `json:"status"`
+	Reason                  string                    `json:"reason,omitempty"`
+	Amount                  float64                   `json:"amount"`
+	Currency                string                    `json:"currency"`
+	DocumentationRequired   bool                      `json:"documentation_required"`
+	DocumentationStatus     string                    `json:"documentation_status,omitempty"`
+	EvidenceDeadline        *time.Time                `json:"evidence_deadline,omitempty"`
+	AcceptsEvidence         bool                      `json:"accepts_evidence"`
+	CreatedAt               time.Time                 `json:"created_at"`
+	UpdatedAt               time.Time                 `json:"updated_at"`
+	ResolvedAt              *time.Time                `json:"resolved_at,omitempty"`
+	Evidences               []DisputeEvidenceResponse `json:"evidences,omitempty"`
+}
+
+// DisputeEvidenceResponse - representa um arquivo de evidência de uma disputa
+type DisputeEvidenceResponse struct {
+	ID          int        `json:"id"`
+	Kind        string     `json:"kind"`
+	Description string     `json:"description,omitempty"`
+	FileName    string     `json:"file_name"`
+	MimeType    string     `json:"mime_type"`
+	FileSize    int64      `json:"file_size"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ListDisputesResponse - representa a listagem de disputas da empresa
+type ListDisputesResponse struct {
+	Disputes []DisputeResponse `json:"disputes"`
+	Total    int               `json:"total"`
+}
+
+// UploadDisputeEvidenceResponse - representa a resposta do envio de evidências
+type UploadDisputeEvidenceResponse struct {
+	Dispute DisputeResponse `json:"dispute"`
+	Message string          `json:"message"`
+}
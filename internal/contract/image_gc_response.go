@@ -0,0 +1,35 @@
+package contract
+
+import "time"
+
+// Ações registradas no relatório da coleta de imagens órfãs
+const (
+	ImageGCDeleteImage     = "delete_image"
+	ImageGCDeleteFile      = "delete_file"
+	ImageGCMarkFailed      = "mark_failed"
+	ImageGCDeleteRendition = "delete_rendition"
+)
+
+// ImageGCReport - representa o relatório de uma execução da coleta de imagens e arquivos órfãos
+type ImageGCReport struct {
+	StartedAt         time.Time     `json:"started_at"`
+	FinishedAt        time.Time     `json:"finished_at"`
+	DryRun            bool          `json:"dry_run"`
+	UnusedImages      int           `json:"unused_images"`
+	UnusedBytes       int64         `json:"unused_bytes"`
+	OrphanedFiles     int           `json:"orphaned_files"`
+	OrphanedBytes     int64         `json:"orphaned_bytes"`
+	MissingOriginals  int           `json:"missing_originals"`
+	MissingRenditions int           `json:"missing_renditions"`
+	Failed            int           `json:"failed"`
+	Items             []ImageGCItem `json:"items"`
+}
+
+// ImageGCItem - representa uma imagem, arquivo ou versão tratada (ou que seria tratada, na simulação) pela coleta
+type ImageGCItem struct {
+	Action  string `json:"action"`
+	ImageID *int   `json:"image_id,omitempty"`
+	Key     string `json:"key"`
+	Size    int64  `json:"size,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
@@ -2,6 +2,7 @@ package contract
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -10,6 +11,7 @@ import (
 type UploadImagesRequest struct {
 	TourID      *int   `json:"tour_id,omitempty"`
 	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
 }
 
 func (r *UploadImagesRequest) Validate() error {
@@ -21,16 +23,27 @@ func (r *UploadImagesRequest) Validate() error {
 		return errors.New("descrição deve ter no máximo 500 caracteres")
 	}
 
+	if err := validateImageVisibility(r.Visibility); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// UpdateImageRequest - request para atualizar metadados de uma imagem
+// UpdateImageRequest - request para atualizar metadados de uma imagem e, com focal_point ou crop, regenerar
+// o thumbnail e as versões
 type UpdateImageRequest struct {
-	Description string `json:"description,omitempty"`
-	AltText     string `json:"alt_text,omitempty"`
-	IsPrimary   *bool  `json:"is_primary,omitempty"`
+	Description string           `json:"description,omitempty"`
+	AltText     string           `json:"alt_text,omitempty"`
+	IsPrimary   *bool            `json:"is_primary,omitempty"`
+	Visibility  string           `json:"visibility,omitempty"`
+	FocalPoint  *ImageFocalPoint `json:"focal_point,omitempty"`
+	Crop        *ImageCrop       `json:"crop,omitempty"`
 }
 
+// MinImageCropSize - menor largura e altura aceitas no recorte, em pixels
+const MinImageCropSize = 16
+
 func (r *UpdateImageRequest) Validate() error {
 	if len(r.Description) > 500 {
 		return errors.New("descrição deve ter no máximo 500 caracteres")
@@ -40,6 +53,23 @@ func (r *UpdateImageRequest) Validate() error {
 		return errors.New("alt_text deve ter no máximo 255 caracteres")
 	}
 
+	if err := validateImageVisibility(r.Visibility); err != nil {
+		return err
+	}
+
+	if r.FocalPoint != nil && (r.FocalPoint.X < 0 || r.FocalPoint.X > 1 || r.FocalPoint.Y < 0 || r.FocalPoint.Y > 1) {
+		return errors.New("focal_point deve ter x e y entre 0 e 1")
+	}
+
+	if r.Crop != nil {
+		if r.Crop.X < 0 || r.Crop.Y < 0 {
+			return errors.New("crop deve ter x e y maiores ou iguais a zero")
+		}
+		if r.Crop.Width < MinImageCropSize || r.Crop.Height < MinImageCropSize {
+			return fmt.Errorf("crop deve ter largura e altura de pelo menos %d pixels", MinImageCropSize)
+		}
+	}
+
 	return nil
 }
 
@@ -179,3 +209,11 @@ func (r *ListImagesRequest) ParseQueryParams(tourIDStr, format, pageStr, limitSt
 
 	return nil
 }
+
+// validateImageVisibility - aceita a visibilidade vazia (padrão ou sem alteração), public ou private
+func validateImageVisibility(visibility string) error {
+	if visibility != "" && visibility != "public" && visibility != "private" {
+		return errors.New("visibility deve ser public ou private")
+	}
+	return nil
+}
@@ -4,29 +4,82 @@ import "time"
 
 // ImageResponse - resposta com dados de uma imagem
 type ImageResponse struct {
-	ID           int       `json:"id"`
-	Filename     string    `json:"filename"`
-	OriginalName string    `json:"original_name,omitempty"`
-	URL          string    `json:"url"`
-	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
-	Size         int       `json:"size"`
-	Width        int       `json:"width"`
-	Height       int       `json:"height"`
-	Format       string    `json:"format"`
-	Description  string    `json:"description,omitempty"`
-	AltText      string    `json:"alt_text,omitempty"`
-	IsPrimary    bool      `json:"is_primary"`
-	TourID       *int      `json:"tour_id,omitempty"`
-	UploadedAt   time.Time `json:"uploaded_at"`
-	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+	ID            int              `json:"id"`
+	Filename      string           `json:"filename"`
+	OriginalName  string           `json:"original_name,omitempty"`
+	URL           string           `json:"url"`
+	ThumbnailURL  string           `json:"thumbnail_url,omitempty"`
+	Size          int              `json:"size"`
+	Width         int              `json:"width"`
+	Height        int              `json:"height"`
+	Format        string           `json:"format"`
+	Description   string           `json:"description,omitempty"`
+	AltText       string           `json:"alt_text,omitempty"`
+	IsPrimary     bool             `json:"is_primary"`
+	TourID        *int             `json:"tour_id,omitempty"`
+	UploadedAt    time.Time        `json:"uploaded_at"`
+	UpdatedAt     time.Time        `json:"updated_at,omitempty"`
+	CapturedAt    *time.Time       `json:"captured_at,omitempty"`
+	DuplicateOf   *int             `json:"duplicate_of,omitempty"`
+	Status        string           `json:"status"`
+	Visibility    string           `json:"visibility"`
+	URLExpiresAt  *time.Time       `json:"url_expires_at,omitempty"`
+	BlurHash      string           `json:"blurhash,omitempty"`
+	DominantColor string           `json:"dominant_color,omitempty"`
+	FocalPoint    *ImageFocalPoint `json:"focal_point,omitempty"`
+	Crop          *ImageCrop       `json:"crop,omitempty"`
+
+	Renditions []ImageRenditionResponse `json:"renditions,omitempty"`
+	SrcSet     map[string]string        `json:"srcset,omitempty"`
+}
+
+// ImageFocalPoint - ponto de interesse da imagem, em coordenadas relativas (0 a 1) à imagem original; os clientes
+// devem mantê-lo visível ao recortar a imagem
+type ImageFocalPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ImageCrop - recorte aplicado ao thumbnail e às versões, em pixels da imagem original
+type ImageCrop struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ImageRenditionResponse - versão redimensionada de uma imagem
+type ImageRenditionResponse struct {
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Format   string `json:"format"`
+	MimeType string `json:"mime_type"`
+	Size     int    `json:"size"`
 }
 
 // UploadImagesResponse - resposta do upload de imagens
 type UploadImagesResponse struct {
-	Success       bool            `json:"success"`
-	Images        []ImageResponse `json:"images"`
-	TotalUploaded int             `json:"total_uploaded"`
-	TotalSize     int64           `json:"total_size"`
+	Success         bool                `json:"success"`
+	Images          []ImageResponse     `json:"images"`
+	Results         []ImageUploadResult `json:"results"`
+	TotalUploaded   int                 `json:"total_uploaded"`
+	TotalFailed     int                 `json:"total_failed"`
+	TotalDuplicates int                 `json:"total_duplicates"`
+	TotalProcessing int                 `json:"total_processing"`
+	TotalSize       int64               `json:"total_size"`
+}
+
+// ImageQuotaResponse - uso e limite de armazenamento de imagens da empresa
+type ImageQuotaResponse struct {
+	Success        bool    `json:"success"`
+	Plan           string  `json:"plan,omitempty"`
+	Unlimited      bool    `json:"unlimited"`
+	QuotaBytes     int64   `json:"quota_bytes"`
+	UsedBytes      int64   `json:"used_bytes"`
+	AvailableBytes int64   `json:"available_bytes"`
+	UsagePercent   float64 `json:"usage_percent"`
+	TotalImages    int     `json:"total_images"`
 }
 
 // ListImagesResponse - resposta da listagem de imagens
@@ -82,11 +135,13 @@ type BatchDeleteError struct {
 	Error   string `json:"error"`
 }
 
-// ImageUploadResult - resultado individual do upload
+// ImageUploadResult - resultado individual de cada arquivo do upload, na ordem em que foram enviados
 type ImageUploadResult struct {
-	Image   ImageResponse `json:"image"`
-	Success bool          `json:"success"`
-	Error   string        `json:"error,omitempty"`
+	OriginalName string `json:"original_name"`
+	Success      bool   `json:"success"`
+	ImageID      *int   `json:"image_id,omitempty"`
+	Code         string `json:"code,omitempty"`
+	Message      string `json:"message,omitempty"`
 }
 
 // UploadSummary - resumo do upload
@@ -0,0 +1,29 @@
+package contract
+
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// ListInvoicesRequest - representa os filtros da listagem de faturas da empresa
+type ListInvoicesRequest struct {
+	CompanyID int
+	Period    string
+	Page      int
+	Limit     int
+}
+
+// Validate - valida os campos da requisição
+func (r *ListInvoicesRequest) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Period, validation.Required, validation.Match(regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)).Error("utilize o formato AAAA-MM")),
+		validation.Field(&r.Page, validation.Min(1)),
+		validation.Field(&r.Limit, validation.Min(1), validation.Max(100)),
+	)
+	if err != nil {
+		return util.WrapError(util.FormatarErroValidacao(err).Error(), err, 422)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package contract
+
+import "time"
+
+// InvoiceResponse - representa um pagamento aprovado da empresa com o link do recibo em PDF
+type InvoiceResponse struct {
+	Number               string     `json:"number"`
+	PagamentoID          int        `json:"pagamento_id"`
+	MercadoPagoPaymentID string     `json:"mercado_pago_payment_id"`
+	ClienteNome          string     `json:"cliente_nome"`
+	Status               string     `json:"status"`
+	MetodoPagamento      string     `json:"metodo_pagamento"`
+	NumeroParcelas       int        `json:"numero_parcelas"`
+	Valor                float64    `json:"valor"`
+	ValorEstornado       float64    `json:"valor_estornado"`
+	TaxaPlataforma       float64    `json:"taxa_plataforma"`
+	ValorLiquido         float64    `json:"valor_liquido"`
+	Moeda                string     `json:"moeda"`
+	AprovadoEm           *time.Time `json:"aprovado_em"`
+	ReceiptURL           string     `json:"receipt_url"`
+}
+
+// ListInvoicesResponse - representa a listagem de faturas da empresa no período
+type ListInvoicesResponse struct {
+	Period     string             `json:"period"`
+	Invoices   []InvoiceResponse  `json:"invoices"`
+	Pagination PaginationResponse `json:"pagination"`
+}
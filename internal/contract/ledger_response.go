@@ -0,0 +1,43 @@
+package contract
+
+import "time"
+
+// CompanyBalanceResponse - representa o saldo da empresa no razão da plataforma
+type CompanyBalanceResponse struct {
+	CompanyID   int     `json:"company_id"`
+	Currency    string  `json:"currency"`
+	GrossSales  float64 `json:"gross_sales"`
+	Fees        float64 `json:"fees"`
+	Refunds     float64 `json:"refunds"`
+	Chargebacks float64 `json:"chargebacks"`
+	Settled     float64 `json:"settled"`
+	Balance     float64 `json:"balance"`
+}
+
+// StatementResponse - representa o extrato mensal de repasses de uma empresa
+type StatementResponse struct {
+	CompanyID      int                    `json:"company_id"`
+	Period         string                 `json:"period"`
+	PeriodStart    time.Time              `json:"period_start"`
+	PeriodEnd      time.Time              `json:"period_end"`
+	Currency       string                 `json:"currency"`
+	OpeningBalance float64                `json:"opening_balance"`
+	ClosingBalance float64                `json:"closing_balance"`
+	TotalGross     float64                `json:"total_gross"`
+	TotalFees      float64                `json:"total_fees"`
+	TotalNet       float64                `json:"total_net"`
+	Transactions   []StatementTransaction `json:"transactions"`
+}
+
+// StatementTransaction - representa um lançamento do extrato
+type StatementTransaction struct {
+	EntryID        int       `json:"entry_id"`
+	PagamentoID    *int      `json:"pagamento_id,omitempty"`
+	Date           time.Time `json:"date"`
+	Type           string    `json:"type"`
+	Description    string    `json:"description,omitempty"`
+	GrossAmount    float64   `json:"gross_amount"`
+	FeeAmount      float64   `json:"fee_amount"`
+	NetAmount      float64   `json:"net_amount"`
+	CommissionRate float64   `json:"commission_rate"`
+}
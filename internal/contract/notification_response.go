@@ -0,0 +1,25 @@
+package contract
+
+import "time"
+
+// NotificationResponse - representa uma notificação da empresa
+type NotificationResponse struct {
+	ID          int        `json:"id"`
+	Type        string     `json:"type"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	ReferenceID *int       `json:"reference_id,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ListNotificationsResponse - representa a listagem de notificações da empresa
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int                    `json:"total"`
+}
+
+// MarkNotificationReadResponse - representa a resposta da leitura de uma notificação
+type MarkNotificationReadResponse struct {
+	Message string `json:"message"`
+}
@@ -1,6 +1,11 @@
 package contract
 
-import validation "github.com/go-ozzo/ozzo-validation"
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/jampa_trip/pkg/util"
+)
 
 // CreatePagamentoRequest - representa a requisição para criar um pagamento
 type CreatePagamentoRequest struct {
@@ -8,7 +13,7 @@ type CreatePagamentoRequest struct {
 	EmpresaID       int     `json:"empresa_id" validate:"required,min=1"`
 	Valor           float64 `json:"valor" validate:"required,min=0.01"`
 	Moeda           string  `json:"moeda" validate:"required,oneof=BRL USD EUR ARS CLP COP MXN PEN UYU"`
-	MetodoPagamento string  `json:"metodo_pagamento" validate:"required,oneof=credit_card debit_card pix bolbradesco"`
+	MetodoPagamento string  `json:"metodo_pagamento" validate:"required,oneof=credit_card debit_card pix boleto"`
 	Descricao       string  `json:"descricao" validate:"max=500"`
 	NumeroParcelas  int     `json:"numero_parcelas" validate:"min=1,max=12"`
 	TokenCartao     string  `json:"token_cartao"`
@@ -22,7 +27,7 @@ func (r *CreatePagamentoRequest) Validate() error {
 		validation.Field(&r.EmpresaID, validation.Required, validation.Min(1)),
 		validation.Field(&r.Valor, validation.Required, validation.Min(0.01)),
 		validation.Field(&r.Moeda, validation.Required, validation.In("BRL", "USD", "EUR", "ARS", "CLP", "COP", "MXN", "PEN", "UYU")),
-		validation.Field(&r.MetodoPagamento, validation.Required, validation.In("credit_card", "debit_card", "pix", "bolbradesco")),
+		validation.Field(&r.MetodoPagamento, validation.Required, validation.In("credit_card", "debit_card", "pix", "boleto")),
 		validation.Field(&r.Descricao, validation.Length(0, 500)),
 		validation.Field(&r.NumeroParcelas, validation.Min(1), validation.Max(12)),
 	)
@@ -62,7 +67,7 @@ type ListPagamentoRequest struct {
 	ClienteID       int    `json:"cliente_id" validate:"omitempty,min=1"`
 	EmpresaID       int    `json:"empresa_id" validate:"omitempty,min=1"`
 	Status          string `json:"status" validate:"omitempty,oneof=pending approved authorized in_process in_mediation rejected cancelled refunded charged_back"`
-	MetodoPagamento string `json:"metodo_pagamento" validate:"omitempty,oneof=credit_card debit_card pix bolbradesco"`
+	MetodoPagamento string `json:"metodo_pagamento" validate:"omitempty,oneof=credit_card debit_card pix boleto"`
 	Page            int    `json:"page" validate:"min=1"`
 	Limit           int    `json:"limit" validate:"min=1,max=100"`
 }
@@ -73,7 +78,7 @@ func (r *ListPagamentoRequest) Validate() error {
 		validation.Field(&r.ClienteID, validation.Min(1)),
 		validation.Field(&r.EmpresaID, validation.Min(1)),
 		validation.Field(&r.Status, validation.In("pending", "approved", "authorized", "in_process", "in_mediation", "rejected", "cancelled", "refunded", "charged_back")),
-		validation.Field(&r.MetodoPagamento, validation.In("credit_card", "debit_card", "pix", "bolbradesco")),
+		validation.Field(&r.MetodoPagamento, validation.In("credit_card", "debit_card", "pix", "boleto")),
 		validation.Field(&r.Page, validation.Min(1)),
 		validation.Field(&r.Limit, validation.Min(1), validation.Max(100)),
 	)
@@ -81,10 +86,11 @@ func (r *ListPagamentoRequest) Validate() error {
 
 // CreateCreditCardPaymentRequest - representa a requisição para criar pagamento com cartão de crédito
 type CreateCreditCardPaymentRequest struct {
-	ClienteID         int          `json:"cliente_id" validate:"required,min=1"`
-	EmpresaID         int          `json:"empresa_id" validate:"required,min=1"`
+	ClienteID         int          `json:"-"`
+	ReservaID         int          `json:"reserva_id" validate:"required,min=1"`
 	Token             string       `json:"token" validate:"required,min=1"`
-	TransactionAmount float64      `json:"transaction_amount" validate:"required,min=0.01"`
+	CardID            string       `json:"card_id"`
+	UseDefaultCard    bool         `json:"use_default_card"`
 	Installments      int          `json:"installments" validate:"required,min=1,max=12"`
 	PaymentMethodID   string       `json:"payment_method_id" validate:"required"`
 	IssuerID          string       `json:"issuer_id"`
@@ -94,27 +100,47 @@ type CreateCreditCardPaymentRequest struct {
 	Capture           bool         `json:"capture"`
 }
 
-// Validate - valida os campos da requisição
+// Validate - valida os campos da requisição; com cartão salvo o token é o do CVV e o pagador é o customer do cliente
 func (r *CreateCreditCardPaymentRequest) Validate() error {
-	return validation.ValidateStruct(r,
-		validation.Field(&r.ClienteID, validation.Required, validation.Min(1)),
-		validation.Field(&r.EmpresaID, validation.Required, validation.Min(1)),
+	paymentMethodRules := []validation.Rule{validation.In("visa", "master", "amex", "elo", "hipercard", "cabal", "naranja", "tarshop")}
+	emailRules := []validation.Rule{}
+	if !r.UsesSavedCard() {
+		paymentMethodRules = append([]validation.Rule{validation.Required}, paymentMethodRules...)
+		emailRules = append(emailRules, validation.Required)
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.ReservaID, validation.Required, validation.Min(1)),
 		validation.Field(&r.Token, validation.Required, validation.Length(1, 500)),
-		validation.Field(&r.TransactionAmount, validation.Required, validation.Min(0.01)),
+		validation.Field(&r.CardID, validation.Length(0, 255)),
 		validation.Field(&r.Installments, validation.Required, validation.Min(1), validation.Max(12)),
-		validation.Field(&r.PaymentMethodID, validation.Required, validation.In("visa", "master", "amex", "elo", "hipercard", "cabal", "naranja", "tarshop")),
+		validation.Field(&r.PaymentMethodID, paymentMethodRules...),
 		validation.Field(&r.Description, validation.Length(0, 500)),
-		validation.Field(&r.Payer.Email, validation.Required),
+	)
+	if err != nil {
+		return err
+	}
+
+	// campos aninhados são validados na própria struct; o ValidateStruct só encontra os campos diretos
+	if err := validation.ValidateStruct(&r.Payer, validation.Field(&r.Payer.Email, emailRules...)); err != nil {
+		return err
+	}
+
+	return validation.ValidateStruct(&r.Payer.Identification,
 		validation.Field(&r.Payer.Identification.Type, validation.In("CPF", "CNPJ")),
 	)
 }
 
+// UsesSavedCard - indica se o pagamento utiliza um cartão salvo do cliente
+func (r *CreateCreditCardPaymentRequest) UsesSavedCard() bool {
+	return r.CardID != "" || r.UseDefaultCard
+}
+
 // CreateDebitCardPaymentRequest - representa a requisição para criar pagamento com cartão de débito
 type CreateDebitCardPaymentRequest struct {
-	ClienteID         int          `json:"cliente_id" validate:"required,min=1"`
-	EmpresaID         int          `json:"empresa_id" validate:"required,min=1"`
+	ClienteID         int          `json:"-"`
+	ReservaID         int          `json:"reserva_id" validate:"required,min=1"`
 	Token             string       `json:"token" validate:"required,min=1"`
-	TransactionAmount float64      `json:"transaction_amount" validate:"required,min=0.01"`
 	PaymentMethodID   string       `json:"payment_method_id" validate:"required"`
 	IssuerID          string       `json:"issuer_id"`
 	Description       string       `json:"description" validate:"max=500"`
@@ -125,10 +151,8 @@ type CreateDebitCardPaymentRequest struct {
 // Validate - valida os campos da requisição
 func (r *CreateDebitCardPaymentRequest) Validate() error {
 	return validation.ValidateStruct(r,
-		validation.Field(&r.ClienteID, validation.Required, validation.Min(1)),
-		validation.Field(&r.EmpresaID, validation.Required, validation.Min(1)),
+		validation.Field(&r.ReservaID, validation.Required, validation.Min(1)),
 		validation.Field(&r.Token, validation.Required, validation.Length(1, 500)),
-		validation.Field(&r.TransactionAmount, validation.Required, validation.Min(0.01)),
 		validation.Field(&r.PaymentMethodID, validation.Required, validation.In("visa", "master", "amex", "elo", "hipercard", "cabal", "naranja", "tarshop")),
 		validation.Field(&r.Description, validation.Length(0, 500)),
 		validation.Field(&r.Payer.Email, validation.Required),
@@ -138,9 +162,8 @@ func (r *CreateDebitCardPaymentRequest) Validate() error {
 
 // CreatePIXPaymentRequest - representa a requisição para criar pagamento com PIX
 type CreatePIXPaymentRequest struct {
-	ClienteID         int          `json:"cliente_id" validate:"required,min=1"`
-	EmpresaID         int          `json:"empresa_id" validate:"required,min=1"`
-	TransactionAmount float64      `json:"transaction_amount" validate:"required,min=0.01"`
+	ClienteID         int          `json:"-"`
+	ReservaID         int          `json:"reserva_id" validate:"required,min=1"`
 	Description       string       `json:"description" validate:"max=500"`
 	Payer             PayerRequest `json:"payer" validate:"required"`
 	ExternalReference string       `json:"external_reference"`
@@ -149,14 +172,73 @@ type CreatePIXPaymentRequest struct {
 // Validate - valida os campos da requisição
 func (r *CreatePIXPaymentRequest) Validate() error {
 	return validation.ValidateStruct(r,
-		validation.Field(&r.ClienteID, validation.Required, validation.Min(1)),
-		validation.Field(&r.EmpresaID, validation.Required, validation.Min(1)),
-		validation.Field(&r.TransactionAmount, validation.Required, validation.Min(0.01)),
+		validation.Field(&r.ReservaID, validation.Required, validation.Min(1)),
 		validation.Field(&r.Description, validation.Length(0, 500)),
 		validation.Field(&r.Payer.Email, validation.Required),
 	)
 }
 
+// CreateBoletoPaymentRequest - representa a requisição para criar pagamento com boleto
+type CreateBoletoPaymentRequest struct {
+	ClienteID         int                `json:"-"`
+	ReservaID         int                `json:"reserva_id" validate:"required,min=1"`
+	PaymentMethodID   string             `json:"payment_method_id" validate:"omitempty,oneof=bolbradesco pec"`
+	Description       string             `json:"description" validate:"max=500"`
+	Payer             BoletoPayerRequest `json:"payer" validate:"required"`
+	ExternalReference string             `json:"external_reference"`
+}
+
+// BoletoPayerRequest - representa o pagador do boleto, que exige CPF e endereço
+type BoletoPayerRequest struct {
+	Email     string               `json:"email" validate:"required,email"`
+	FirstName string               `json:"first_name" validate:"required"`
+	LastName  string               `json:"last_name" validate:"required"`
+	CPF       string               `json:"cpf" validate:"required"`
+	Address   BoletoAddressRequest `json:"address" validate:"required"`
+}
+
+// BoletoAddressRequest - representa o endereço do pagador do boleto
+type BoletoAddressRequest struct {
+	ZipCode      string `json:"zip_code" validate:"required"`
+	StreetName   string `json:"street_name" validate:"required"`
+	StreetNumber string `json:"street_number" validate:"required"`
+	Neighborhood string `json:"neighborhood" validate:"required"`
+	City         string `json:"city" validate:"required"`
+	FederalUnit  string `json:"federal_unit" validate:"required,len=2"`
+}
+
+// Validate - valida os campos da requisição
+func (r *CreateBoletoPaymentRequest) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.ReservaID, validation.Required, validation.Min(1)),
+		validation.Field(&r.PaymentMethodID, validation.In("bolbradesco", "pec")),
+		validation.Field(&r.Description, validation.Length(0, 500)),
+		validation.Field(&r.Payer.Email, validation.Required, validation.Match(util.COD_03)),
+		validation.Field(&r.Payer.FirstName, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Payer.LastName, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Payer.CPF, validation.Required, validation.Match(util.COD_04)),
+		validation.Field(&r.Payer.Address.ZipCode, validation.Required, validation.Match(util.COD_04)),
+		validation.Field(&r.Payer.Address.StreetName, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Payer.Address.StreetNumber, validation.Required, validation.Length(1, 20)),
+		validation.Field(&r.Payer.Address.Neighborhood, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Payer.Address.City, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Payer.Address.FederalUnit, validation.Required, validation.Length(2, 2)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := util.ValidaCPF(r.Payer.CPF); err != nil {
+		return err
+	}
+
+	if len(util.SomenteDigitos(r.Payer.Address.ZipCode)) != 8 {
+		return util.WrapError("CEP deve ter 8 dígitos", nil, http.StatusUnprocessableEntity)
+	}
+
+	return nil
+}
+
 // ListPaymentsRequest - representa a requisição para buscar pagamentos
 type ListPaymentsRequest struct {
 	ExternalReference string `json:"external_reference"`
@@ -172,7 +254,7 @@ type ListPaymentsRequest struct {
 func (r *ListPaymentsRequest) Validate() error {
 	return validation.ValidateStruct(r,
 		validation.Field(&r.Status, validation.In("pending", "approved", "authorized", "in_process", "in_mediation", "rejected", "cancelled", "refunded", "charged_back")),
-		validation.Field(&r.PaymentMethod, validation.In("credit_card", "debit_card", "pix", "bolbradesco")),
+		validation.Field(&r.PaymentMethod, validation.In("credit_card", "debit_card", "pix", "boleto")),
 		validation.Field(&r.Offset, validation.Min(0)),
 		validation.Field(&r.Limit, validation.Min(1), validation.Max(100)),
 	)
@@ -219,3 +301,21 @@ type IdentificationRequest struct {
 	Type   string `json:"type" validate:"required,oneof=CPF CNPJ"`
 	Number string `json:"number" validate:"required"`
 }
+
+// ListInstallmentsRequest - representa a requisição de simulação de parcelamento
+type ListInstallmentsRequest struct {
+	Amount float64 `query:"amount"`
+	Bin    string  `query:"bin"`
+}
+
+// Validate - valida os campos da requisição
+func (r *ListInstallmentsRequest) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Amount, validation.Required, validation.Min(0.01)),
+		validation.Field(&r.Bin, validation.Required, validation.Length(6, 8), validation.Match(util.COD_02)),
+	)
+	if err != nil {
+		return util.WrapError(util.FormatarErroValidacao(err).Error(), err, http.StatusUnprocessableEntity)
+	}
+	return nil
+}
@@ -25,10 +25,16 @@ type PaymentResponse struct {
 	CardholderName            string  `json:"cardholder_name,omitempty"`
 	Captured                  bool    `json:"captured"`
 	TransactionAmountRefunded float64 `json:"transaction_amount_refunded"`
+	TaxaPlataforma            float64 `json:"taxa_plataforma"`
 
-	TokenCartao string `json:"token_cartao,omitempty"`
-	ChavePIX    string `json:"chave_pix,omitempty"`
-	QRCode      string `json:"qr_code,omitempty"`
+	TokenCartao   string `json:"token_cartao,omitempty"`
+	ChavePIX      string `json:"chave_pix,omitempty"`
+	QRCode        string `json:"qr_code,omitempty"`
+	PixEndToEndID string `json:"pix_end_to_end_id,omitempty"`
+
+	CodigoBarras   string `json:"codigo_barras,omitempty"`
+	LinhaDigitavel string `json:"linha_digitavel,omitempty"`
+	BoletoURL      string `json:"boleto_url,omitempty"`
 
 	MomentoCriacao      time.Time  `json:"momento_criacao"`
 	MomentoAtualizacao  time.Time  `json:"momento_atualizacao"`
@@ -36,6 +42,7 @@ type PaymentResponse struct {
 	MomentoCancelamento *time.Time `json:"momento_cancelamento,omitempty"`
 	MomentoAutorizacao  *time.Time `json:"momento_autorizacao,omitempty"`
 	MomentoCaptura      *time.Time `json:"momento_captura,omitempty"`
+	ExpiraEm            *time.Time `json:"expira_em,omitempty"`
 
 	StatusDisplay          string `json:"status_display"`
 	MetodoPagamentoDisplay string `json:"metodo_pagamento_display"`
@@ -62,6 +69,16 @@ type CreatePIXPaymentResponse struct {
 	TicketURL    string          `json:"ticket_url,omitempty"`
 }
 
+// CreateBoletoPaymentResponse - representa a resposta da criação de pagamento com boleto
+type CreateBoletoPaymentResponse struct {
+	Pagamento      PaymentResponse `json:"pagamento"`
+	Message        string          `json:"message"`
+	CodigoBarras   string          `json:"codigo_barras"`
+	LinhaDigitavel string          `json:"linha_digitavel"`
+	BoletoURL      string          `json:"boleto_url"`
+	ExpiraEm       *time.Time      `json:"expira_em,omitempty"`
+}
+
 // ListPaymentsResponse - representa a resposta da busca de pagamentos
 type ListPaymentsResponse struct {
 	Pagamentos []PaymentResponse `json:"pagamentos"`
@@ -81,3 +98,31 @@ type UpdatePaymentResponse struct {
 	Pagamento PaymentResponse `json:"pagamento"`
 	Message   string          `json:"message"`
 }
+
+// ListInstallmentsResponse - representa a resposta da simulação de parcelamento
+type ListInstallmentsResponse struct {
+	Amount  float64                     `json:"amount"`
+	Bin     string                      `json:"bin"`
+	Options []InstallmentOptionResponse `json:"options"`
+}
+
+// InstallmentOptionResponse - representa os planos de parcelamento de um meio de pagamento
+type InstallmentOptionResponse struct {
+	PaymentMethodID   string                    `json:"payment_method_id"`
+	PaymentMethodName string                    `json:"payment_method_name,omitempty"`
+	PaymentTypeID     string                    `json:"payment_type_id"`
+	Thumbnail         string                    `json:"thumbnail,omitempty"`
+	IssuerID          string                    `json:"issuer_id,omitempty"`
+	IssuerName        string                    `json:"issuer_name,omitempty"`
+	Plans             []InstallmentPlanResponse `json:"plans"`
+}
+
+// InstallmentPlanResponse - representa um plano de parcelamento
+type InstallmentPlanResponse struct {
+	Installments       int     `json:"installments"`
+	InstallmentAmount  float64 `json:"installment_amount"`
+	TotalAmount        float64 `json:"total_amount"`
+	InterestRate       float64 `json:"interest_rate"`
+	InterestFree       bool    `json:"interest_free"`
+	RecommendedMessage string  `json:"recommended_message"`
+}
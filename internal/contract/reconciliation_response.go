@@ -0,0 +1,24 @@
+package contract
+
+import "time"
+
+// PaymentReconciliationReport - representa o relatório de uma execução da conciliação de pagamentos
+type PaymentReconciliationReport struct {
+	StartedAt     time.Time            `json:"started_at"`
+	FinishedAt    time.Time            `json:"finished_at"`
+	Scanned       int                  `json:"scanned"`
+	Updated       int                  `json:"updated"`
+	Failed        int                  `json:"failed"`
+	Discrepancies []PaymentDiscrepancy `json:"discrepancies"`
+}
+
+// PaymentDiscrepancy - representa uma divergência entre o pagamento local e o Mercado Pago
+type PaymentDiscrepancy struct {
+	PagamentoID          int    `json:"pagamento_id"`
+	MercadoPagoPaymentID string `json:"mercado_pago_payment_id"`
+	LocalStatus          string `json:"local_status"`
+	RemoteStatus         string `json:"remote_status,omitempty"`
+	LocalStatusDetail    string `json:"local_status_detail"`
+	RemoteStatusDetail   string `json:"remote_status_detail,omitempty"`
+	Error                string `json:"error,omitempty"`
+}
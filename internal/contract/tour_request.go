@@ -1,12 +1,16 @@
 package contract
 
 import (
+	"fmt"
 	"net/http"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/jampa_trip/pkg/util"
 )
 
+// MaxTourImages - quantidade máxima de imagens vinculadas a um passeio
+const MaxTourImages = 20
+
 // CreateTourRequest - objeto de request do endpoint de criação de passeio
 type CreateTourRequest struct {
 	Name          string   `json:"name"`
@@ -15,7 +19,7 @@ type CreateTourRequest struct {
 	ArrivalTime   string   `json:"arrival_time"`
 	MaxPeople     int      `json:"max_people"`
 	Description   string   `json:"description"`
-	Images        []string `json:"images"`
+	ImageIDs      []int    `json:"image_ids"`
 	Price         float64  `json:"price"`
 }
 
@@ -39,14 +43,15 @@ func (receiver CreateTourRequest) Validate() error {
 		return err
 	}
 
-	if err := util.ValidateImageURLs(receiver.Images); err != nil {
+	if err := validateTourImageIDs(receiver.ImageIDs); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// UpdateTourRequest - objeto de request do endpoint de atualização de passeio
+// UpdateTourRequest - objeto de request do endpoint de atualização de passeio. Sem image_ids as imagens do
+// passeio são mantidas; com uma lista (inclusive vazia) ela substitui as imagens vinculadas.
 type UpdateTourRequest struct {
 	ID            int      `json:"id"`
 	Name          string   `json:"name"`
@@ -55,7 +60,7 @@ type UpdateTourRequest struct {
 	ArrivalTime   string   `json:"arrival_time"`
 	MaxPeople     int      `json:"max_people"`
 	Description   string   `json:"description"`
-	Images        []string `json:"images"`
+	ImageIDs      []int    `json:"image_ids"`
 	Price         float64  `json:"price"`
 }
 
@@ -80,13 +85,33 @@ func (receiver UpdateTourRequest) Validate() error {
 		return err
 	}
 
-	if err := util.ValidateImageURLs(receiver.Images); err != nil {
+	if err := validateTourImageIDs(receiver.ImageIDs); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// validateTourImageIDs - valida os IDs das imagens do passeio: positivos, sem repetição e no máximo MaxTourImages
+func validateTourImageIDs(imageIDs []int) error {
+	if len(imageIDs) > MaxTourImages {
+		return util.WrapError(fmt.Sprintf("máximo de %d imagens por passeio", MaxTourImages), nil, http.StatusUnprocessableEntity)
+	}
+
+	seen := make(map[int]bool, len(imageIDs))
+	for _, id := range imageIDs {
+		if id <= 0 {
+			return util.WrapError("todos os image_ids devem ser números positivos", nil, http.StatusUnprocessableEntity)
+		}
+		if seen[id] {
+			return util.WrapError("image_ids não pode conter IDs duplicados", nil, http.StatusUnprocessableEntity)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
 // ListToursRequest - objeto de request do endpoint de listagem de passeios
 type ListToursRequest struct {
 	Search string `json:"search"`
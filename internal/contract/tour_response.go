@@ -2,19 +2,19 @@ package contract
 
 // TourResponse - resposta com dados do passeio
 type TourResponse struct {
-	ID            int      `json:"id"`
-	Name          string   `json:"name"`
-	Dates         []string `json:"dates"`
-	DepartureTime string   `json:"departure_time"`
-	ArrivalTime   string   `json:"arrival_time"`
-	MaxPeople     int      `json:"max_people"`
-	Description   string   `json:"description"`
-	Images        []string `json:"images"`
-	Price         float64  `json:"price"`
-	CompanyID     int      `json:"company_id"`
-	CompanyName   string   `json:"company_name"`
-	CreatedAt     string   `json:"created_at"`
-	UpdatedAt     string   `json:"updated_at"`
+	ID            int             `json:"id"`
+	Name          string          `json:"name"`
+	Dates         []string        `json:"dates"`
+	DepartureTime string          `json:"departure_time"`
+	ArrivalTime   string          `json:"arrival_time"`
+	MaxPeople     int             `json:"max_people"`
+	Description   string          `json:"description"`
+	Images        []ImageResponse `json:"images"`
+	Price         float64         `json:"price"`
+	CompanyID     int             `json:"company_id"`
+	CompanyName   string          `json:"company_name"`
+	CreatedAt     string          `json:"created_at"`
+	UpdatedAt     string          `json:"updated_at"`
 }
 
 // CreateTourResponse - resposta de criação de passeio
@@ -45,17 +45,17 @@ type GetMyToursResponse struct {
 
 // MyTourResponse - resposta com dados do passeio da empresa (inclui contagem de reservas)
 type MyTourResponse struct {
-	ID                int      `json:"id"`
-	Name              string   `json:"name"`
-	Dates             []string `json:"dates"`
-	DepartureTime     string   `json:"departure_time"`
-	ArrivalTime       string   `json:"arrival_time"`
-	MaxPeople         int      `json:"max_people"`
-	Description       string   `json:"description"`
-	Images            []string `json:"images"`
-	Price             float64  `json:"price"`
-	CreatedAt         string   `json:"created_at"`
-	ReservationsCount int      `json:"reservations_count"`
+	ID                int             `json:"id"`
+	Name              string          `json:"name"`
+	Dates             []string        `json:"dates"`
+	DepartureTime     string          `json:"departure_time"`
+	ArrivalTime       string          `json:"arrival_time"`
+	MaxPeople         int             `json:"max_people"`
+	Description       string          `json:"description"`
+	Images            []ImageResponse `json:"images"`
+	Price             float64         `json:"price"`
+	CreatedAt         string          `json:"created_at"`
+	ReservationsCount int             `json:"reservations_count"`
 }
 
 // DeleteTourResponse - resposta de exclusão de passeio
@@ -0,0 +1,21 @@
+package contract
+
+import validation "github.com/go-ozzo/ozzo-validation"
+
+// MercadoPagoWebhookRequest - representa a notificação recebida do Mercado Pago
+type MercadoPagoWebhookRequest struct {
+	ID       int64  `json:"id"`
+	LiveMode bool   `json:"live_mode"`
+	Type     string `json:"type"`
+	Action   string `json:"action"`
+	Data     struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// Validate - valida os campos da requisição
+func (r *MercadoPagoWebhookRequest) Validate() error {
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Type, validation.Required),
+	)
+}
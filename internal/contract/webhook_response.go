@@ -0,0 +1,6 @@
+package contract
+
+// WebhookResponse - representa a resposta do processamento de um webhook
+type WebhookResponse struct {
+	Message string `json:"message"`
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/jampa_trip/internal/contract"
 	"github.com/jampa_trip/internal/service"
 	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
 	"github.com/jampa_trip/pkg/util"
 	"github.com/jampa_trip/pkg/webserver"
 	"github.com/labstack/echo/v4"
@@ -13,12 +14,11 @@ import (
 
 type CardHandler struct{}
 
-// Create - cria um cartão para um cliente
+// Create - salva um cartão para o cliente autenticado
 func (h CardHandler) Create(ctx echo.Context) error {
 
-	customerID := ctx.Param("customer_id")
-	if customerID == "" {
-		return webserver.ErrorResponse(ctx, util.WrapError("customer_id é obrigatório", nil, http.StatusBadRequest))
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem acessar esta rota", nil, http.StatusForbidden))
 	}
 
 	request := &contract.CreateCartaoRequest{}
@@ -35,7 +35,7 @@ func (h CardHandler) Create(ctx echo.Context) error {
 	}
 
 	serviceCartao := service.CartaoServiceNew(database.DB)
-	response, err := serviceCartao.Create(ctx.Request().Context(), customerID, request)
+	response, err := serviceCartao.Create(ctx.Request().Context(), middleware.GetUserID(ctx), request)
 	if err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -43,15 +43,15 @@ func (h CardHandler) Create(ctx echo.Context) error {
 	return ctx.JSON(http.StatusCreated, response)
 }
 
-// List - lista os cartões de um cliente
+// List - lista os cartões do cliente autenticado
 func (h CardHandler) List(ctx echo.Context) error {
-	customerID := ctx.Param("customer_id")
-	if customerID == "" {
-		return webserver.ErrorResponse(ctx, util.WrapError("customer_id é obrigatório", nil, http.StatusBadRequest))
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem acessar esta rota", nil, http.StatusForbidden))
 	}
 
 	serviceCartao := service.CartaoServiceNew(database.DB)
-	response, err := serviceCartao.List(ctx.Request().Context(), customerID)
+	response, err := serviceCartao.List(ctx.Request().Context(), middleware.GetUserID(ctx))
 	if err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -59,11 +59,11 @@ func (h CardHandler) List(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
-// Get - obtém um cartão específico de um cliente
+// Get - obtém um cartão específico do cliente autenticado
 func (h CardHandler) Get(ctx echo.Context) error {
-	customerID := ctx.Param("customer_id")
-	if customerID == "" {
-		return webserver.ErrorResponse(ctx, util.WrapError("customer_id é obrigatório", nil, http.StatusBadRequest))
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem acessar esta rota", nil, http.StatusForbidden))
 	}
 
 	cardID := ctx.Param("card_id")
@@ -72,7 +72,7 @@ func (h CardHandler) Get(ctx echo.Context) error {
 	}
 
 	serviceCartao := service.CartaoServiceNew(database.DB)
-	response, err := serviceCartao.Get(ctx.Request().Context(), customerID, cardID)
+	response, err := serviceCartao.Get(ctx.Request().Context(), middleware.GetUserID(ctx), cardID)
 	if err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -80,11 +80,11 @@ func (h CardHandler) Get(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
-// Update - atualiza um cartão de um cliente
+// Update - atualiza um cartão do cliente autenticado
 func (h CardHandler) Update(ctx echo.Context) error {
-	customerID := ctx.Param("customer_id")
-	if customerID == "" {
-		return webserver.ErrorResponse(ctx, util.WrapError("customer_id é obrigatório", nil, http.StatusBadRequest))
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem acessar esta rota", nil, http.StatusForbidden))
 	}
 
 	cardID := ctx.Param("card_id")
@@ -106,7 +106,28 @@ func (h CardHandler) Update(ctx echo.Context) error {
 	}
 
 	serviceCartao := service.CartaoServiceNew(database.DB)
-	response, err := serviceCartao.Update(ctx.Request().Context(), customerID, cardID, request)
+	response, err := serviceCartao.Update(ctx.Request().Context(), middleware.GetUserID(ctx), cardID, request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// SetDefault - define o cartão padrão do cliente autenticado
+func (h CardHandler) SetDefault(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	cardID := ctx.Param("card_id")
+	if cardID == "" {
+		return webserver.ErrorResponse(ctx, util.WrapError("card_id é obrigatório", nil, http.StatusBadRequest))
+	}
+
+	serviceCartao := service.CartaoServiceNew(database.DB)
+	response, err := serviceCartao.SetDefault(ctx.Request().Context(), middleware.GetUserID(ctx), cardID)
 	if err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -114,11 +135,11 @@ func (h CardHandler) Update(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
-// Delete - exclui um cartão de um cliente
+// Delete - exclui um cartão do cliente autenticado
 func (h CardHandler) Delete(ctx echo.Context) error {
-	customerID := ctx.Param("customer_id")
-	if customerID == "" {
-		return webserver.ErrorResponse(ctx, util.WrapError("customer_id é obrigatório", nil, http.StatusBadRequest))
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem acessar esta rota", nil, http.StatusForbidden))
 	}
 
 	cardID := ctx.Param("card_id")
@@ -127,7 +148,7 @@ func (h CardHandler) Delete(ctx echo.Context) error {
 	}
 
 	serviceCartao := service.CartaoServiceNew(database.DB)
-	response, err := serviceCartao.Delete(ctx.Request().Context(), customerID, cardID)
+	response, err := serviceCartao.Delete(ctx.Request().Context(), middleware.GetUserID(ctx), cardID)
 	if err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
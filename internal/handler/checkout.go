@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
+	"github.com/jampa_trip/pkg/util"
+	"github.com/jampa_trip/pkg/webserver"
+	"github.com/labstack/echo/v4"
+)
+
+type CheckoutHandler struct{}
+
+// Checkout - cria uma order no Mercado Pago com as reservas do carrinho do cliente
+func (h CheckoutHandler) Checkout(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem realizar pagamentos", nil, http.StatusForbidden))
+	}
+
+	request := &contract.CheckoutRequest{}
+
+	if err := ctx.Bind(request); err != nil {
+		if erro := util.ValidateBodyType(err); erro != nil {
+			return webserver.ErrorResponse(ctx, erro)
+		}
+		return webserver.BadJSONResponse(ctx, err)
+	}
+
+	request.ClienteID = middleware.GetUserID(ctx)
+
+	if err := request.Validate(); err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.Checkout(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+// Capture - captura uma order autorizada
+func (h CheckoutHandler) Capture(ctx echo.Context) error {
+
+	request := orderOperationRequest(ctx)
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.CaptureOrder(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Cancel - cancela uma order ainda não concluída
+func (h CheckoutHandler) Cancel(ctx echo.Context) error {
+
+	request := orderOperationRequest(ctx)
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.CancelOrder(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Refund - reembolsa uma order aprovada
+func (h CheckoutHandler) Refund(ctx echo.Context) error {
+
+	request := orderOperationRequest(ctx)
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.RefundOrder(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// orderOperationRequest - monta a requisição de operação da order a partir da rota e do usuário autenticado
+func orderOperationRequest(ctx echo.Context) *contract.OrderOperationRequest {
+	return &contract.OrderOperationRequest{
+		OrderID:  ctx.Param("order_id"),
+		UserID:   middleware.GetUserID(ctx),
+		UserType: middleware.GetUserType(ctx),
+	}
+}
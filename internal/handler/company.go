@@ -8,6 +8,7 @@ import (
 	"github.com/jampa_trip/internal/model"
 	"github.com/jampa_trip/internal/service"
 	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
 	"github.com/jampa_trip/pkg/util"
 	"github.com/jampa_trip/pkg/webserver"
 	"github.com/labstack/echo/v4"
@@ -118,3 +119,19 @@ func (receiver CompanyHandler) Get(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, response)
 }
+
+// GetCommission - retorna a comissão da plataforma aplicada à empresa autenticada
+func (receiver CompanyHandler) GetCommission(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	serviceLedger := service.LedgerServiceNew(database.DB)
+	response, err := serviceLedger.GetCommission(middleware.GetUserID(ctx))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
+	"github.com/jampa_trip/pkg/util"
+	"github.com/jampa_trip/pkg/webserver"
+	"github.com/labstack/echo/v4"
+)
+
+type DisputeHandler struct{}
+
+// List - lista as disputas de pagamento da empresa autenticada
+func (h DisputeHandler) List(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	request := &contract.ListDisputesRequest{Status: ctx.QueryParam("status")}
+
+	if err := request.Validate(); err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	serviceDispute := service.DisputeServiceNew(database.DB)
+	response, err := serviceDispute.List(middleware.GetUserID(ctx), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get - obtém uma disputa da empresa autenticada com as evidências enviadas
+func (h DisputeHandler) Get(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	ID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return webserver.InvalidIDResponse(ctx, err)
+	}
+
+	serviceDispute := service.DisputeServiceNew(database.DB)
+	response, err := serviceDispute.Get(ID, middleware.GetUserID(ctx))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// UploadEvidence - recebe as evidências (manifesto do passeio, check-in, imagens) de uma disputa
+func (h DisputeHandler) UploadEvidence(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	ID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return webserver.InvalidIDResponse(ctx, err)
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return webserver.ErrorResponse(ctx, util.WrapError("Erro ao processar formulário", err, http.StatusBadRequest))
+	}
+	defer form.RemoveAll()
+
+	files := form.File["files[]"]
+	if len(files) == 0 {
+		return webserver.ErrorResponse(ctx, util.WrapError("Nenhum arquivo enviado", nil, http.StatusBadRequest))
+	}
+
+	request := &contract.UploadDisputeEvidenceRequest{
+		DisputeID: ID,
+		CompanyID: middleware.GetUserID(ctx),
+	}
+
+	if kind := form.Value["kind"]; len(kind) > 0 {
+		request.Kind = kind[0]
+	}
+
+	if description := form.Value["description"]; len(description) > 0 {
+		request.Description = description[0]
+	}
+
+	if err := request.Validate(); err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	serviceDispute := service.DisputeServiceNew(database.DB)
+	response, err := serviceDispute.UploadEvidence(ctx.Request().Context(), request, files)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, response)
+}
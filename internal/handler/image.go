@@ -50,6 +50,10 @@ func (h ImageHandler) UploadImages(ctx echo.Context) error {
 		request.Description = description[0]
 	}
 
+	if visibility := form.Value["visibility"]; len(visibility) > 0 {
+		request.Visibility = visibility[0]
+	}
+
 	if err := request.Validate(); err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -60,7 +64,7 @@ func (h ImageHandler) UploadImages(ctx echo.Context) error {
 		return webserver.ErrorResponse(ctx, err)
 	}
 
-	return ctx.JSON(http.StatusCreated, response)
+	return ctx.JSON(service.UploadStatusCode(response), response)
 }
 
 // ListImages - lista imagens do usuário
@@ -91,6 +95,25 @@ func (h ImageHandler) ListImages(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// GetQuota - retorna o uso e o limite de armazenamento de imagens da empresa
+func (h ImageHandler) GetQuota(ctx echo.Context) error {
+
+	userID := middleware.GetUserID(ctx)
+	userType := middleware.GetUserType(ctx)
+
+	if userType != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas possuem cota de armazenamento de imagens", nil, http.StatusForbidden))
+	}
+
+	imageService := service.ImageServiceNew(database.DB)
+	response, err := imageService.GetQuota(userID)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // DeleteImage - deleta uma imagem específica
 func (h ImageHandler) DeleteImage(ctx echo.Context) error {
 
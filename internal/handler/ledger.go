@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
+	"github.com/jampa_trip/pkg/util"
+	"github.com/jampa_trip/pkg/webserver"
+	"github.com/labstack/echo/v4"
+)
+
+type LedgerHandler struct{}
+
+// GetBalance - retorna o saldo a receber da empresa autenticada
+func (h LedgerHandler) GetBalance(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	serviceLedger := service.LedgerServiceNew(database.DB)
+	response, err := serviceLedger.GetBalance(middleware.GetUserID(ctx))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetStatement - retorna o extrato mensal da empresa autenticada em JSON ou CSV
+func (h LedgerHandler) GetStatement(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	period := ctx.Param("period")
+	if period == "" {
+		period = time.Now().Format("2006-01")
+	}
+
+	format := ctx.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return webserver.ErrorResponse(ctx, util.WrapError("formato inválido, utilize json ou csv", nil, http.StatusBadRequest))
+	}
+
+	serviceLedger := service.LedgerServiceNew(database.DB)
+	response, err := serviceLedger.GetStatement(middleware.GetUserID(ctx), period)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	if format == "csv" {
+		data, err := serviceLedger.StatementCSV(response)
+		if err != nil {
+			return webserver.ErrorResponse(ctx, err)
+		}
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=extrato-%s.csv", period))
+		return ctx.Blob(http.StatusOK, "text/csv; charset=utf-8", data)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
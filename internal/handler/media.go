@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/util"
+	"github.com/jampa_trip/pkg/webserver"
+	"github.com/labstack/echo/v4"
+)
+
+type MediaHandler struct{}
+
+// Serve - entrega uma imagem do armazenamento com cache HTTP (ETag e Cache-Control); imagens privadas exigem
+// URL assinada (expires e signature)
+func (h MediaHandler) Serve(ctx echo.Context) error {
+
+	key, err := url.PathUnescape(ctx.Param("*"))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, util.WrapError("arquivo não encontrado", err, http.StatusNotFound))
+	}
+
+	serviceMedia := service.MediaServiceNew(database.DB)
+	media, err := serviceMedia.Get(ctx.Request().Context(), key, ctx.QueryParam("expires"), ctx.QueryParam("signature"))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderCacheControl, media.CacheControl(time.Now()))
+	header.Set("ETag", media.ETag)
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+
+	if media.NotModified(ctx.Request().Header.Get("If-None-Match")) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
+	return ctx.Blob(http.StatusOK, media.ContentType, media.Data)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
+	"github.com/jampa_trip/pkg/util"
+	"github.com/jampa_trip/pkg/webserver"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct{}
+
+// List - lista as notificações da empresa autenticada; ?unread=true retorna apenas as não lidas
+func (h NotificationHandler) List(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	unreadOnly, _ := strconv.ParseBool(ctx.QueryParam("unread"))
+
+	serviceNotification := service.NotificationServiceNew(database.DB)
+	response, err := serviceNotification.List(middleware.GetUserID(ctx), unreadOnly)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// MarkAsRead - marca uma notificação da empresa autenticada como lida
+func (h NotificationHandler) MarkAsRead(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	ID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return webserver.InvalidIDResponse(ctx, err)
+	}
+
+	serviceNotification := service.NotificationServiceNew(database.DB)
+	response, err := serviceNotification.MarkAsRead(ID, middleware.GetUserID(ctx))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
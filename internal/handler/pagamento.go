@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jampa_trip/internal/contract"
 	"github.com/jampa_trip/internal/service"
 	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/middleware"
 	"github.com/jampa_trip/pkg/util"
 	"github.com/jampa_trip/pkg/webserver"
 	"github.com/labstack/echo/v4"
@@ -17,6 +21,10 @@ type PaymentHandler struct{}
 // CreateCreditCardPayment - cria um pagamento com cartão de crédito
 func (h PaymentHandler) CreateCreditCardPayment(ctx echo.Context) error {
 
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem realizar pagamentos", nil, http.StatusForbidden))
+	}
+
 	request := &contract.CreateCreditCardPaymentRequest{}
 
 	if err := ctx.Bind(request); err != nil {
@@ -26,6 +34,8 @@ func (h PaymentHandler) CreateCreditCardPayment(ctx echo.Context) error {
 		return webserver.BadJSONResponse(ctx, err)
 	}
 
+	request.ClienteID = middleware.GetUserID(ctx)
+
 	if err := request.Validate(); err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -42,6 +52,10 @@ func (h PaymentHandler) CreateCreditCardPayment(ctx echo.Context) error {
 // CreateDebitCardPayment - cria um pagamento com cartão de débito
 func (h PaymentHandler) CreateDebitCardPayment(ctx echo.Context) error {
 
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem realizar pagamentos", nil, http.StatusForbidden))
+	}
+
 	request := &contract.CreateDebitCardPaymentRequest{}
 
 	if err := ctx.Bind(request); err != nil {
@@ -51,6 +65,8 @@ func (h PaymentHandler) CreateDebitCardPayment(ctx echo.Context) error {
 		return webserver.BadJSONResponse(ctx, err)
 	}
 
+	request.ClienteID = middleware.GetUserID(ctx)
+
 	if err := request.Validate(); err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -67,6 +83,10 @@ func (h PaymentHandler) CreateDebitCardPayment(ctx echo.Context) error {
 // CreatePIXPayment - cria um pagamento com PIX
 func (h PaymentHandler) CreatePIXPayment(ctx echo.Context) error {
 
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem realizar pagamentos", nil, http.StatusForbidden))
+	}
+
 	request := &contract.CreatePIXPaymentRequest{}
 
 	if err := ctx.Bind(request); err != nil {
@@ -76,6 +96,8 @@ func (h PaymentHandler) CreatePIXPayment(ctx echo.Context) error {
 		return webserver.BadJSONResponse(ctx, err)
 	}
 
+	request.ClienteID = middleware.GetUserID(ctx)
+
 	if err := request.Validate(); err != nil {
 		return webserver.ErrorResponse(ctx, err)
 	}
@@ -89,6 +111,37 @@ func (h PaymentHandler) CreatePIXPayment(ctx echo.Context) error {
 	return ctx.JSON(http.StatusCreated, response)
 }
 
+// CreateBoletoPayment - cria um pagamento com boleto bancário
+func (h PaymentHandler) CreateBoletoPayment(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "client" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas clientes podem realizar pagamentos", nil, http.StatusForbidden))
+	}
+
+	request := &contract.CreateBoletoPaymentRequest{}
+
+	if err := ctx.Bind(request); err != nil {
+		if erro := util.ValidateBodyType(err); erro != nil {
+			return webserver.ErrorResponse(ctx, erro)
+		}
+		return webserver.BadJSONResponse(ctx, err)
+	}
+
+	request.ClienteID = middleware.GetUserID(ctx)
+
+	if err := request.Validate(); err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.CreateBoletoPayment(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, response)
+}
+
 // List - busca pagamentos com filtros
 func (h PaymentHandler) List(ctx echo.Context) error {
 
@@ -114,6 +167,32 @@ func (h PaymentHandler) List(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// ListInstallments - simula os planos de parcelamento para um valor e BIN de cartão
+func (h PaymentHandler) ListInstallments(ctx echo.Context) error {
+
+	amount, err := strconv.ParseFloat(ctx.QueryParam("amount"), 64)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, util.WrapError("valor informado inválido", err, http.StatusBadRequest))
+	}
+
+	request := &contract.ListInstallmentsRequest{
+		Amount: amount,
+		Bin:    ctx.QueryParam("bin"),
+	}
+
+	if err := request.Validate(); err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.ListInstallments(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // Get - obtém um pagamento por ID
 func (h PaymentHandler) Get(ctx echo.Context) error {
 
@@ -132,6 +211,52 @@ func (h PaymentHandler) Get(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// Receipt - baixa o recibo em PDF de um pagamento aprovado
+func (h PaymentHandler) Receipt(ctx echo.Context) error {
+
+	// pagamentos de order do carrinho têm ID alfanumérico no Mercado Pago
+	paymentID := strings.TrimSpace(ctx.Param("id"))
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	data, filename, err := servicePagamento.Receipt(ctx.Request().Context(), paymentID, middleware.GetUserID(ctx), middleware.GetUserType(ctx))
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s", filename))
+	return ctx.Blob(http.StatusOK, "application/pdf", data)
+}
+
+// ListInvoices - lista as faturas (pagamentos aprovados) da empresa autenticada no período
+func (h PaymentHandler) ListInvoices(ctx echo.Context) error {
+
+	if middleware.GetUserType(ctx) != "company" {
+		return webserver.ErrorResponse(ctx, util.WrapError("Apenas empresas podem acessar esta rota", nil, http.StatusForbidden))
+	}
+
+	period := ctx.QueryParam("period")
+	if period == "" {
+		period = time.Now().Format("2006-01")
+	}
+
+	page, limit := util.ParseQueryParams(ctx.QueryParam("page"), ctx.QueryParam("limit"))
+
+	request := &contract.ListInvoicesRequest{
+		CompanyID: middleware.GetUserID(ctx),
+		Period:    period,
+		Page:      page,
+		Limit:     limit,
+	}
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.ListInvoices(request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // Update - atualiza um pagamento
 func (h PaymentHandler) Update(ctx echo.Context) error {
 
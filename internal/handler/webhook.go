@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/database"
+	"github.com/jampa_trip/pkg/mercadopago"
+	"github.com/jampa_trip/pkg/util"
+	"github.com/jampa_trip/pkg/webserver"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct{}
+
+// MercadoPago - recebe as notificações de pagamento do Mercado Pago
+func (h WebhookHandler) MercadoPago(ctx echo.Context) error {
+
+	request := &contract.MercadoPagoWebhookRequest{}
+
+	if err := ctx.Bind(request); err != nil {
+		if erro := util.ValidateBodyType(err); erro != nil {
+			return webserver.ErrorResponse(ctx, erro)
+		}
+		return webserver.BadJSONResponse(ctx, err)
+	}
+
+	if request.Data.ID == "" {
+		request.Data.ID = ctx.QueryParam("data.id")
+	}
+
+	if request.Type == "" {
+		request.Type = ctx.QueryParam("type")
+	}
+
+	if err := request.Validate(); err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	secret := database.Config.MercadoPagoWebhookSecret
+	if secret == "" && database.Config.MercadoPagoEnvironment != "sandbox" {
+		// sem a chave não há como autenticar a notificação; só o sandbox aceita notificações sem assinatura
+		return webserver.ErrorResponse(ctx, util.WrapError("chave de assinatura do webhook não configurada", nil, http.StatusUnauthorized))
+	}
+
+	if secret != "" {
+		signature := ctx.Request().Header.Get("x-signature")
+		requestID := ctx.Request().Header.Get("x-request-id")
+		if err := mercadopago.ValidateWebhookSignature(secret, signature, requestID, request.Data.ID); err != nil {
+			return webserver.ErrorResponse(ctx, err)
+		}
+	}
+
+	servicePagamento := service.PagamentoServiceNew(database.DB)
+	response, err := servicePagamento.ProcessWebhook(ctx.Request().Context(), request)
+	if err != nil {
+		return webserver.ErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// CompanyCommission - representa a comissão da plataforma configurada para uma empresa
+type CompanyCommission struct {
+	CompanyID              int       `gorm:"column:company_id;primaryKey"`
+	Rate                   float64   `gorm:"column:rate;not null;type:decimal(5,2)"`
+	MercadoPagoSellerToken string    `gorm:"column:mercado_pago_seller_token"`
+	CreatedAt              time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt              time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (CompanyCommission) TableName() string {
+	return "company_commissions"
+}
+
+// HasSplit - indica se a empresa está vinculada ao marketplace e recebe o split diretamente
+func (c *CompanyCommission) HasSplit() bool {
+	return c.MercadoPagoSellerToken != ""
+}
+
+// Fee - calcula a comissão da plataforma sobre um valor, arredondada em centavos
+func (c *CompanyCommission) Fee(amount float64) float64 {
+	return RoundCents(amount * c.Rate / 100)
+}
+
+// RoundCents - arredonda um valor monetário para duas casas decimais
+func RoundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// Dispute - representa uma disputa (chargeback ou mediação) aberta sobre um pagamento
+type Dispute struct {
+	ID                      int        `gorm:"column:id;primaryKey;autoIncrement"`
+	PagamentoID             int        `gorm:"column:pagamento_id;not null;index"`
+	CompanyID               int        `gorm:"column:company_id;not null;index"`
+	MercadoPagoChargebackID *string    `gorm:"column:mercado_pago_chargeback_id;uniqueIndex"`
+	Type                    string     `gorm:"column:type;not null"`
+	Status                  string     `gorm:"column:status;not null;default:'open'"`
+	Reason                  string     `gorm:"column:reason"`
+	Amount                  float64    `gorm:"column:amount;not null;type:decimal(10,2)"`
+	Currency                string     `gorm:"column:currency;not null;default:'BRL'"`
+	DocumentationRequired   bool       `gorm:"column:documentation_required;default:true"`
+	DocumentationStatus     string     `gorm:"column:documentation_status"`
+	EvidenceDeadline        *time.Time `gorm:"column:evidence_deadline"`
+	CreatedAt               time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt               time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+	ResolvedAt              *time.Time `gorm:"column:resolved_at"`
+
+	Evidences []DisputeEvidence `gorm:"foreignKey:DisputeID;references:ID"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (Dispute) TableName() string {
+	return "disputes"
+}
+
+// DisputeType - define os tipos de disputa
+type DisputeType string
+
+const (
+	DisputeChargeback DisputeType = "chargeback"
+	DisputeMediation  DisputeType = "mediation"
+)
+
+// DisputeStatus - define os status de uma disputa
+type DisputeStatus string
+
+const (
+	DisputeOpen              DisputeStatus = "open"
+	DisputeEvidenceSubmitted DisputeStatus = "evidence_submitted"
+	DisputeWon               DisputeStatus = "won"
+	DisputeLost              DisputeStatus = "lost"
+)
+
+// IsResolved - indica se a disputa já foi decidida
+func (d *Dispute) IsResolved() bool {
+	return d.Status == string(DisputeWon) || d.Status == string(DisputeLost)
+}
+
+// AcceptsEvidence - indica se a empresa ainda pode enviar evidências
+func (d *Dispute) AcceptsEvidence(now time.Time) bool {
+	if d.IsResolved() {
+		return false
+	}
+	return d.EvidenceDeadline == nil || !now.After(*d.EvidenceDeadline)
+}
+
+// Resolve - encerra a disputa com o resultado informado
+func (d *Dispute) Resolve(status DisputeStatus, now time.Time) {
+	d.Status = string(status)
+	d.ResolvedAt = &now
+	d.UpdatedAt = now
+}
+
+// DisputeEvidence - representa um arquivo de evidência enviado pela empresa
+type DisputeEvidence struct {
+	ID          int        `gorm:"column:id;primaryKey;autoIncrement"`
+	DisputeID   int        `gorm:"column:dispute_id;not null;index"`
+	Kind        string     `gorm:"column:kind;not null"`
+	Description string     `gorm:"column:description"`
+	FileName    string     `gorm:"column:file_name;not null"`
+	FilePath    string     `gorm:"column:file_path;not null"`
+	MimeType    string     `gorm:"column:mime_type;not null"`
+	FileSize    int64      `gorm:"column:file_size;not null"`
+	SubmittedAt *time.Time `gorm:"column:submitted_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (DisputeEvidence) TableName() string {
+	return "dispute_evidences"
+}
+
+// Tipos de evidência aceitos
+const (
+	EvidenceTourManifest = "tour_manifest"
+	EvidenceCheckIn      = "check_in"
+	EvidenceImage        = "image"
+	EvidenceOther        = "other"
+)
@@ -2,29 +2,74 @@ package model
 
 import (
 	"fmt"
+	"image"
 	"strings"
 	"time"
 )
 
 // Image - representa a entidade de imagem
 type Image struct {
-	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
-	UserID       int       `gorm:"column:user_id;not null"`
-	TourID       *int      `gorm:"column:tour_id"`
-	Filename     string    `gorm:"column:filename;not null"`
-	OriginalName string    `gorm:"column:original_name"`
-	URL          string    `gorm:"column:url;not null"`
-	ThumbnailURL string    `gorm:"column:thumbnail_url"`
-	Size         int       `gorm:"column:size;not null"`
-	Width        int       `gorm:"column:width"`
-	Height       int       `gorm:"column:height"`
-	Format       string    `gorm:"column:format;not null"`
-	Description  string    `gorm:"column:description"`
-	AltText      string    `gorm:"column:alt_text"`
-	IsPrimary    bool      `gorm:"column:is_primary;default:false"`
-	SortOrder    int       `gorm:"column:sort_order;default:0"`
-	UploadedAt   time.Time `gorm:"column:uploaded_at;not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt    time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+	ID            int        `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID        int        `gorm:"column:user_id;not null"`
+	TourID        *int       `gorm:"column:tour_id"`
+	Filename      string     `gorm:"column:filename;not null"`
+	OriginalName  string     `gorm:"column:original_name"`
+	URL           string     `gorm:"column:url;not null"`
+	ThumbnailURL  string     `gorm:"column:thumbnail_url"`
+	ThumbnailSize int        `gorm:"column:thumbnail_size"`
+	Size          int        `gorm:"column:size;not null"`
+	Width         int        `gorm:"column:width"`
+	Height        int        `gorm:"column:height"`
+	Format        string     `gorm:"column:format;not null"`
+	Description   string     `gorm:"column:description"`
+	AltText       string     `gorm:"column:alt_text"`
+	IsPrimary     bool       `gorm:"column:is_primary;default:false"`
+	SortOrder     int        `gorm:"column:sort_order;default:0"`
+	UploadedAt    time.Time  `gorm:"column:uploaded_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+	CapturedAt    *time.Time `gorm:"column:captured_at"`
+	ContentHash   string     `gorm:"column:content_hash"`
+	Status        string     `gorm:"column:status;not null;default:ready"`
+	Visibility    string     `gorm:"column:visibility;not null;default:public"`
+	BlurHash      string     `gorm:"column:blurhash;not null;default:''"`
+	DominantColor string     `gorm:"column:dominant_color;not null;default:''"`
+	FocalX        *float64   `gorm:"column:focal_x"`
+	FocalY        *float64   `gorm:"column:focal_y"`
+	CropX         *int       `gorm:"column:crop_x"`
+	CropY         *int       `gorm:"column:crop_y"`
+	CropWidth     *int       `gorm:"column:crop_width"`
+	CropHeight    *int       `gorm:"column:crop_height"`
+}
+
+// Situações do processamento de uma imagem
+const (
+	ImageStatusProcessing = "processing"
+	ImageStatusReady      = "ready"
+	ImageStatusFailed     = "failed"
+)
+
+// Visibilidade de uma imagem: as privadas só são entregues por URLs assinadas com validade
+const (
+	ImageVisibilityPublic  = "public"
+	ImageVisibilityPrivate = "private"
+)
+
+// IsPrivate - indica se a imagem só pode ser acessada por URL assinada
+func (i *Image) IsPrivate() bool {
+	return i.Visibility == ImageVisibilityPrivate
+}
+
+// HasFocalPoint - indica se a empresa marcou o ponto de interesse da imagem
+func (i *Image) HasFocalPoint() bool {
+	return i.FocalX != nil && i.FocalY != nil
+}
+
+// CropRect - recorte aplicado ao thumbnail e às versões, em pixels da imagem original; sem recorte, a imagem inteira
+func (i *Image) CropRect() image.Rectangle {
+	if i.CropX == nil || i.CropY == nil || i.CropWidth == nil || i.CropHeight == nil {
+		return image.Rect(0, 0, i.Width, i.Height)
+	}
+	return image.Rect(*i.CropX, *i.CropY, *i.CropX+*i.CropWidth, *i.CropY+*i.CropHeight)
 }
 
 // TableName - especifica o nome da tabela no banco de dados
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// ImageJob - representa o processamento pendente de uma imagem enviada (original sem metadados, thumbnail e versões)
+type ImageJob struct {
+	ID        int        `gorm:"column:id;primaryKey;autoIncrement"`
+	ImageID   int        `gorm:"column:image_id;not null;uniqueIndex"`
+	SourceKey string     `gorm:"column:source_key;not null"`
+	Status    string     `gorm:"column:status;not null;default:pending"`
+	Attempts  int        `gorm:"column:attempts;not null;default:0"`
+	LastError string     `gorm:"column:last_error;type:text"`
+	RunAfter  time.Time  `gorm:"column:run_after;not null;default:CURRENT_TIMESTAMP"`
+	LockedAt  *time.Time `gorm:"column:locked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (ImageJob) TableName() string {
+	return "image_jobs"
+}
+
+// Situações de um job de processamento; jobs concluídos são removidos
+const (
+	ImageJobPending = "pending"
+	ImageJobRunning = "running"
+	ImageJobFailed  = "failed"
+)
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ImageRendition - representa uma versão redimensionada de uma imagem, usada no srcset dos clientes
+type ImageRendition struct {
+	ID         int       `gorm:"column:id;primaryKey;autoIncrement"`
+	ImageID    int       `gorm:"column:image_id;not null"`
+	Width      int       `gorm:"column:width;not null"`
+	Height     int       `gorm:"column:height;not null"`
+	Format     string    `gorm:"column:format;not null"`
+	StorageKey string    `gorm:"column:storage_key;not null"`
+	Size       int       `gorm:"column:size;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (ImageRendition) TableName() string {
+	return "image_renditions"
+}
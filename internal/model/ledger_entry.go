@@ -0,0 +1,109 @@
+package model
+
+import "time"
+
+// LedgerEntry - representa um lançamento financeiro de um pagamento (bruto, comissão e líquido)
+type LedgerEntry struct {
+	ID             int       `gorm:"column:id;primaryKey;autoIncrement"`
+	PagamentoID    *int      `gorm:"column:pagamento_id;index"`
+	CompanyID      int       `gorm:"column:company_id;not null;index"`
+	Type           string    `gorm:"column:type;not null"`
+	Description    string    `gorm:"column:description"`
+	GrossAmount    float64   `gorm:"column:gross_amount;not null;type:decimal(10,2)"`
+	FeeAmount      float64   `gorm:"column:fee_amount;not null;type:decimal(10,2)"`
+	NetAmount      float64   `gorm:"column:net_amount;not null;type:decimal(10,2)"`
+	CommissionRate float64   `gorm:"column:commission_rate;not null;type:decimal(5,2)"`
+	Currency       string    `gorm:"column:currency;not null;default:'BRL'"`
+	CreatedAt      time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+
+	Postings []LedgerPosting `gorm:"foreignKey:LedgerEntryID;references:ID"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+// LedgerEntryType - define os tipos de lançamento
+type LedgerEntryType string
+
+const (
+	LedgerEntrySale            LedgerEntryType = "sale"
+	LedgerEntryRefund          LedgerEntryType = "refund"
+	LedgerEntryChargeback      LedgerEntryType = "chargeback"
+	LedgerEntryChargebackWon   LedgerEntryType = "chargeback_won"
+	LedgerEntrySplitSettlement LedgerEntryType = "split_settlement"
+	LedgerEntryPayout          LedgerEntryType = "payout"
+)
+
+// LedgerPosting - representa uma partida (débito ou crédito) de um lançamento em uma conta
+type LedgerPosting struct {
+	ID            int       `gorm:"column:id;primaryKey;autoIncrement"`
+	LedgerEntryID int       `gorm:"column:ledger_entry_id;not null;index"`
+	CompanyID     int       `gorm:"column:company_id;not null;index"`
+	Account       string    `gorm:"column:account;not null"`
+	Direction     string    `gorm:"column:direction;not null"`
+	Amount        float64   `gorm:"column:amount;not null;type:decimal(10,2)"`
+	CreatedAt     time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (LedgerPosting) TableName() string {
+	return "ledger_postings"
+}
+
+// Contas do razão
+const (
+	AccountCash            = "cash"
+	AccountCompanyPayable  = "company_payable"
+	AccountPlatformRevenue = "platform_revenue"
+)
+
+// Direções das partidas
+const (
+	PostingDebit  = "debit"
+	PostingCredit = "credit"
+)
+
+// BuildPostings - gera as partidas dobradas do lançamento: o valor bruto entra no caixa,
+// o líquido é devido à empresa e a comissão é receita da plataforma. Valores negativos invertem as partidas.
+func (e *LedgerEntry) BuildPostings() []LedgerPosting {
+	return e.buildPostings(e.GrossAmount, e.NetAmount)
+}
+
+// BuildSplitPostings - gera as partidas de uma reversão de pagamento com split: o Mercado Pago devolve ou recupera
+// o líquido direto na conta da empresa, então só a comissão movimenta o caixa e a receita da plataforma
+func (e *LedgerEntry) BuildSplitPostings() []LedgerPosting {
+	return e.buildPostings(e.FeeAmount, 0)
+}
+
+// buildPostings - gera as partidas com os valores informados para o caixa e para o saldo devido à empresa
+func (e *LedgerEntry) buildPostings(cash, payable float64) []LedgerPosting {
+	postings := []LedgerPosting{}
+
+	add := func(account string, debitWhenPositive bool, amount float64) {
+		if amount == 0 {
+			return
+		}
+		direction := PostingCredit
+		if debitWhenPositive == (amount > 0) {
+			direction = PostingDebit
+		}
+		if amount < 0 {
+			amount = -amount
+		}
+		postings = append(postings, LedgerPosting{
+			CompanyID: e.CompanyID,
+			Account:   account,
+			Direction: direction,
+			Amount:    RoundCents(amount),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	add(AccountCash, true, cash)
+	add(AccountCompanyPayable, false, payable)
+	add(AccountPlatformRevenue, false, e.FeeAmount)
+
+	return postings
+}
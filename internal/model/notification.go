@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Notification - representa uma notificação exibida para a empresa
+type Notification struct {
+	ID          int        `gorm:"column:id;primaryKey;autoIncrement"`
+	CompanyID   int        `gorm:"column:company_id;not null;index"`
+	Type        string     `gorm:"column:type;not null"`
+	Title       string     `gorm:"column:title;not null"`
+	Message     string     `gorm:"column:message;type:text"`
+	ReferenceID *int       `gorm:"column:reference_id"`
+	ReadAt      *time.Time `gorm:"column:read_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName - especifica o nome da tabela no banco de dados
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Tipos de notificação
+const (
+	NotificationDisputeOpened   = "dispute_opened"
+	NotificationDisputeResolved = "dispute_resolved"
+	NotificationImageReady      = "image_ready"
+	NotificationImageFailed     = "image_failed"
+)
@@ -19,6 +19,12 @@ type Pagamento struct {
 	TokenCartao          string  `gorm:"column:token_cartao"`
 	ChavePIX             string  `gorm:"column:chave_pix"`
 	QRCode               string  `gorm:"column:qr_code;type:text"`
+	PixEndToEndID        string  `gorm:"column:pix_end_to_end_id"`
+
+	// Dados do boleto emitido pelo Mercado Pago
+	CodigoBarras   string `gorm:"column:codigo_barras"`
+	LinhaDigitavel string `gorm:"column:linha_digitavel"`
+	BoletoURL      string `gorm:"column:boleto_url;type:text"`
 
 	// Campos específicos para cartão de crédito (dados não sensíveis)
 	LastFourDigits            string  `gorm:"column:last_four_digits"`
@@ -29,12 +35,17 @@ type Pagamento struct {
 	Captured                  bool    `gorm:"column:captured;default:false"`
 	TransactionAmountRefunded float64 `gorm:"column:transaction_amount_refunded;type:decimal(10,2);default:0"`
 
+	// Comissão da plataforma enviada como application_fee no split do marketplace
+	TaxaPlataforma   float64 `gorm:"column:taxa_plataforma;type:decimal(10,2);default:0"`
+	SplitMarketplace bool    `gorm:"column:split_marketplace;default:false"`
+
 	MomentoCriacao      time.Time  `gorm:"column:momento_criacao;not null;default:CURRENT_TIMESTAMP"`
 	MomentoAtualizacao  time.Time  `gorm:"column:momento_atualizacao;not null;default:CURRENT_TIMESTAMP"`
 	MomentoAprovacao    *time.Time `gorm:"column:momento_aprovacao"`
 	MomentoCancelamento *time.Time `gorm:"column:momento_cancelamento"`
 	MomentoAutorizacao  *time.Time `gorm:"column:momento_autorizacao"`
 	MomentoCaptura      *time.Time `gorm:"column:momento_captura"`
+	ExpiraEm            *time.Time `gorm:"column:expira_em;index"`
 
 	// Relacionamentos
 	Cliente Client `gorm:"foreignKey:ClienteID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
@@ -61,6 +72,13 @@ const (
 	StatusChargedBack StatusPagamento = "charged_back"
 )
 
+// StatusNaoFinais - status que ainda podem ser alterados pelo Mercado Pago
+var StatusNaoFinais = []string{
+	string(StatusPending),
+	string(StatusInProcess),
+	string(StatusAuthorized),
+}
+
 // MetodoPagamento - define os métodos de pagamento suportados
 type MetodoPagamento string
 
@@ -68,6 +86,7 @@ const (
 	MetodoCartaoCredito MetodoPagamento = "credit_card"
 	MetodoCartaoDebito  MetodoPagamento = "debit_card"
 	MetodoPIX           MetodoPagamento = "pix"
+	MetodoBoleto        MetodoPagamento = "boleto"
 )
 
 // Moeda - define as moedas suportadas
@@ -110,6 +129,16 @@ func (p *Pagamento) IsAuthorized() bool {
 	return p.Status == string(StatusAuthorized)
 }
 
+// IsFinal - indica se o status não será mais alterado pelo Mercado Pago
+func (p *Pagamento) IsFinal() bool {
+	for _, status := range StatusNaoFinais {
+		if p.Status == status {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *Pagamento) IsCaptured() bool {
 	return p.Captured && p.Status == string(StatusApproved)
 }
@@ -152,6 +181,8 @@ func (p *Pagamento) GetMetodoPagamentoDisplay() string {
 		return "Cartão de Débito"
 	case string(MetodoPIX):
 		return "PIX"
+	case string(MetodoBoleto):
+		return "Boleto Bancário"
 	default:
 		return "Desconhecido"
 	}
@@ -170,7 +201,7 @@ func IsValidStatus(status StatusPagamento) bool {
 
 func IsValidPaymentMethod(method MetodoPagamento) bool {
 	switch method {
-	case MetodoCartaoCredito, MetodoCartaoDebito, MetodoPIX:
+	case MetodoCartaoCredito, MetodoCartaoDebito, MetodoPIX, MetodoBoleto:
 		return true
 	default:
 		return false
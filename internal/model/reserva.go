@@ -7,6 +7,7 @@ type Reserva struct {
 	ID                  int        `gorm:"column:id;primaryKey;autoIncrement"`
 	ClienteID           int        `gorm:"column:cliente_id;not null"`
 	EmpresaID           int        `gorm:"column:empresa_id;not null"`
+	TourID              int        `gorm:"column:tour_id;not null"`
 	PagamentoID         int        `gorm:"column:pagamento_id"`
 	Status              string     `gorm:"column:status;not null;default:'pendente'"`
 	DataReserva         time.Time  `gorm:"column:data_reserva;not null"`
@@ -60,6 +61,11 @@ func (r *Reserva) IsCompleted() bool {
 	return r.Status == string(StatusReservaConcluida)
 }
 
+// CanBePaid - indica se a reserva ainda aguarda pagamento
+func (r *Reserva) CanBePaid() bool {
+	return r.Status == string(StatusReservaPendente)
+}
+
 func (r *Reserva) CanBeCancelled() bool {
 	return r.Status == string(StatusReservaPendente) || r.Status == string(StatusReservaConfirmada)
 }
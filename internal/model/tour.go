@@ -17,12 +17,12 @@ type Tour struct {
 	ArrivalTime   string         `gorm:"column:arrival_time"`
 	MaxPeople     int            `gorm:"column:max_people;default:1"`
 	Description   string         `gorm:"column:description"`
-	Images        pq.StringArray `gorm:"column:images;type:text[]"`
 	Price         float64        `gorm:"column:price;type:decimal(10,2);default:0"`
 	CreatedAt     time.Time      `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt     time.Time      `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
 
 	Company Company `gorm:"foreignKey:CompanyID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Images  []Image `gorm:"foreignKey:TourID;references:ID"`
 }
 
 // TableName especifica o nome da tabela no banco de dados
@@ -45,15 +45,6 @@ func (t *Tour) HasValidTimes() bool {
 	return true
 }
 
-func (t *Tour) HasValidImages() bool {
-	for _, img := range t.Images {
-		if img == "" {
-			return false
-		}
-	}
-	return true
-}
-
 func (t *Tour) GetFormattedPrice() string {
 	return fmt.Sprintf("%.2f", t.Price)
 }
@@ -31,6 +31,12 @@ var (
 		WHERE email = ?;
 	`
 
+	GetClientMercadoPagoCustomerID = `
+		SELECT COALESCE(mercado_pago_customer_id, '') AS mercado_pago_customer_id
+		FROM clients
+		WHERE id = ?;
+	`
+
 	CreateClient = `
 		INSERT INTO clients (name, email, password, cpf, phone, birth_date, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
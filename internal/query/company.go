@@ -61,4 +61,10 @@ var (
 			CASE WHEN (? <> '') THEN address = ? ELSE TRUE END
 		ORDER BY created_at DESC;
 	`
+
+	GetCompanyPlan = `
+		SELECT COALESCE(plan, '') AS plan
+		FROM companies
+		WHERE id = ?
+	`
 )
@@ -4,8 +4,9 @@ var (
 	CreateImage = `
         INSERT INTO images (
             user_id, tour_id, filename, original_name, url, thumbnail_url,
-            size, width, height, format, description, alt_text, is_primary, sort_order
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+            size, width, height, format, description, alt_text, is_primary, sort_order, captured_at, content_hash, status, visibility,
+            blurhash, dominant_color, focal_x, focal_y, crop_x, crop_y, crop_width, crop_height, thumbnail_size
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
         RETURNING id, uploaded_at, updated_at
     `
 
@@ -13,7 +14,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE id = $1
     `
@@ -22,7 +24,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE id = $1 AND user_id = $2
     `
@@ -34,6 +37,7 @@ var (
             description = $3,
             alt_text = $4,
             is_primary = $5,
+            visibility = $6,
             updated_at = CURRENT_TIMESTAMP
         WHERE id = $1
         RETURNING updated_at
@@ -47,7 +51,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE user_id = $1
             AND ($2::int IS NULL OR tour_id = $2)
@@ -72,7 +77,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE tour_id = $1 AND user_id = $2
         ORDER BY sort_order ASC, id ASC
@@ -150,7 +156,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE id = ANY($1::int[]) AND user_id = $2
         ORDER BY id
@@ -165,12 +172,25 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE tour_id = $1
         ORDER BY sort_order ASC, id ASC
     `
 
+	ListImagesByTourIDs = `
+        SELECT 
+            i.id, i.user_id, i.tour_id, i.filename, i.original_name, i.url, i.thumbnail_url,
+            i.size, i.width, i.height, i.format, i.description, i.alt_text, i.is_primary, i.sort_order,
+            i.uploaded_at, i.updated_at, i.captured_at, i.status, i.visibility, i.blurhash, i.dominant_color,
+            i.focal_x, i.focal_y, i.crop_x, i.crop_y, i.crop_width, i.crop_height
+        FROM images i
+        INNER JOIN tours t ON i.tour_id = t.id AND i.user_id = t.company_id
+        WHERE i.tour_id = ANY($1::int[]) AND ($2 OR (i.visibility = 'public' AND i.status = 'ready'))
+        ORDER BY i.tour_id, i.is_primary DESC, i.sort_order ASC, i.id ASC
+    `
+
 	CheckImageExists = `
         SELECT COUNT(*) > 0
         FROM images 
@@ -181,7 +201,8 @@ var (
         SELECT 
             i.id, i.user_id, i.tour_id, i.filename, i.original_name, i.url, i.thumbnail_url,
             i.size, i.width, i.height, i.format, i.description, i.alt_text, i.is_primary, i.sort_order,
-            i.uploaded_at, i.updated_at,
+            i.uploaded_at, i.updated_at, i.captured_at, i.status, i.visibility, i.blurhash, i.dominant_color,
+            i.focal_x, i.focal_y, i.crop_x, i.crop_y, i.crop_width, i.crop_height,
             t.name as tour_name
         FROM images i
         LEFT JOIN tours t ON i.tour_id = t.id
@@ -192,7 +213,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE user_id = $1
         ORDER BY uploaded_at DESC
@@ -203,7 +225,8 @@ var (
         SELECT 
             id, user_id, tour_id, filename, original_name, url, thumbnail_url,
             size, width, height, format, description, alt_text, is_primary, sort_order,
-            uploaded_at, updated_at
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
         FROM images 
         WHERE user_id = $1
             AND (
@@ -225,4 +248,176 @@ var (
                 OR description ILIKE $2
             )
     `
+
+	CreateImageRendition = `
+        INSERT INTO image_renditions (image_id, width, height, format, storage_key, size)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+
+	ListImageRenditionsByImageIDs = `
+        SELECT id, image_id, width, height, format, storage_key, size, created_at
+        FROM image_renditions
+        WHERE image_id = ANY($1)
+        ORDER BY image_id, format, width
+    `
+
+	GetImageByContentHash = `
+        SELECT 
+            id, user_id, tour_id, filename, original_name, url, thumbnail_url,
+            size, width, height, format, description, alt_text, is_primary, sort_order,
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height, thumbnail_size
+        FROM images 
+        WHERE user_id = $1 AND content_hash = $2 AND visibility = $3 AND status = 'ready'
+        ORDER BY id
+        LIMIT 1
+    `
+
+	LockImage = `
+        SELECT id FROM images WHERE id = $1 FOR UPDATE
+    `
+
+	CopyImageRenditions = `
+        INSERT INTO image_renditions (image_id, width, height, format, storage_key, size)
+        SELECT $1, width, height, format, storage_key, size
+        FROM image_renditions
+        WHERE image_id = $2
+    `
+
+	CountImagesByURL = `
+        SELECT COUNT(*) FROM images WHERE url = $1
+    `
+
+	GetImageStorageUsage = `
+        SELECT storage_used, total_images
+        FROM image_stats
+        WHERE user_id = $1
+    `
+
+	LockImageQuota = `
+        SELECT pg_advisory_xact_lock(hashtext('image_quota'), $1)
+    `
+
+	CreateImageJob = `
+        INSERT INTO image_jobs (image_id, source_key)
+        VALUES ($1, $2)
+        RETURNING id, status, attempts, run_after, created_at
+    `
+
+	ClaimImageJob = `
+        UPDATE image_jobs
+        SET status = 'running', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+        WHERE id = (
+            SELECT id FROM image_jobs
+            WHERE (status = 'pending' AND run_after <= NOW())
+                OR (status = 'running' AND locked_at < NOW() - $1 * INTERVAL '1 second')
+            ORDER BY run_after, id
+            FOR UPDATE SKIP LOCKED
+            LIMIT 1
+        )
+        RETURNING id, image_id, source_key, status, attempts, run_after, created_at
+    `
+
+	RetryImageJob = `
+        UPDATE image_jobs
+        SET status = 'pending', last_error = $2, run_after = NOW() + $3 * INTERVAL '1 second', locked_at = NULL, updated_at = NOW()
+        WHERE id = $1
+    `
+
+	FailImageJob = `
+        UPDATE image_jobs
+        SET status = 'failed', last_error = $2, locked_at = NULL, updated_at = NOW()
+        WHERE id = $1
+    `
+
+	DeleteImageJob = `
+        DELETE FROM image_jobs WHERE id = $1
+    `
+
+	CompleteImageProcessing = `
+        UPDATE images
+        SET thumbnail_url = $2, size = $3, width = $4, height = $5, format = $6, captured_at = $7,
+            blurhash = $8, dominant_color = $9, thumbnail_size = $10, status = 'ready', updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'processing'
+    `
+
+	SetImageStatus = `
+        UPDATE images SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+    `
+
+	ListUnusedImages = `
+        SELECT 
+            id, user_id, tour_id, filename, original_name, url, thumbnail_url,
+            size, width, height, format, description, alt_text, is_primary, sort_order,
+            uploaded_at, updated_at, captured_at, status, visibility, blurhash, dominant_color,
+            focal_x, focal_y, crop_x, crop_y, crop_width, crop_height
+        FROM images 
+        WHERE tour_id IS NULL AND visibility = 'public' AND status <> 'processing' AND unattached_at < $1
+        ORDER BY unattached_at, id
+        LIMIT $2
+    `
+
+	DeleteUnusedImage = `
+        DELETE FROM images 
+        WHERE id = $1 AND tour_id IS NULL AND visibility = 'public' AND status <> 'processing' AND unattached_at < $2
+    `
+
+	ListStorageReferences = `
+        SELECT url FROM images
+        UNION SELECT thumbnail_url FROM images WHERE thumbnail_url <> ''
+        UNION SELECT storage_key FROM image_renditions
+        UNION SELECT source_key FROM image_jobs
+    `
+
+	ListReadyImageFiles = `
+        SELECT id, url FROM images
+        WHERE status = 'ready' AND updated_at < $1
+        ORDER BY id
+    `
+
+	ListRenditionFiles = `
+        SELECT DISTINCT storage_key FROM image_renditions
+        WHERE created_at < $1
+    `
+
+	UpdateImageDerivatives = `
+        UPDATE images
+        SET thumbnail_url = $3, blurhash = $4, dominant_color = $5, focal_x = $6, focal_y = $7,
+            crop_x = $8, crop_y = $9, crop_width = $10, crop_height = $11, thumbnail_size = $12, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND thumbnail_url = $2 AND status = 'ready'
+    `
+
+	UpdateImageFiles = `
+        UPDATE images
+        SET filename = $3, url = $4, thumbnail_url = $5, visibility = $6, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND url = $2 AND status = 'ready'
+    `
+
+	DeleteImageRenditions = `
+        DELETE FROM image_renditions WHERE image_id = $1
+    `
+
+	CountFileReferences = `
+        SELECT (SELECT COUNT(*) FROM images WHERE url = $1 OR thumbnail_url = $1)
+             + (SELECT COUNT(*) FROM image_renditions WHERE storage_key = $1)
+    `
+
+	DeleteRenditionsByKey = `
+        DELETE FROM image_renditions WHERE storage_key = $1
+    `
+
+	IsPrivateMediaKey = `
+        SELECT COALESCE(BOOL_AND(visibility = 'private'), FALSE)
+        FROM (
+            SELECT visibility FROM images WHERE url = $1
+            UNION ALL
+            SELECT visibility FROM images WHERE thumbnail_url = $1
+            UNION ALL
+            SELECT i.visibility
+            FROM image_renditions r
+            JOIN images i ON i.id = r.image_id
+            WHERE r.storage_key = $1
+        ) refs
+    `
 )
@@ -2,14 +2,14 @@ package query
 
 var (
 	CreateTour = `
-		INSERT INTO tours (company_id, name, dates, departure_time, arrival_time, max_people, description, images, price, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+		INSERT INTO tours (company_id, name, dates, departure_time, arrival_time, max_people, description, price, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
 		RETURNING id;
 	`
 
 	UpdateTour = `
 		UPDATE tours 
-		SET name = ?, dates = ?, departure_time = ?, arrival_time = ?, max_people = ?, description = ?, images = ?, price = ?, updated_at = NOW()
+		SET name = ?, dates = ?, departure_time = ?, arrival_time = ?, max_people = ?, description = ?, price = ?, updated_at = NOW()
 		WHERE id = ?;
 	`
 
@@ -23,7 +23,6 @@ var (
 			COALESCE(t.arrival_time, '') AS arrival_time,
 			COALESCE(t.max_people, 0) AS max_people,
 			COALESCE(t.description, '') AS description,
-			t.images,
 			COALESCE(t.price, 0) AS price,
 			t.created_at,
 			t.updated_at,
@@ -43,7 +42,6 @@ var (
 			COALESCE(t.arrival_time, '') AS arrival_time,
 			COALESCE(t.max_people, 0) AS max_people,
 			COALESCE(t.description, '') AS description,
-			t.images,
 			COALESCE(t.price, 0) AS price,
 			t.created_at,
 			t.updated_at,
@@ -65,14 +63,13 @@ var (
 			COALESCE(t.arrival_time, '') AS arrival_time,
 			COALESCE(t.max_people, 0) AS max_people,
 			COALESCE(t.description, '') AS description,
-			t.images,
 			COALESCE(t.price, 0) AS price,
 			t.created_at,
 			COALESCE(COUNT(r.id), 0) AS reservations_count
 		FROM tours t
 		LEFT JOIN reservas r ON t.id = r.tour_id
 		WHERE t.company_id = ?
-		GROUP BY t.id, t.company_id, t.name, t.dates, t.departure_time, t.arrival_time, t.max_people, t.description, t.images, t.price, t.created_at
+		GROUP BY t.id, t.company_id, t.name, t.dates, t.departure_time, t.arrival_time, t.max_people, t.description, t.price, t.created_at
 		ORDER BY t.created_at DESC
 		LIMIT ? OFFSET ?;
 	`
@@ -99,6 +96,24 @@ var (
 		WHERE id = ? AND company_id = ?;
 	`
 
+	DetachTourImages = `
+		UPDATE images
+		SET tour_id = NULL, is_primary = FALSE, sort_order = 0
+		WHERE tour_id = ? AND id <> ALL(?::int[]);
+	`
+
+	AttachTourImages = `
+		UPDATE images
+		SET tour_id = ?, is_primary = FALSE, sort_order = array_position(?::int[], id) - 1
+		WHERE id = ANY(?::int[]) AND user_id = ? AND (tour_id IS NULL OR tour_id = ?);
+	`
+
+	SetTourPrimaryImage = `
+		UPDATE images
+		SET is_primary = TRUE
+		WHERE id = ? AND tour_id = ?;
+	`
+
 	CountReservationsByTourID = `
 		SELECT COUNT(*)
 		FROM reservas
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/jampa_trip/internal/model"
 	"github.com/jampa_trip/internal/query"
 	"gorm.io/gorm"
@@ -64,6 +66,21 @@ func (receiver *ClientRepository) GetByEmail(email string) (*model.Client, error
 	return row, nil
 }
 
+// GetMercadoPagoCustomerID - busca o ID do customer do Mercado Pago vinculado ao cliente
+func (receiver *ClientRepository) GetMercadoPagoCustomerID(id int) (string, error) {
+	var customerID string
+	err := receiver.DB.Raw(query.GetClientMercadoPagoCustomerID, id).Row().Scan(&customerID)
+	return customerID, err
+}
+
+// SetMercadoPagoCustomerID - vincula o customer do Mercado Pago ao cliente
+func (receiver *ClientRepository) SetMercadoPagoCustomerID(id int, customerID string) error {
+	return receiver.Update(id, map[string]interface{}{
+		"mercado_pago_customer_id": customerID,
+		"updated_at":               time.Now(),
+	})
+}
+
 // Create - cria um novo cliente
 func (receiver *ClientRepository) Create(client *model.Client) error {
 	err := receiver.DB.Raw(query.CreateClient,
@@ -130,3 +130,10 @@ func (r *CompanyRepository) EmailExisteParaOutraEmpresa(email string, id int) (b
 	err := r.DB.Model(&model.Company{}).Where("email = ? AND id != ?", email, id).Count(&count).Error
 	return count > 0, err
 }
+
+// GetPlan - busca o plano contratado pela empresa
+func (receiver *CompanyRepository) GetPlan(id int) (string, error) {
+	var plan string
+	err := receiver.DB.Raw(query.GetCompanyPlan, id).Row().Scan(&plan)
+	return plan, err
+}
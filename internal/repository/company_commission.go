@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/jampa_trip/internal/model"
+	"gorm.io/gorm"
+)
+
+// CompanyCommissionRepository - objeto de contexto
+type CompanyCommissionRepository struct {
+	DB *gorm.DB
+}
+
+// CompanyCommissionRepositoryNew - construtor do objeto
+func CompanyCommissionRepositoryNew(DB *gorm.DB) *CompanyCommissionRepository {
+	return &CompanyCommissionRepository{
+		DB: DB,
+	}
+}
+
+// GetByCompanyID - busca a comissão configurada para uma empresa
+func (r *CompanyCommissionRepository) GetByCompanyID(companyID int) (*model.CompanyCommission, error) {
+	var commission model.CompanyCommission
+	err := r.DB.Where("company_id = ?", companyID).First(&commission).Error
+	if err != nil {
+		return nil, err
+	}
+	return &commission, nil
+}
+
+// Save - cria ou atualiza a comissão de uma empresa
+func (r *CompanyCommissionRepository) Save(commission *model.CompanyCommission) error {
+	return r.DB.Save(commission).Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jampa_trip/internal/model"
+	"gorm.io/gorm"
+)
+
+// DisputeRepository - objeto de contexto
+type DisputeRepository struct {
+	DB *gorm.DB
+}
+
+// DisputeRepositoryNew - construtor do objeto
+func DisputeRepositoryNew(DB *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{
+		DB: DB,
+	}
+}
+
+// Create - cria uma nova disputa
+func (r *DisputeRepository) Create(dispute *model.Dispute) error {
+	return r.DB.Create(dispute).Error
+}
+
+// Update - atualiza uma disputa existente
+func (r *DisputeRepository) Update(dispute *model.Dispute) error {
+	return r.DB.Omit("Evidences").Save(dispute).Error
+}
+
+// GetByID - busca uma disputa da empresa junto com as evidências enviadas
+func (r *DisputeRepository) GetByID(id, companyID int) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := r.DB.Preload("Evidences", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("id = ? AND company_id = ?", id, companyID).First(&dispute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// GetByChargebackID - busca a disputa vinculada a um chargeback do Mercado Pago
+func (r *DisputeRepository) GetByChargebackID(chargebackID string) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := r.DB.Where("mercado_pago_chargeback_id = ?", chargebackID).First(&dispute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// GetUnresolvedByPagamentoID - busca a disputa ainda não decidida de um pagamento
+func (r *DisputeRepository) GetUnresolvedByPagamentoID(pagamentoID int) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := r.DB.Where("pagamento_id = ? AND status IN ?", pagamentoID,
+		[]string{string(model.DisputeOpen), string(model.DisputeEvidenceSubmitted)}).
+		Order("created_at DESC").
+		First(&dispute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// ListByCompanyID - lista as disputas de uma empresa, opcionalmente filtradas pelo status
+func (r *DisputeRepository) ListByCompanyID(companyID int, status string) ([]model.Dispute, error) {
+	var disputes []model.Dispute
+	query := r.DB.Where("company_id = ?", companyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&disputes).Error
+	return disputes, err
+}
+
+// CreateEvidence - registra um arquivo de evidência da disputa
+func (r *DisputeRepository) CreateEvidence(evidence *model.DisputeEvidence) error {
+	return r.DB.Create(evidence).Error
+}
+
+// MarkEvidencesSubmitted - marca as evidências como enviadas ao Mercado Pago
+func (r *DisputeRepository) MarkEvidencesSubmitted(ids []int, submittedAt time.Time) error {
+	return r.DB.Model(&model.DisputeEvidence{}).Where("id IN ?", ids).Update("submitted_at", submittedAt).Error
+}
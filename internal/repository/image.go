@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jampa_trip/internal/model"
 	"github.com/jampa_trip/internal/query"
@@ -25,7 +26,12 @@ func ImageRepositoryNew(DB *gorm.DB) *ImageRepository {
 
 // Create - cria uma nova imagem
 func (r *ImageRepository) Create(image *model.Image) error {
-	err := r.DB.Raw(query.CreateImage,
+	return createImage(r.DB, image)
+}
+
+// createImage - insere a imagem usando a conexão ou transação informada
+func createImage(db *gorm.DB, image *model.Image) error {
+	err := db.Raw(query.CreateImage,
 		image.UserID,
 		image.TourID,
 		image.Filename,
@@ -40,11 +46,40 @@ func (r *ImageRepository) Create(image *model.Image) error {
 		image.AltText,
 		image.IsPrimary,
 		image.SortOrder,
+		image.CapturedAt,
+		sql.NullString{String: image.ContentHash, Valid: image.ContentHash != ""},
+		imageStatus(image),
+		imageVisibility(image),
+		image.BlurHash,
+		image.DominantColor,
+		image.FocalX,
+		image.FocalY,
+		image.CropX,
+		image.CropY,
+		image.CropWidth,
+		image.CropHeight,
+		image.ThumbnailSize,
 	).Row().Scan(&image.ID, &image.UploadedAt, &image.UpdatedAt)
 
 	return err
 }
 
+// imageStatus - situação gravada na criação da imagem; sem indicação a imagem já está pronta
+func imageStatus(image *model.Image) string {
+	if image.Status == "" {
+		image.Status = model.ImageStatusReady
+	}
+	return image.Status
+}
+
+// imageVisibility - visibilidade gravada da imagem; sem indicação a imagem é pública
+func imageVisibility(image *model.Image) string {
+	if image.Visibility == "" {
+		image.Visibility = model.ImageVisibilityPublic
+	}
+	return image.Visibility
+}
+
 // GetByID - busca uma imagem pelo ID
 func (r *ImageRepository) GetByID(id int) (*model.Image, error) {
 	image := &model.Image{}
@@ -67,6 +102,17 @@ func (r *ImageRepository) GetByID(id int) (*model.Image, error) {
 		&image.SortOrder,
 		&image.UploadedAt,
 		&image.UpdatedAt,
+		&image.CapturedAt,
+		&image.Status,
+		&image.Visibility,
+		&image.BlurHash,
+		&image.DominantColor,
+		&image.FocalX,
+		&image.FocalY,
+		&image.CropX,
+		&image.CropY,
+		&image.CropWidth,
+		&image.CropHeight,
 	)
 
 	if err != nil {
@@ -98,6 +144,17 @@ func (r *ImageRepository) GetByIDAndUser(id, userID int) (*model.Image, error) {
 		&image.SortOrder,
 		&image.UploadedAt,
 		&image.UpdatedAt,
+		&image.CapturedAt,
+		&image.Status,
+		&image.Visibility,
+		&image.BlurHash,
+		&image.DominantColor,
+		&image.FocalX,
+		&image.FocalY,
+		&image.CropX,
+		&image.CropY,
+		&image.CropWidth,
+		&image.CropHeight,
 	)
 
 	if err != nil {
@@ -115,6 +172,7 @@ func (r *ImageRepository) Update(image *model.Image) error {
 		image.Description,
 		image.AltText,
 		image.IsPrimary,
+		imageVisibility(image),
 	).Row().Scan(&image.UpdatedAt)
 
 	return err
@@ -122,8 +180,7 @@ func (r *ImageRepository) Update(image *model.Image) error {
 
 // Delete - deleta uma imagem
 func (r *ImageRepository) Delete(id int) error {
-	err := r.DB.Raw(query.DeleteImage, id).Row().Scan()
-	return err
+	return r.DB.Exec(query.DeleteImage, id).Error
 }
 
 // List - lista imagens do usuário com filtros
@@ -158,6 +215,17 @@ func (r *ImageRepository) List(userID int, tourID *int, format string, sortBy st
 			&image.SortOrder,
 			&image.UploadedAt,
 			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -204,6 +272,17 @@ func (r *ImageRepository) ListByTour(tourID, userID int) ([]*model.Image, error)
 			&image.SortOrder,
 			&image.UploadedAt,
 			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
 		)
 		if err != nil {
 			return nil, err
@@ -355,6 +434,17 @@ func (r *ImageRepository) GetByIDs(imageIDs []int, userID int) ([]*model.Image,
 			&image.SortOrder,
 			&image.UploadedAt,
 			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
 		)
 		if err != nil {
 			return nil, err
@@ -401,6 +491,17 @@ func (r *ImageRepository) GetByTourID(tourID int) ([]*model.Image, error) {
 			&image.SortOrder,
 			&image.UploadedAt,
 			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
 		)
 		if err != nil {
 			return nil, err
@@ -411,6 +512,62 @@ func (r *ImageRepository) GetByTourID(tourID int) ([]*model.Image, error) {
 	return images, nil
 }
 
+// ListByTourIDs - busca as imagens dos passeios, agrupadas pelo ID do passeio e com a principal primeiro. Sem
+// includeHidden, apenas as imagens públicas e prontas, que podem ser exibidas a qualquer usuário.
+func (r *ImageRepository) ListByTourIDs(tourIDs []int, includeHidden bool) (map[int][]*model.Image, error) {
+	images := make(map[int][]*model.Image)
+	if len(tourIDs) == 0 {
+		return images, nil
+	}
+
+	rows, err := r.DB.Raw(query.ListImagesByTourIDs, pq.Array(tourIDs), includeHidden).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image := &model.Image{}
+
+		err := rows.Scan(
+			&image.ID,
+			&image.UserID,
+			&image.TourID,
+			&image.Filename,
+			&image.OriginalName,
+			&image.URL,
+			&image.ThumbnailURL,
+			&image.Size,
+			&image.Width,
+			&image.Height,
+			&image.Format,
+			&image.Description,
+			&image.AltText,
+			&image.IsPrimary,
+			&image.SortOrder,
+			&image.UploadedAt,
+			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+		images[*image.TourID] = append(images[*image.TourID], image)
+	}
+
+	return images, rows.Err()
+}
+
 // Exists - verifica se a imagem existe
 func (r *ImageRepository) Exists(id int) (bool, error) {
 	var exists bool
@@ -444,6 +601,17 @@ func (r *ImageRepository) GetWithTourInfo(id int) (*model.Image, string, error)
 		&image.SortOrder,
 		&image.UploadedAt,
 		&image.UpdatedAt,
+		&image.CapturedAt,
+		&image.Status,
+		&image.Visibility,
+		&image.BlurHash,
+		&image.DominantColor,
+		&image.FocalX,
+		&image.FocalY,
+		&image.CropX,
+		&image.CropY,
+		&image.CropWidth,
+		&image.CropHeight,
 		&tourName,
 	)
 
@@ -488,6 +656,17 @@ func (r *ImageRepository) GetRecent(userID, limit int) ([]*model.Image, error) {
 			&image.SortOrder,
 			&image.UploadedAt,
 			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
 		)
 		if err != nil {
 			return nil, err
@@ -531,6 +710,17 @@ func (r *ImageRepository) Search(userID int, searchTerm string, page, limit int)
 			&image.SortOrder,
 			&image.UploadedAt,
 			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -546,3 +736,542 @@ func (r *ImageRepository) Search(userID int, searchTerm string, page, limit int)
 
 	return images, total, nil
 }
+
+// CreateRenditions - grava as versões redimensionadas de uma imagem
+func (r *ImageRepository) CreateRenditions(renditions []model.ImageRendition) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range renditions {
+			rendition := &renditions[i]
+			err := tx.Raw(query.CreateImageRendition,
+				rendition.ImageID,
+				rendition.Width,
+				rendition.Height,
+				rendition.Format,
+				rendition.StorageKey,
+				rendition.Size,
+			).Row().Scan(&rendition.ID, &rendition.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListRenditions - busca as versões redimensionadas das imagens, agrupadas pelo ID da imagem
+func (r *ImageRepository) ListRenditions(imageIDs []int) (map[int][]model.ImageRendition, error) {
+	renditions := make(map[int][]model.ImageRendition)
+	if len(imageIDs) == 0 {
+		return renditions, nil
+	}
+
+	rows, err := r.DB.Raw(query.ListImageRenditionsByImageIDs, pq.Array(imageIDs)).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rendition model.ImageRendition
+		err := rows.Scan(
+			&rendition.ID,
+			&rendition.ImageID,
+			&rendition.Width,
+			&rendition.Height,
+			&rendition.Format,
+			&rendition.StorageKey,
+			&rendition.Size,
+			&rendition.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		renditions[rendition.ImageID] = append(renditions[rendition.ImageID], rendition)
+	}
+
+	return renditions, rows.Err()
+}
+
+// GetByContentHash - busca a imagem mais antiga do usuário com o mesmo conteúdo e a mesma visibilidade
+func (r *ImageRepository) GetByContentHash(userID int, contentHash, visibility string) (*model.Image, error) {
+	image := &model.Image{}
+
+	err := r.DB.Raw(query.GetImageByContentHash, userID, contentHash, visibility).Row().Scan(
+		&image.ID,
+		&image.UserID,
+		&image.TourID,
+		&image.Filename,
+		&image.OriginalName,
+		&image.URL,
+		&image.ThumbnailURL,
+		&image.Size,
+		&image.Width,
+		&image.Height,
+		&image.Format,
+		&image.Description,
+		&image.AltText,
+		&image.IsPrimary,
+		&image.SortOrder,
+		&image.UploadedAt,
+		&image.UpdatedAt,
+		&image.CapturedAt,
+		&image.Status,
+		&image.Visibility,
+		&image.BlurHash,
+		&image.DominantColor,
+		&image.FocalX,
+		&image.FocalY,
+		&image.CropX,
+		&image.CropY,
+		&image.CropWidth,
+		&image.CropHeight,
+		&image.ThumbnailSize,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	image.ContentHash = contentHash
+	return image, nil
+}
+
+// CreateDuplicate - grava uma imagem que reaproveita os arquivos de outra, copiando suas versões.
+// A imagem de origem fica bloqueada na transação para que uma exclusão simultânea não remova os arquivos;
+// retorna false quando ela já foi excluída.
+func (r *ImageRepository) CreateDuplicate(sourceID int, image *model.Image) (bool, error) {
+	created := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var id int
+		err := tx.Raw(query.LockImage, sourceID).Row().Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := createImage(tx, image); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(query.CopyImageRenditions, image.ID, sourceID).Error; err != nil {
+			return err
+		}
+
+		created = true
+		return nil
+	})
+
+	return created, err
+}
+
+// CountByURL - conta as imagens que apontam para o mesmo arquivo
+func (r *ImageRepository) CountByURL(url string) (int64, error) {
+	var count int64
+	err := r.DB.Raw(query.CountImagesByURL, url).Row().Scan(&count)
+	return count, err
+}
+
+// GetStorageUsage - espaço ocupado pelos arquivos distintos do usuário e total de imagens, a partir da view image_stats
+func (r *ImageRepository) GetStorageUsage(userID int) (int64, int, error) {
+	return storageUsage(r.DB, userID)
+}
+
+// CreateWithinQuota - cria a imagem apenas se o espaço ocupado pelo usuário somado ao tamanho dela não ultrapassar a cota.
+// Um lock por usuário serializa os uploads simultâneos entre a verificação e a gravação; retorna false quando a cota seria excedida.
+func (r *ImageRepository) CreateWithinQuota(image *model.Image, quota int64) (bool, error) {
+	return r.createWithinQuota(image, nil, quota)
+}
+
+// CreateForProcessing - cria a imagem pendente de processamento junto com o job que vai processá-la,
+// com a mesma verificação de cota de CreateWithinQuota; cota zero dispensa a verificação
+func (r *ImageRepository) CreateForProcessing(image *model.Image, job *model.ImageJob, quota int64) (bool, error) {
+	return r.createWithinQuota(image, job, quota)
+}
+
+// createWithinQuota - grava a imagem e, quando informado, o job de processamento na mesma transação
+func (r *ImageRepository) createWithinQuota(image *model.Image, job *model.ImageJob, quota int64) (bool, error) {
+	created := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if quota > 0 {
+			if err := tx.Exec(query.LockImageQuota, image.UserID).Error; err != nil {
+				return err
+			}
+
+			used, _, err := storageUsage(tx, image.UserID)
+			if err != nil {
+				return err
+			}
+
+			if used+int64(image.Size) > quota {
+				return nil
+			}
+		}
+
+		if err := createImage(tx, image); err != nil {
+			return err
+		}
+
+		if job != nil {
+			job.ImageID = image.ID
+			err := tx.Raw(query.CreateImageJob, job.ImageID, job.SourceKey).Row().Scan(
+				&job.ID, &job.Status, &job.Attempts, &job.RunAfter, &job.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		created = true
+		return nil
+	})
+
+	return created, err
+}
+
+// storageUsage - lê o uso de armazenamento usando a conexão ou transação informada
+func storageUsage(db *gorm.DB, userID int) (int64, int, error) {
+	var used int64
+	var totalImages int
+
+	err := db.Raw(query.GetImageStorageUsage, userID).Row().Scan(&used, &totalImages)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+
+	return used, totalImages, err
+}
+
+// ClaimJob - reserva o próximo job de processamento disponível, incluindo os que ficaram em execução por mais tempo que staleAfter
+// (worker interrompido); retorna nil quando não há job
+func (r *ImageRepository) ClaimJob(staleAfter time.Duration) (*model.ImageJob, error) {
+	job := &model.ImageJob{}
+
+	err := r.DB.Raw(query.ClaimImageJob, staleAfter.Seconds()).Row().Scan(
+		&job.ID,
+		&job.ImageID,
+		&job.SourceKey,
+		&job.Status,
+		&job.Attempts,
+		&job.RunAfter,
+		&job.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// RetryJob - devolve o job para a fila, disponível novamente após o intervalo informado
+func (r *ImageRepository) RetryJob(id int, lastError string, delay time.Duration) error {
+	return r.DB.Exec(query.RetryImageJob, id, lastError, delay.Seconds()).Error
+}
+
+// FailProcessing - marca o job e a imagem como falhos após esgotar as tentativas
+func (r *ImageRepository) FailProcessing(job *model.ImageJob, lastError string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(query.FailImageJob, job.ID, lastError).Error; err != nil {
+			return err
+		}
+		return tx.Exec(query.SetImageStatus, job.ImageID, model.ImageStatusFailed).Error
+	})
+}
+
+// DeleteJob - remove um job de processamento
+func (r *ImageRepository) DeleteJob(id int) error {
+	return r.DB.Exec(query.DeleteImageJob, id).Error
+}
+
+// CompleteProcessing - grava os dados da imagem processada e suas versões e remove o job.
+// Retorna false quando a imagem foi excluída ou já não estava em processamento.
+func (r *ImageRepository) CompleteProcessing(image *model.Image, renditions []model.ImageRendition, jobID int) (bool, error) {
+	completed := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(query.CompleteImageProcessing,
+			image.ID,
+			image.ThumbnailURL,
+			image.Size,
+			image.Width,
+			image.Height,
+			image.Format,
+			image.CapturedAt,
+			image.BlurHash,
+			image.DominantColor,
+			image.ThumbnailSize,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Exec(query.DeleteImageJob, jobID).Error
+		}
+
+		for i := range renditions {
+			rendition := &renditions[i]
+			rendition.ImageID = image.ID
+			err := tx.Raw(query.CreateImageRendition,
+				rendition.ImageID,
+				rendition.Width,
+				rendition.Height,
+				rendition.Format,
+				rendition.StorageKey,
+				rendition.Size,
+			).Row().Scan(&rendition.ID, &rendition.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(query.DeleteImageJob, jobID).Error; err != nil {
+			return err
+		}
+
+		image.Status = model.ImageStatusReady
+		completed = true
+		return nil
+	})
+
+	return completed, err
+}
+
+// ReplaceDerivatives - grava o recorte, o ponto focal, o thumbnail e os placeholders da imagem editada e troca suas
+// versões pelas novas. Retorna false quando a imagem foi excluída, não está pronta ou foi editada ao mesmo tempo
+// (o thumbnail gravado não é mais previousThumbnail).
+func (r *ImageRepository) ReplaceDerivatives(image *model.Image, previousThumbnail string, renditions []model.ImageRendition) (bool, error) {
+	replaced := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(query.UpdateImageDerivatives,
+			image.ID,
+			previousThumbnail,
+			image.ThumbnailURL,
+			image.BlurHash,
+			image.DominantColor,
+			image.FocalX,
+			image.FocalY,
+			image.CropX,
+			image.CropY,
+			image.CropWidth,
+			image.CropHeight,
+			image.ThumbnailSize,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := replaceRenditions(tx, image.ID, renditions); err != nil {
+			return err
+		}
+
+		replaced = true
+		return nil
+	})
+
+	return replaced, err
+}
+
+// ReplaceFiles - grava a visibilidade da imagem junto com as chaves novas do original, do thumbnail e das versões.
+// Retorna false quando a imagem foi excluída, não está pronta ou teve os arquivos trocados ao mesmo tempo
+// (o original gravado não é mais previousURL).
+func (r *ImageRepository) ReplaceFiles(image *model.Image, previousURL string, renditions []model.ImageRendition) (bool, error) {
+	replaced := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(query.UpdateImageFiles,
+			image.ID,
+			previousURL,
+			image.Filename,
+			image.URL,
+			image.ThumbnailURL,
+			imageVisibility(image),
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := replaceRenditions(tx, image.ID, renditions); err != nil {
+			return err
+		}
+
+		replaced = true
+		return nil
+	})
+
+	return replaced, err
+}
+
+// replaceRenditions - troca as versões gravadas da imagem pelas informadas, dentro da transação
+func replaceRenditions(tx *gorm.DB, imageID int, renditions []model.ImageRendition) error {
+	if err := tx.Exec(query.DeleteImageRenditions, imageID).Error; err != nil {
+		return err
+	}
+
+	for i := range renditions {
+		rendition := &renditions[i]
+		rendition.ImageID = imageID
+		err := tx.Raw(query.CreateImageRendition,
+			rendition.ImageID,
+			rendition.Width,
+			rendition.Height,
+			rendition.Format,
+			rendition.StorageKey,
+			rendition.Size,
+		).Row().Scan(&rendition.ID, &rendition.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CountFileReferences - conta as imagens e versões que ainda apontam para a chave do armazenamento
+func (r *ImageRepository) CountFileReferences(key string) (int64, error) {
+	var count int64
+	err := r.DB.Raw(query.CountFileReferences, key).Row().Scan(&count)
+	return count, err
+}
+
+// ListUnused - busca as imagens públicas que estão sem passeio desde antes da data informada; as privadas
+// (documentos, evidências, rascunhos) nunca têm passeio e não são excluídas
+func (r *ImageRepository) ListUnused(unattachedBefore time.Time, limit int) ([]*model.Image, error) {
+	rows, err := r.DB.Raw(query.ListUnusedImages, unattachedBefore, limit).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []*model.Image
+	for rows.Next() {
+		image := &model.Image{}
+
+		err := rows.Scan(
+			&image.ID,
+			&image.UserID,
+			&image.TourID,
+			&image.Filename,
+			&image.OriginalName,
+			&image.URL,
+			&image.ThumbnailURL,
+			&image.Size,
+			&image.Width,
+			&image.Height,
+			&image.Format,
+			&image.Description,
+			&image.AltText,
+			&image.IsPrimary,
+			&image.SortOrder,
+			&image.UploadedAt,
+			&image.UpdatedAt,
+			&image.CapturedAt,
+			&image.Status,
+			&image.Visibility,
+			&image.BlurHash,
+			&image.DominantColor,
+			&image.FocalX,
+			&image.FocalY,
+			&image.CropX,
+			&image.CropY,
+			&image.CropWidth,
+			&image.CropHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+
+	return images, rows.Err()
+}
+
+// DeleteUnused - exclui a imagem apenas se ela continuar pública e sem passeio desde antes da data informada;
+// retorna false quando foi vinculada, desvinculada de novo ou tornada privada nesse meio tempo
+func (r *ImageRepository) DeleteUnused(id int, unattachedBefore time.Time) (bool, error) {
+	result := r.DB.Exec(query.DeleteUnusedImage, id, unattachedBefore)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListStorageReferences - chaves do armazenamento referenciadas por imagens, versões e jobs de processamento
+func (r *ImageRepository) ListStorageReferences() ([]string, error) {
+	return r.listStrings(query.ListStorageReferences)
+}
+
+// ListRenditionKeys - chaves das versões criadas antes da data informada
+func (r *ImageRepository) ListRenditionKeys(createdBefore time.Time) ([]string, error) {
+	return r.listStrings(query.ListRenditionFiles, createdBefore)
+}
+
+// ListReadyOriginals - chave do original das imagens prontas, por ID, alteradas antes da data informada
+func (r *ImageRepository) ListReadyOriginals(updatedBefore time.Time) (map[int]string, error) {
+	rows, err := r.DB.Raw(query.ListReadyImageFiles, updatedBefore).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	originals := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var url string
+		if err := rows.Scan(&id, &url); err != nil {
+			return nil, err
+		}
+		originals[id] = url
+	}
+
+	return originals, rows.Err()
+}
+
+// SetStatus - altera a situação de processamento da imagem
+func (r *ImageRepository) SetStatus(id int, status string) error {
+	return r.DB.Exec(query.SetImageStatus, id, status).Error
+}
+
+// DeleteRenditionsByKey - remove as versões que apontam para o arquivo informado
+func (r *ImageRepository) DeleteRenditionsByKey(storageKey string) error {
+	return r.DB.Exec(query.DeleteRenditionsByKey, storageKey).Error
+}
+
+// listStrings - executa uma consulta que retorna uma única coluna de texto, ignorando valores nulos
+func (r *ImageRepository) listStrings(sql string, values ...interface{}) ([]string, error) {
+	rows, err := r.DB.Raw(sql, values...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var value *string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		if value != nil {
+			result = append(result, *value)
+		}
+	}
+
+	return result, rows.Err()
+}
+
+// IsPrivateKey - indica se o arquivo pertence apenas a imagens privadas. Arquivos compartilhados com alguma imagem
+// pública, por deduplicação, continuam públicos.
+func (r *ImageRepository) IsPrivateKey(key string) (bool, error) {
+	var private bool
+	err := r.DB.Raw(query.IsPrivateMediaKey, key).Row().Scan(&private)
+	return private, err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jampa_trip/internal/model"
+	"gorm.io/gorm"
+)
+
+// LedgerRepository - objeto de contexto
+type LedgerRepository struct {
+	DB *gorm.DB
+}
+
+// LedgerRepositoryNew - construtor do objeto
+func LedgerRepositoryNew(DB *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{
+		DB: DB,
+	}
+}
+
+// LedgerTotals - representa a soma dos lançamentos de um tipo
+type LedgerTotals struct {
+	Type        string  `gorm:"column:type"`
+	GrossAmount float64 `gorm:"column:gross_amount"`
+	FeeAmount   float64 `gorm:"column:fee_amount"`
+	NetAmount   float64 `gorm:"column:net_amount"`
+}
+
+// Create - cria um novo lançamento junto com suas partidas na mesma transação
+func (r *LedgerRepository) Create(entry *model.LedgerEntry) error {
+	return r.DB.Create(entry).Error
+}
+
+// ExistsForPayment - verifica se já existe lançamento do tipo informado para o pagamento
+func (r *LedgerRepository) ExistsForPayment(pagamentoID int, entryType string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&model.LedgerEntry{}).
+		Where("pagamento_id = ? AND type = ?", pagamentoID, entryType).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetForPayment - busca o lançamento do tipo informado para o pagamento
+func (r *LedgerRepository) GetForPayment(pagamentoID int, entryType string) (*model.LedgerEntry, error) {
+	entry := &model.LedgerEntry{}
+	err := r.DB.Where("pagamento_id = ? AND type = ?", pagamentoID, entryType).First(entry).Error
+	return entry, err
+}
+
+// SumGrossForPayment - soma o valor bruto dos lançamentos do tipo informado para o pagamento
+func (r *LedgerRepository) SumGrossForPayment(pagamentoID int, entryType string) (float64, error) {
+	var total float64
+	err := r.DB.Model(&model.LedgerEntry{}).
+		Select("COALESCE(SUM(gross_amount), 0)").
+		Where("pagamento_id = ? AND type = ?", pagamentoID, entryType).
+		Scan(&total).Error
+	return total, err
+}
+
+// GetByCompanyIDAndPeriod - lista os lançamentos de uma empresa no intervalo [start, end)
+func (r *LedgerRepository) GetByCompanyIDAndPeriod(companyID int, start, end time.Time) ([]model.LedgerEntry, error) {
+	var entries []model.LedgerEntry
+	err := r.DB.Where("company_id = ? AND created_at >= ? AND created_at < ?", companyID, start, end).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// TotalsByType - soma os lançamentos de uma empresa agrupados por tipo
+func (r *LedgerRepository) TotalsByType(companyID int) ([]LedgerTotals, error) {
+	var totals []LedgerTotals
+	err := r.DB.Model(&model.LedgerEntry{}).
+		Select("type, COALESCE(SUM(gross_amount), 0) AS gross_amount, COALESCE(SUM(fee_amount), 0) AS fee_amount, COALESCE(SUM(net_amount), 0) AS net_amount").
+		Where("company_id = ?", companyID).
+		Group("type").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// PayableBalance - calcula o saldo devido à empresa (créditos menos débitos) até o instante informado
+func (r *LedgerRepository) PayableBalance(companyID int, before time.Time) (float64, error) {
+	var balance float64
+	err := r.DB.Model(&model.LedgerPosting{}).
+		Select("COALESCE(SUM(CASE WHEN direction = ? THEN amount ELSE -amount END), 0)", model.PostingCredit).
+		Where("company_id = ? AND account = ? AND created_at < ?", companyID, model.AccountCompanyPayable, before).
+		Scan(&balance).Error
+	return balance, err
+}
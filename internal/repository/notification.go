@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jampa_trip/internal/model"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository - objeto de contexto
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+// NotificationRepositoryNew - construtor do objeto
+func NotificationRepositoryNew(DB *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{
+		DB: DB,
+	}
+}
+
+// Create - cria uma nova notificação
+func (r *NotificationRepository) Create(notification *model.Notification) error {
+	return r.DB.Create(notification).Error
+}
+
+// ListByCompanyID - lista as notificações de uma empresa, mais recentes primeiro
+func (r *NotificationRepository) ListByCompanyID(companyID int, unreadOnly bool, limit int) ([]model.Notification, error) {
+	var notifications []model.Notification
+	query := r.DB.Where("company_id = ?", companyID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkAsRead - marca uma notificação da empresa como lida, preservando a primeira leitura
+func (r *NotificationRepository) MarkAsRead(id, companyID int, readAt time.Time) (bool, error) {
+	result := r.DB.Model(&model.Notification{}).
+		Where("id = ? AND company_id = ?", id, companyID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", readAt))
+	return result.RowsAffected > 0, result.Error
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/jampa_trip/internal/model"
 	"gorm.io/gorm"
 )
@@ -27,6 +29,16 @@ func (r *PagamentoRepository) Update(pagamento *model.Pagamento) error {
 	return r.DB.Save(pagamento).Error
 }
 
+// GetByID - busca um pagamento pelo ID
+func (r *PagamentoRepository) GetByID(id int) (*model.Pagamento, error) {
+	var pagamento model.Pagamento
+	err := r.DB.First(&pagamento, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pagamento, nil
+}
+
 // GetByMercadoPagoPaymentID - busca um pagamento pelo ID do Mercado Pago
 func (r *PagamentoRepository) GetByMercadoPagoPaymentID(paymentID string) (*model.Pagamento, error) {
 	var pagamento model.Pagamento
@@ -37,6 +49,16 @@ func (r *PagamentoRepository) GetByMercadoPagoPaymentID(paymentID string) (*mode
 	return &pagamento, nil
 }
 
+// GetByMercadoPagoOrderID - busca o pagamento vinculado a uma order do Mercado Pago
+func (r *PagamentoRepository) GetByMercadoPagoOrderID(orderID string) (*model.Pagamento, error) {
+	var pagamento model.Pagamento
+	err := r.DB.Where("mercado_pago_order_id = ?", orderID).First(&pagamento).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pagamento, nil
+}
+
 // GetByClienteID - lista pagamentos de um cliente
 func (r *PagamentoRepository) GetByClienteID(clienteID int) ([]model.Pagamento, error) {
 	var pagamentos []model.Pagamento
@@ -50,3 +72,46 @@ func (r *PagamentoRepository) GetByEmpresaID(empresaID int) ([]model.Pagamento,
 	err := r.DB.Where("empresa_id = ?", empresaID).Order("momento_criacao DESC").Find(&pagamentos).Error
 	return pagamentos, err
 }
+
+// ListForReconciliation - lista, em ordem de ID a partir de afterID, uma página dos pagamentos nos status informados
+// criados antes do instante de corte
+func (r *PagamentoRepository) ListForReconciliation(statuses []string, createdBefore time.Time, afterID, limit int) ([]model.Pagamento, error) {
+	var pagamentos []model.Pagamento
+	err := r.DB.Where("status IN ? AND momento_criacao < ? AND mercado_pago_payment_id <> '' AND id > ?", statuses, createdBefore, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&pagamentos).Error
+	return pagamentos, err
+}
+
+// ListExpiredPIX - lista pagamentos PIX ainda pendentes cuja expiração já passou
+func (r *PagamentoRepository) ListExpiredPIX(now time.Time, limit int) ([]model.Pagamento, error) {
+	var pagamentos []model.Pagamento
+	err := r.DB.Where("metodo_pagamento = ? AND status IN ? AND expira_em IS NOT NULL AND expira_em < ?",
+		string(model.MetodoPIX), []string{string(model.StatusPending), string(model.StatusInProcess)}, now).
+		Order("expira_em ASC").
+		Limit(limit).
+		Find(&pagamentos).Error
+	return pagamentos, err
+}
+
+// ListInvoicesByEmpresaID - lista os pagamentos aprovados de uma empresa no período, incluindo os estornados depois da aprovação
+func (r *PagamentoRepository) ListInvoicesByEmpresaID(empresaID int, start, end time.Time, page, limit int) ([]model.Pagamento, int64, error) {
+	var pagamentos []model.Pagamento
+	var total int64
+
+	query := r.DB.Model(&model.Pagamento{}).
+		Where("empresa_id = ? AND momento_aprovacao IS NOT NULL AND momento_aprovacao >= ? AND momento_aprovacao < ?", empresaID, start, end)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Cliente").
+		Order("momento_aprovacao DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&pagamentos).Error
+
+	return pagamentos, total, err
+}
@@ -34,6 +34,13 @@ func (r *ReservaRepository) GetByID(id int) (*model.Reserva, error) {
 	return &reserva, nil
 }
 
+// ListByPagamentoID - busca todas as reservas pagas por um mesmo pagamento (carrinho)
+func (r *ReservaRepository) ListByPagamentoID(pagamentoID int) ([]model.Reserva, error) {
+	var reservas []model.Reserva
+	err := r.DB.Where("pagamento_id = ?", pagamentoID).Order("id").Find(&reservas).Error
+	return reservas, err
+}
+
 // GetByClienteID - busca reservas por cliente
 func (r *ReservaRepository) GetByClienteID(clienteID int, page, limit int) ([]model.Reserva, int64, error) {
 	var reservas []model.Reserva
@@ -117,6 +124,15 @@ func (r *ReservaRepository) UpdateStatus(id int, status string) error {
 		}).Error
 }
 
+// LinkPayment - vincula o pagamento à reserva
+func (r *ReservaRepository) LinkPayment(id, pagamentoID int) error {
+	return r.DB.Model(&model.Reserva{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"pagamento_id":        pagamentoID,
+			"momento_atualizacao": time.Now(),
+		}).Error
+}
+
 // Cancel - cancela uma reserva
 func (r *ReservaRepository) Cancel(id int) error {
 	now := time.Now()
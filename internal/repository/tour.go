@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/jampa_trip/internal/model"
 	"github.com/jampa_trip/internal/query"
 	"github.com/lib/pq"
@@ -21,7 +23,12 @@ func TourRepositoryNew(DB *gorm.DB) *TourRepository {
 
 // Create - cria um novo passeio
 func (r *TourRepository) Create(tour *model.Tour) error {
-	err := r.DB.Raw(query.CreateTour,
+	return createTour(r.DB, tour)
+}
+
+// createTour - grava o passeio usando a conexão ou transação informada
+func createTour(db *gorm.DB, tour *model.Tour) error {
+	err := db.Raw(query.CreateTour,
 		tour.CompanyID,
 		tour.Name,
 		pq.Array(tour.Dates),
@@ -29,7 +36,6 @@ func (r *TourRepository) Create(tour *model.Tour) error {
 		tour.ArrivalTime,
 		tour.MaxPeople,
 		tour.Description,
-		pq.Array(tour.Images),
 		tour.Price,
 	).Row().Scan(&tour.ID)
 
@@ -38,19 +44,88 @@ func (r *TourRepository) Create(tour *model.Tour) error {
 
 // Update - atualiza um passeio existente
 func (r *TourRepository) Update(tour *model.Tour) error {
-	err := r.DB.Raw(query.UpdateTour,
+	return updateTour(r.DB, tour)
+}
+
+// updateTour - atualiza o passeio usando a conexão ou transação informada
+func updateTour(db *gorm.DB, tour *model.Tour) error {
+	return db.Exec(query.UpdateTour,
 		tour.Name,
 		pq.Array(tour.Dates),
 		tour.DepartureTime,
 		tour.ArrivalTime,
 		tour.MaxPeople,
 		tour.Description,
-		pq.Array(tour.Images),
 		tour.Price,
 		tour.ID,
-	).Row().Scan()
+	).Error
+}
 
-	return err
+// CreateWithImages - cria o passeio e vincula as imagens da empresa na mesma transação. Retorna false, sem gravar
+// nada, quando alguma imagem não pertence à empresa ou já está em outro passeio.
+func (r *TourRepository) CreateWithImages(tour *model.Tour, imageIDs []int) (bool, error) {
+	return r.withImages(func(tx *gorm.DB) error {
+		if err := createTour(tx, tour); err != nil {
+			return err
+		}
+		return attachTourImages(tx, tour, imageIDs)
+	})
+}
+
+// UpdateWithImages - atualiza o passeio e substitui suas imagens na mesma transação; com imageIDs nil as imagens
+// atuais são mantidas. Retorna false, sem gravar nada, quando alguma imagem não pertence à empresa ou já está em
+// outro passeio.
+func (r *TourRepository) UpdateWithImages(tour *model.Tour, imageIDs []int) (bool, error) {
+	return r.withImages(func(tx *gorm.DB) error {
+		if err := updateTour(tx, tour); err != nil {
+			return err
+		}
+		if imageIDs == nil {
+			return nil
+		}
+		return attachTourImages(tx, tour, imageIDs)
+	})
+}
+
+// errTourImagesUnavailable - desfaz a transação quando alguma imagem não pode ser vinculada ao passeio
+var errTourImagesUnavailable = errors.New("imagens indisponíveis para o passeio")
+
+// withImages - executa a gravação do passeio em uma transação, convertendo a falha ao vincular as imagens em false
+func (r *TourRepository) withImages(save func(tx *gorm.DB) error) (bool, error) {
+	err := r.DB.Transaction(save)
+	if errors.Is(err, errTourImagesUnavailable) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// attachTourImages - desvincula do passeio as imagens fora da lista e vincula as informadas na ordem recebida,
+// com a primeira como principal
+func attachTourImages(tx *gorm.DB, tour *model.Tour, imageIDs []int) error {
+	ids := pq.Array(imageIDs)
+
+	if err := tx.Exec(query.DetachTourImages, tour.ID, ids).Error; err != nil {
+		return err
+	}
+
+	if len(imageIDs) == 0 {
+		return nil
+	}
+
+	result := tx.Exec(query.AttachTourImages, tour.ID, ids, ids, tour.CompanyID, tour.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(imageIDs)) {
+		return errTourImagesUnavailable
+	}
+
+	// a principal é marcada depois das demais para que o gatilho de imagem principal única não altere linhas
+	// ainda pendentes no comando anterior
+	return tx.Exec(query.SetTourPrimaryImage, imageIDs[0], tour.ID).Error
 }
 
 // GetByID - busca um passeio pelo ID
@@ -62,12 +137,11 @@ func (r *TourRepository) GetByID(id int) (*model.Tour, error) {
 		&tour.ID,
 		&tour.CompanyID,
 		&tour.Name,
-		pq.Array(&tour.Dates),
+		&tour.Dates,
 		&tour.DepartureTime,
 		&tour.ArrivalTime,
 		&tour.MaxPeople,
 		&tour.Description,
-		pq.Array(&tour.Images),
 		&tour.Price,
 		&tour.CreatedAt,
 		&tour.UpdatedAt,
@@ -101,12 +175,11 @@ func (r *TourRepository) List(search string, page, limit int) ([]*model.Tour, in
 			&tour.ID,
 			&tour.CompanyID,
 			&tour.Name,
-			pq.Array(&tour.Dates),
+			&tour.Dates,
 			&tour.DepartureTime,
 			&tour.ArrivalTime,
 			&tour.MaxPeople,
 			&tour.Description,
-			pq.Array(&tour.Images),
 			&tour.Price,
 			&tour.CreatedAt,
 			&tour.UpdatedAt,
@@ -146,12 +219,11 @@ func (r *TourRepository) ListByCompanyID(companyID int, page, limit int) ([]*mod
 			&tour.ID,
 			&tour.CompanyID,
 			&tour.Name,
-			pq.Array(&tour.Dates),
+			&tour.Dates,
 			&tour.DepartureTime,
 			&tour.ArrivalTime,
 			&tour.MaxPeople,
 			&tour.Description,
-			pq.Array(&tour.Images),
 			&tour.Price,
 			&tour.CreatedAt,
 			&reservationsCount,
@@ -206,12 +278,11 @@ func (r *TourRepository) GetTourWithCompanyName(id int) (*model.Tour, string, er
 		&tour.ID,
 		&tour.CompanyID,
 		&tour.Name,
-		pq.Array(&tour.Dates),
+		&tour.Dates,
 		&tour.DepartureTime,
 		&tour.ArrivalTime,
 		&tour.MaxPeople,
 		&tour.Description,
-		pq.Array(&tour.Images),
 		&tour.Price,
 		&tour.CreatedAt,
 		&tour.UpdatedAt,
@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/internal/repository"
 	"github.com/jampa_trip/pkg/config"
 	"github.com/jampa_trip/pkg/mercadopago"
 	"github.com/jampa_trip/pkg/util"
@@ -12,23 +17,29 @@ import (
 )
 
 type CartaoService struct {
-	db     *gorm.DB
-	client *mercadopago.Client
+	db               *gorm.DB
+	client           *mercadopago.Client
+	clientRepository *repository.ClientRepository
 }
 
 // CartaoServiceNew - cria uma nova instância do service de cartão
 func CartaoServiceNew(db *gorm.DB) *CartaoService {
 	cfg, _ := config.LoadConfig()
-	client := mercadopago.NewClient(cfg.MercadoPagoAccessToken, cfg.MercadoPagoBaseURL)
 
 	return &CartaoService{
-		db:     db,
-		client: client,
+		db:               db,
+		client:           newMercadoPagoClient(cfg),
+		clientRepository: repository.ClientRepositoryNew(db),
 	}
 }
 
-// Create - cria um cartão para um cliente
-func (s *CartaoService) Create(ctx context.Context, customerID string, req *contract.CreateCartaoRequest) (*contract.CreateCartaoResponse, error) {
+// Create - salva um cartão para o cliente, definindo-o como padrão quando solicitado ou quando for o primeiro
+func (s *CartaoService) Create(ctx context.Context, clienteID int, req *contract.CreateCartaoRequest) (*contract.CreateCartaoResponse, error) {
+
+	customer, err := s.ensureCustomer(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
 
 	mpReq := &mercadopago.CustomerCardRequest{
 		Token:           req.Token,
@@ -44,90 +55,43 @@ func (s *CartaoService) Create(ctx context.Context, customerID string, req *cont
 		Metadata: req.Metadata,
 	}
 
-	mpResp, err := s.client.CreateCustomerCard(ctx, customerID, mpReq)
+	mpResp, err := s.client.CreateCustomerCard(ctx, customer.ID, mpReq)
 	if err != nil {
 		return nil, util.WrapError("erro ao criar cartão no Mercado Pago", err, http.StatusInternalServerError)
 	}
 
+	defaultCard := customer.DefaultCard
+	if req.Default || defaultCard == "" {
+		if _, err := s.client.UpdateCustomer(ctx, customer.ID, &mercadopago.CustomerRequest{DefaultCard: mpResp.ID}); err != nil {
+			return nil, util.WrapError("erro ao definir cartão padrão no Mercado Pago", err, http.StatusInternalServerError)
+		}
+		defaultCard = mpResp.ID
+	}
+
 	response := &contract.CreateCartaoResponse{
-		Cartao: contract.CartaoResponse{
-			ID:              mpResp.ID,
-			CustomerID:      mpResp.CustomerID,
-			FirstSixDigits:  mpResp.FirstSixDigits,
-			LastFourDigits:  mpResp.LastFourDigits,
-			ExpirationMonth: mpResp.ExpirationMonth,
-			ExpirationYear:  mpResp.ExpirationYear,
-			SecurityCode: contract.SecurityCodeInfo{
-				Length:       mpResp.SecurityCode.Length,
-				CardLocation: mpResp.SecurityCode.CardLocation,
-				Mode:         mpResp.SecurityCode.Mode,
-			},
-			Issuer: contract.IssuerInfo{
-				ID:   mpResp.Issuer.ID,
-				Name: mpResp.Issuer.Name,
-			},
-			PaymentMethod: contract.CartaoPaymentMethodInfo{
-				ID:   mpResp.PaymentMethod.ID,
-				Name: mpResp.PaymentMethod.Name,
-			},
-			Cardholder: contract.CardholderResponse{
-				Name: mpResp.Cardholder.Name,
-				Identification: contract.IdentificationResponse{
-					Type:   mpResp.Cardholder.Identification.Type,
-					Number: mpResp.Cardholder.Identification.Number,
-				},
-			},
-			DateCreated:     mpResp.DateCreated,
-			DateLastUpdated: mpResp.DateLastUpdated,
-			Metadata:        mpResp.Metadata,
-		},
+		Cartao:  cardToResponse(mpResp, defaultCard),
 		Message: "Cartão criado com sucesso",
 	}
 
 	return response, nil
 }
 
-// List - lista os cartões de um cliente
-func (s *CartaoService) List(ctx context.Context, customerID string) (*contract.ListCartoesResponse, error) {
+// List - lista os cartões do cliente
+func (s *CartaoService) List(ctx context.Context, clienteID int) (*contract.ListCartoesResponse, error) {
 
-	mpCards, err := s.client.ListCustomerCards(ctx, customerID)
+	customer, err := s.ensureCustomer(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	mpCards, err := s.client.ListCustomerCards(ctx, customer.ID)
 	if err != nil {
 		return nil, util.WrapError("erro ao listar cartões no Mercado Pago", err, http.StatusInternalServerError)
 	}
 
 	cartoes := make([]contract.CartaoResponse, len(mpCards))
-	for i, mpCard := range mpCards {
-		cartoes[i] = contract.CartaoResponse{
-			ID:              mpCard.ID,
-			CustomerID:      mpCard.CustomerID,
-			FirstSixDigits:  mpCard.FirstSixDigits,
-			LastFourDigits:  mpCard.LastFourDigits,
-			ExpirationMonth: mpCard.ExpirationMonth,
-			ExpirationYear:  mpCard.ExpirationYear,
-			SecurityCode: contract.SecurityCodeInfo{
-				Length:       mpCard.SecurityCode.Length,
-				CardLocation: mpCard.SecurityCode.CardLocation,
-				Mode:         mpCard.SecurityCode.Mode,
-			},
-			Issuer: contract.IssuerInfo{
-				ID:   mpCard.Issuer.ID,
-				Name: mpCard.Issuer.Name,
-			},
-			PaymentMethod: contract.CartaoPaymentMethodInfo{
-				ID:   mpCard.PaymentMethod.ID,
-				Name: mpCard.PaymentMethod.Name,
-			},
-			Cardholder: contract.CardholderResponse{
-				Name: mpCard.Cardholder.Name,
-				Identification: contract.IdentificationResponse{
-					Type:   mpCard.Cardholder.Identification.Type,
-					Number: mpCard.Cardholder.Identification.Number,
-				},
-			},
-			DateCreated:     mpCard.DateCreated,
-			DateLastUpdated: mpCard.DateLastUpdated,
-			Metadata:        mpCard.Metadata,
-		}
+	for i := range mpCards {
+		cartoes[i] = cardToResponse(&mpCards[i], customer.DefaultCard)
 	}
 
 	response := &contract.ListCartoesResponse{
@@ -138,51 +102,31 @@ func (s *CartaoService) List(ctx context.Context, customerID string) (*contract.
 	return response, nil
 }
 
-// Get - obtém um cartão específico de um cliente
-func (s *CartaoService) Get(ctx context.Context, customerID, cardID string) (*contract.CartaoResponse, error) {
+// Get - obtém um cartão específico do cliente
+func (s *CartaoService) Get(ctx context.Context, clienteID int, cardID string) (*contract.CartaoResponse, error) {
 
-	mpCard, err := s.client.GetCustomerCard(ctx, customerID, cardID)
+	customer, err := s.ensureCustomer(ctx, clienteID)
 	if err != nil {
-		return nil, util.WrapError("erro ao obter cartão no Mercado Pago", err, http.StatusInternalServerError)
+		return nil, err
 	}
 
-	response := &contract.CartaoResponse{
-		ID:              mpCard.ID,
-		CustomerID:      mpCard.CustomerID,
-		FirstSixDigits:  mpCard.FirstSixDigits,
-		LastFourDigits:  mpCard.LastFourDigits,
-		ExpirationMonth: mpCard.ExpirationMonth,
-		ExpirationYear:  mpCard.ExpirationYear,
-		SecurityCode: contract.SecurityCodeInfo{
-			Length:       mpCard.SecurityCode.Length,
-			CardLocation: mpCard.SecurityCode.CardLocation,
-			Mode:         mpCard.SecurityCode.Mode,
-		},
-		Issuer: contract.IssuerInfo{
-			ID:   mpCard.Issuer.ID,
-			Name: mpCard.Issuer.Name,
-		},
-		PaymentMethod: contract.CartaoPaymentMethodInfo{
-			ID:   mpCard.PaymentMethod.ID,
-			Name: mpCard.PaymentMethod.Name,
-		},
-		Cardholder: contract.CardholderResponse{
-			Name: mpCard.Cardholder.Name,
-			Identification: contract.IdentificationResponse{
-				Type:   mpCard.Cardholder.Identification.Type,
-				Number: mpCard.Cardholder.Identification.Number,
-			},
-		},
-		DateCreated:     mpCard.DateCreated,
-		DateLastUpdated: mpCard.DateLastUpdated,
-		Metadata:        mpCard.Metadata,
+	mpCard, err := s.client.GetCustomerCard(ctx, customer.ID, cardID)
+	if err != nil {
+		return nil, util.WrapError("erro ao obter cartão no Mercado Pago", err, http.StatusInternalServerError)
 	}
 
-	return response, nil
+	response := cardToResponse(mpCard, customer.DefaultCard)
+
+	return &response, nil
 }
 
-// Update - atualiza um cartão de um cliente
-func (s *CartaoService) Update(ctx context.Context, customerID, cardID string, req *contract.UpdateCartaoRequest) (*contract.UpdateCartaoResponse, error) {
+// Update - atualiza um cartão do cliente
+func (s *CartaoService) Update(ctx context.Context, clienteID int, cardID string, req *contract.UpdateCartaoRequest) (*contract.UpdateCartaoResponse, error) {
+
+	customer, err := s.ensureCustomer(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
 
 	mpReq := &mercadopago.CustomerCardUpdateRequest{
 		Cardholder: mercadopago.CustomerCardholder{
@@ -196,53 +140,61 @@ func (s *CartaoService) Update(ctx context.Context, customerID, cardID string, r
 		Default:  req.Default,
 	}
 
-	mpResp, err := s.client.UpdateCustomerCard(ctx, customerID, cardID, mpReq)
+	mpResp, err := s.client.UpdateCustomerCard(ctx, customer.ID, cardID, mpReq)
 	if err != nil {
 		return nil, util.WrapError("erro ao atualizar cartão no Mercado Pago", err, http.StatusInternalServerError)
 	}
 
+	defaultCard := customer.DefaultCard
+	if req.Default && defaultCard != cardID {
+		if _, err := s.client.UpdateCustomer(ctx, customer.ID, &mercadopago.CustomerRequest{DefaultCard: cardID}); err != nil {
+			return nil, util.WrapError("erro ao definir cartão padrão no Mercado Pago", err, http.StatusInternalServerError)
+		}
+		defaultCard = cardID
+	}
+
 	response := &contract.UpdateCartaoResponse{
-		Cartao: contract.CartaoResponse{
-			ID:              mpResp.ID,
-			CustomerID:      mpResp.CustomerID,
-			FirstSixDigits:  mpResp.FirstSixDigits,
-			LastFourDigits:  mpResp.LastFourDigits,
-			ExpirationMonth: mpResp.ExpirationMonth,
-			ExpirationYear:  mpResp.ExpirationYear,
-			SecurityCode: contract.SecurityCodeInfo{
-				Length:       mpResp.SecurityCode.Length,
-				CardLocation: mpResp.SecurityCode.CardLocation,
-				Mode:         mpResp.SecurityCode.Mode,
-			},
-			Issuer: contract.IssuerInfo{
-				ID:   mpResp.Issuer.ID,
-				Name: mpResp.Issuer.Name,
-			},
-			PaymentMethod: contract.CartaoPaymentMethodInfo{
-				ID:   mpResp.PaymentMethod.ID,
-				Name: mpResp.PaymentMethod.Name,
-			},
-			Cardholder: contract.CardholderResponse{
-				Name: mpResp.Cardholder.Name,
-				Identification: contract.IdentificationResponse{
-					Type:   mpResp.Cardholder.Identification.Type,
-					Number: mpResp.Cardholder.Identification.Number,
-				},
-			},
-			DateCreated:     mpResp.DateCreated,
-			DateLastUpdated: mpResp.DateLastUpdated,
-			Metadata:        mpResp.Metadata,
-		},
+		Cartao:  cardToResponse(mpResp, defaultCard),
 		Message: "Cartão atualizado com sucesso",
 	}
 
 	return response, nil
 }
 
-// Delete - exclui um cartão de um cliente
-func (s *CartaoService) Delete(ctx context.Context, customerID, cardID string) (*contract.DeleteCartaoResponse, error) {
+// SetDefault - define o cartão padrão usado nos pagamentos com um clique
+func (s *CartaoService) SetDefault(ctx context.Context, clienteID int, cardID string) (*contract.UpdateCartaoResponse, error) {
+
+	customer, err := s.ensureCustomer(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	mpCard, err := s.client.GetCustomerCard(ctx, customer.ID, cardID)
+	if err != nil {
+		return nil, util.WrapError("cartão não encontrado", err, http.StatusNotFound)
+	}
+
+	if _, err := s.client.UpdateCustomer(ctx, customer.ID, &mercadopago.CustomerRequest{DefaultCard: cardID}); err != nil {
+		return nil, util.WrapError("erro ao definir cartão padrão no Mercado Pago", err, http.StatusInternalServerError)
+	}
+
+	response := &contract.UpdateCartaoResponse{
+		Cartao:  cardToResponse(mpCard, cardID),
+		Message: "Cartão padrão atualizado com sucesso",
+	}
+
+	return response, nil
+}
+
+// Delete - exclui um cartão do cliente
+func (s *CartaoService) Delete(ctx context.Context, clienteID int, cardID string) (*contract.DeleteCartaoResponse, error) {
 
-	err := s.client.DeleteCustomerCard(ctx, customerID, cardID)
+	customer, err := s.ensureCustomer(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.client.DeleteCustomerCard(ctx, customer.ID, cardID)
 	if err != nil {
 		return nil, util.WrapError("erro ao excluir cartão no Mercado Pago", err, http.StatusInternalServerError)
 	}
@@ -253,3 +205,138 @@ func (s *CartaoService) Delete(ctx context.Context, customerID, cardID string) (
 
 	return response, nil
 }
+
+// ResolveCard - retorna o customer e o cartão salvo usado no pagamento; sem card_id utiliza o cartão padrão
+func (s *CartaoService) ResolveCard(ctx context.Context, clienteID int, cardID string) (*mercadopago.CustomerResponse, *mercadopago.CustomerCardResponse, error) {
+
+	customer, err := s.ensureCustomer(ctx, clienteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cardID == "" {
+		cardID = customer.DefaultCard
+	}
+
+	if cardID == "" {
+		return nil, nil, util.WrapError("cliente não possui cartão padrão", nil, http.StatusUnprocessableEntity)
+	}
+
+	card, err := s.client.GetCustomerCard(ctx, customer.ID, cardID)
+	if err != nil {
+		return nil, nil, util.WrapError("cartão salvo não encontrado", err, http.StatusNotFound)
+	}
+
+	return customer, card, nil
+}
+
+// ensureCustomer - retorna o customer do Mercado Pago do cliente, criando-o no primeiro uso
+func (s *CartaoService) ensureCustomer(ctx context.Context, clienteID int) (*mercadopago.CustomerResponse, error) {
+
+	customerID, err := s.clientRepository.GetMercadoPagoCustomerID(clienteID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, util.WrapError("cliente não encontrado", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao buscar cliente", err, http.StatusInternalServerError)
+	}
+
+	if customerID != "" {
+		customer, err := s.client.GetCustomer(ctx, customerID)
+		if err != nil {
+			return nil, util.WrapError("erro ao obter customer no Mercado Pago", err, http.StatusBadGateway)
+		}
+		return customer, nil
+	}
+
+	client, err := s.clientRepository.GetByID(clienteID)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar cliente", err, http.StatusInternalServerError)
+	}
+
+	// o Mercado Pago não aceita dois customers com o mesmo email
+	customer, err := s.client.SearchCustomerByEmail(ctx, client.Email)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar customer no Mercado Pago", err, http.StatusBadGateway)
+	}
+
+	// só adota o customer existente quando ele foi criado para este cliente ou tem o mesmo CPF
+	if customer != nil && !customerBelongsTo(customer, client) {
+		return nil, util.WrapError("o email do cliente já está vinculado a outro customer no Mercado Pago", nil, http.StatusConflict)
+	}
+
+	if customer == nil {
+		firstName, lastName, _ := strings.Cut(strings.TrimSpace(client.Name), " ")
+		customer, err = s.client.CreateCustomer(ctx, &mercadopago.CustomerRequest{
+			Email:     client.Email,
+			FirstName: firstName,
+			LastName:  lastName,
+			Identification: &mercadopago.CustomerIdentification{
+				Type:   "CPF",
+				Number: util.SomenteDigitos(client.CPF),
+			},
+			Description: customerDescription(client.ID),
+		})
+		if err != nil {
+			return nil, util.WrapError("erro ao criar customer no Mercado Pago", err, http.StatusBadGateway)
+		}
+	}
+
+	if err := s.clientRepository.SetMercadoPagoCustomerID(clienteID, customer.ID); err != nil {
+		return nil, util.WrapError("erro ao salvar customer do cliente", err, http.StatusInternalServerError)
+	}
+
+	return customer, nil
+}
+
+// customerDescription - identifica no Mercado Pago o customer criado para o cliente
+func customerDescription(clienteID int) string {
+	return fmt.Sprintf("jampa_trip cliente %d", clienteID)
+}
+
+// customerBelongsTo - verifica se o customer encontrado pelo email foi criado para o cliente ou tem o CPF dele
+func customerBelongsTo(customer *mercadopago.CustomerResponse, client *model.Client) bool {
+
+	if customer.Description == customerDescription(client.ID) {
+		return true
+	}
+
+	cpf := util.SomenteDigitos(client.CPF)
+	return cpf != "" && customer.Identification.Type == "CPF" && util.SomenteDigitos(customer.Identification.Number) == cpf
+}
+
+// cardToResponse - converte o cartão do Mercado Pago para a resposta da API
+func cardToResponse(mpCard *mercadopago.CustomerCardResponse, defaultCard string) contract.CartaoResponse {
+	return contract.CartaoResponse{
+		ID:              mpCard.ID,
+		CustomerID:      mpCard.CustomerID,
+		FirstSixDigits:  mpCard.FirstSixDigits,
+		LastFourDigits:  mpCard.LastFourDigits,
+		ExpirationMonth: mpCard.ExpirationMonth,
+		ExpirationYear:  mpCard.ExpirationYear,
+		SecurityCode: contract.SecurityCodeInfo{
+			Length:       mpCard.SecurityCode.Length,
+			CardLocation: mpCard.SecurityCode.CardLocation,
+			Mode:         mpCard.SecurityCode.Mode,
+		},
+		Issuer: contract.IssuerInfo{
+			ID:   mpCard.Issuer.ID,
+			Name: mpCard.Issuer.Name,
+		},
+		PaymentMethod: contract.CartaoPaymentMethodInfo{
+			ID:   mpCard.PaymentMethod.ID,
+			Name: mpCard.PaymentMethod.Name,
+		},
+		Cardholder: contract.CardholderResponse{
+			Name: mpCard.Cardholder.Name,
+			Identification: contract.IdentificationResponse{
+				Type:   mpCard.Cardholder.Identification.Type,
+				Number: mpCard.Cardholder.Identification.Number,
+			},
+		},
+		DateCreated:     mpCard.DateCreated,
+		DateLastUpdated: mpCard.DateLastUpdated,
+		Metadata:        mpCard.Metadata,
+		IsDefault:       mpCard.ID != "" && mpCard.ID == defaultCard,
+	}
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/pkg/mercadopago"
+	"github.com/jampa_trip/pkg/util"
+	"gorm.io/gorm"
+)
+
+// Checkout - cria uma order no Mercado Pago com as reservas do carrinho e registra o pagamento.
+// Todas as reservas devem ser da mesma empresa, pois o split do marketplace é feito por recebedor.
+func (s *PagamentoService) Checkout(ctx context.Context, req *contract.CheckoutRequest) (*contract.CheckoutResponse, error) {
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	lines := make([]*reservationLine, 0, len(req.ReservaIDs))
+	seen := map[int]bool{}
+	total := 0.0
+
+	for _, reservaID := range req.ReservaIDs {
+		if seen[reservaID] {
+			return nil, util.WrapError(fmt.Sprintf("reserva %d informada mais de uma vez", reservaID), nil, http.StatusUnprocessableEntity)
+		}
+		seen[reservaID] = true
+
+		line, err := s.reservationCharge(req.ClienteID, reservaID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(lines) > 0 && line.Reserva.EmpresaID != lines[0].Reserva.EmpresaID {
+			return nil, util.WrapError("todas as reservas do carrinho devem ser da mesma empresa", nil, http.StatusUnprocessableEntity)
+		}
+
+		lines = append(lines, line)
+		total += line.Amount
+	}
+
+	total = model.RoundCents(total)
+	empresaID := lines[0].Reserva.EmpresaID
+
+	split, err := s.prepareSplit(empresaID, total)
+	if err != nil {
+		return nil, err
+	}
+
+	orderReq := s.buildOrderRequest(req, lines, total)
+	if split.Enabled {
+		orderReq.MarketplaceFee = split.Fee
+	}
+
+	order, err := split.Client.CreateOrder(ctx, orderReq)
+	if err != nil {
+		return nil, mercadoPagoPaymentError(err)
+	}
+
+	now := time.Now()
+	payment := &model.Pagamento{
+		ClienteID:          req.ClienteID,
+		EmpresaID:          empresaID,
+		MercadoPagoOrderID: order.ID,
+		Status:             order.PaymentStatus(),
+		StatusDetail:       order.StatusDetail,
+		Valor:              total,
+		Moeda:              "BRL",
+		MetodoPagamento:    req.PaymentType,
+		Descricao:          orderReq.Description,
+		NumeroParcelas:     orderReq.Transactions.Payments[0].PaymentMethod.Installments,
+		PaymentMethodID:    orderReq.Transactions.Payments[0].PaymentMethod.ID,
+		TaxaPlataforma:     split.Fee,
+		SplitMarketplace:   split.Enabled,
+		MomentoCriacao:     now,
+		MomentoAtualizacao: now,
+	}
+
+	var orderPayment mercadopago.OrderPayment
+	if len(order.Transactions.Payments) > 0 {
+		orderPayment = order.Transactions.Payments[0]
+		payment.MercadoPagoPaymentID = orderPayment.ID
+		payment.QRCode = orderPayment.PaymentMethod.QRCode
+		if orderPayment.StatusDetail != "" {
+			payment.StatusDetail = orderPayment.StatusDetail
+		}
+	}
+
+	switch model.StatusPagamento(payment.Status) {
+	case model.StatusApproved:
+		payment.Captured = true
+		payment.MomentoAprovacao = &now
+		payment.MomentoCaptura = &now
+	case model.StatusAuthorized:
+		payment.MomentoAutorizacao = &now
+	}
+
+	if req.PaymentType == "pix" {
+		expiraEm := now.Add(s.PIXExpiration)
+		if orderPayment.DateOfExpiration != "" {
+			if parsed, err := time.Parse(mercadopago.DateTimeLayout, orderPayment.DateOfExpiration); err == nil {
+				expiraEm = parsed
+			}
+		}
+		payment.ExpiraEm = &expiraEm
+	}
+
+	if err := s.PagamentoRepository.Create(payment); err != nil {
+		return nil, util.WrapError("erro ao salvar pagamento", err, http.StatusInternalServerError)
+	}
+
+	items := make([]contract.CheckoutItemResponse, 0, len(lines))
+	for _, line := range lines {
+		if err := s.ReservaRepository.LinkPayment(line.Reserva.ID, payment.ID); err != nil {
+			log.Printf("erro ao vincular pagamento %d à reserva %d: %v", payment.ID, line.Reserva.ID, err)
+		}
+
+		items = append(items, contract.CheckoutItemResponse{
+			ReservaID:         line.Reserva.ID,
+			TourID:            line.Tour.ID,
+			TourName:          line.Tour.Name,
+			QuantidadePessoas: line.Reserva.QuantidadePessoas,
+			PrecoUnitario:     line.Tour.Price,
+			Valor:             line.Amount,
+		})
+	}
+
+	s.recordLedger(payment)
+
+	return &contract.CheckoutResponse{
+		OrderID:      order.ID,
+		OrderStatus:  order.Status,
+		Pagamento:    s.modelToResponse(payment),
+		Items:        items,
+		Message:      s.getStatusDetailMessage(payment.StatusDetail),
+		QRCode:       orderPayment.PaymentMethod.QRCode,
+		QRCodeBase64: orderPayment.PaymentMethod.QRCodeBase64,
+		TicketURL:    orderPayment.PaymentMethod.TicketURL,
+	}, nil
+}
+
+// buildOrderRequest - monta a order do Mercado Pago com uma linha por reserva do carrinho
+func (s *PagamentoService) buildOrderRequest(req *contract.CheckoutRequest, lines []*reservationLine, total float64) *mercadopago.OrderRequest {
+
+	reservaIDs := make([]string, 0, len(lines))
+	items := make([]mercadopago.OrderItem, 0, len(lines))
+	for _, line := range lines {
+		reservaIDs = append(reservaIDs, strconv.Itoa(line.Reserva.ID))
+		items = append(items, mercadopago.OrderItem{
+			ID:          fmt.Sprintf("reserva-%d", line.Reserva.ID),
+			Title:       line.Tour.Name,
+			Description: fmt.Sprintf("%s em %s", line.Tour.Name, line.Reserva.DataPasseio.Format("02/01/2006")),
+			CategoryID:  "travels",
+			Quantity:    line.Reserva.QuantidadePessoas,
+			CurrencyID:  "BRL",
+			UnitPrice:   line.Tour.Price,
+		})
+	}
+
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("Reservas %s", strings.Join(reservaIDs, ", "))
+	}
+
+	captureMode := "automatic_async"
+	if req.ManualCapture {
+		captureMode = "manual"
+	}
+
+	paymentMethod := mercadopago.OrderPaymentMethod{
+		ID:           req.PaymentMethodID,
+		Type:         req.PaymentType,
+		Token:        req.Token,
+		Installments: req.Installments,
+	}
+	expirationTime := ""
+	switch req.PaymentType {
+	case "pix":
+		paymentMethod = mercadopago.OrderPaymentMethod{ID: "pix", Type: "bank_transfer"}
+		expirationTime = mercadopago.ExpirationDuration(s.PIXExpiration)
+	case "debit_card":
+		paymentMethod.Installments = 1
+	default:
+		if paymentMethod.Installments == 0 {
+			paymentMethod.Installments = 1
+		}
+	}
+
+	return &mercadopago.OrderRequest{
+		Type:              "online",
+		ProcessingMode:    "automatic",
+		CaptureMode:       captureMode,
+		ExternalReference: fmt.Sprintf("checkout:%d:%s", req.ClienteID, strings.Join(reservaIDs, "-")),
+		TotalAmount:       total,
+		Items:             items,
+		Payer: mercadopago.Payer{
+			Name:  strings.TrimSpace(req.Payer.FirstName + " " + req.Payer.LastName),
+			Email: req.Payer.Email,
+		},
+		Description: description,
+		Metadata: map[string]string{
+			"cliente_id":  strconv.Itoa(req.ClienteID),
+			"empresa_id":  strconv.Itoa(lines[0].Reserva.EmpresaID),
+			"reserva_ids": strings.Join(reservaIDs, ","),
+		},
+		Transactions: &mercadopago.OrderTransactions{
+			Payments: []mercadopago.OrderPayment{
+				{Amount: total, ExpirationTime: expirationTime, PaymentMethod: paymentMethod},
+			},
+		},
+	}
+}
+
+// CaptureOrder - captura uma order autorizada com captura manual
+func (s *PagamentoService) CaptureOrder(ctx context.Context, req *contract.OrderOperationRequest) (*contract.OrderOperationResponse, error) {
+
+	payment, err := s.orderPayment(req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payment.IsAuthorized() {
+		return nil, util.WrapError("apenas orders autorizadas podem ser capturadas", nil, http.StatusConflict)
+	}
+
+	return s.applyOrderOperation(ctx, payment, s.clientForPayment(payment).CaptureOrder, "Order capturada com sucesso")
+}
+
+// CancelOrder - cancela uma order ainda não concluída
+func (s *PagamentoService) CancelOrder(ctx context.Context, req *contract.OrderOperationRequest) (*contract.OrderOperationResponse, error) {
+
+	payment, err := s.orderPayment(req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.IsFinal() {
+		return nil, util.WrapError("order já concluída não pode ser cancelada, utilize o reembolso", nil, http.StatusConflict)
+	}
+
+	return s.applyOrderOperation(ctx, payment, s.clientForPayment(payment).CancelOrder, "Order cancelada com sucesso")
+}
+
+// RefundOrder - reembolsa integralmente uma order aprovada
+func (s *PagamentoService) RefundOrder(ctx context.Context, req *contract.OrderOperationRequest) (*contract.OrderOperationResponse, error) {
+
+	payment, err := s.orderPayment(req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payment.IsApproved() {
+		return nil, util.WrapError("apenas orders aprovadas podem ser reembolsadas", nil, http.StatusConflict)
+	}
+
+	return s.applyOrderOperation(ctx, payment, s.clientForPayment(payment).RefundOrder, "Order reembolsada com sucesso")
+}
+
+// orderPayment - busca o pagamento da order e verifica se o usuário pode operá-la;
+// a empresa recebedora pode executar qualquer operação e o cliente apenas as permitidas
+func (s *PagamentoService) orderPayment(req *contract.OrderOperationRequest, allowClient bool) (*model.Pagamento, error) {
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	payment, err := s.PagamentoRepository.GetByMercadoPagoOrderID(req.OrderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, util.WrapError("order não encontrada", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao buscar order", err, http.StatusInternalServerError)
+	}
+
+	switch {
+	case req.UserType == "company" && payment.EmpresaID == req.UserID:
+		return payment, nil
+	case req.UserType == "client" && allowClient && payment.ClienteID == req.UserID:
+		return payment, nil
+	default:
+		return nil, util.WrapError("usuário sem permissão para operar esta order", nil, http.StatusForbidden)
+	}
+}
+
+// applyOrderOperation - executa a operação da order no Mercado Pago e persiste o novo status
+func (s *PagamentoService) applyOrderOperation(ctx context.Context, payment *model.Pagamento, operation func(ctx context.Context, orderID string) (*mercadopago.OrderResponse, error), message string) (*contract.OrderOperationResponse, error) {
+
+	order, err := operation(ctx, payment.MercadoPagoOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := payment.Status
+	if s.applyRemoteStatus(payment, orderAsPayment(order, payment)) {
+		if err := s.persistStatusChange(payment, previousStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	return &contract.OrderOperationResponse{
+		OrderID:     order.ID,
+		OrderStatus: order.Status,
+		Pagamento:   s.modelToResponse(payment),
+		Message:     message,
+	}, nil
+}
+
+// fetchRemotePayment - consulta o estado do pagamento no Mercado Pago, via order quando o pagamento foi criado por checkout
+func (s *PagamentoService) fetchRemotePayment(ctx context.Context, payment *model.Pagamento) (*mercadopago.PaymentResponse, error) {
+
+	client := s.clientForPayment(payment)
+
+	if payment.MercadoPagoOrderID == "" {
+		return client.GetPayment(ctx, payment.MercadoPagoPaymentID)
+	}
+
+	order, err := client.GetOrder(ctx, payment.MercadoPagoOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderAsPayment(order, payment), nil
+}
+
+// cancelRemotePayment - cancela o pagamento no Mercado Pago, via order quando o pagamento foi criado por checkout
+func (s *PagamentoService) cancelRemotePayment(ctx context.Context, payment *model.Pagamento) error {
+
+	client := s.clientForPayment(payment)
+
+	if payment.MercadoPagoOrderID == "" {
+		_, err := client.CancelPayment(ctx, payment.MercadoPagoPaymentID)
+		return err
+	}
+
+	_, err := client.CancelOrder(ctx, payment.MercadoPagoOrderID)
+	return err
+}
+
+// orderAsPayment - representa o estado da order no formato de pagamento usado na sincronização
+func orderAsPayment(order *mercadopago.OrderResponse, payment *model.Pagamento) *mercadopago.PaymentResponse {
+
+	status := order.PaymentStatus()
+
+	remote := &mercadopago.PaymentResponse{
+		Status:            status,
+		StatusDetail:      order.StatusDetail,
+		TransactionAmount: order.TotalAmount,
+		ExternalReference: order.ExternalReference,
+		Captured:          status == "approved" || status == "refunded" || status == "charged_back",
+	}
+
+	if len(order.Transactions.Payments) > 0 && order.Transactions.Payments[0].StatusDetail != "" {
+		remote.StatusDetail = order.Transactions.Payments[0].StatusDetail
+	}
+
+	if status == "refunded" {
+		remote.TransactionAmountRefunded = payment.Valor
+	}
+
+	return remote
+}
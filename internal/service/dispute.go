@@ -0,0 +1,456 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/mercadopago"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultDisputeEvidenceWindow - prazo para envio de evidências quando o Mercado Pago não informa um
+	DefaultDisputeEvidenceWindow = 7 * 24 * time.Hour
+
+	// maxEvidenceUploadSize - tamanho máximo do conjunto de arquivos aceito pelo Mercado Pago
+	maxEvidenceUploadSize = 10 * 1024 * 1024
+
+	// evidenceStoragePrefix - prefixo das evidências no armazenamento; fica fora de images/ para nunca ser servido
+	// pela rota de mídia
+	evidenceStoragePrefix = "disputes/"
+)
+
+// evidenceExtensions - tipos de arquivo aceitos como evidência e suas extensões
+var evidenceExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// DisputeService - objeto de contexto
+type DisputeService struct {
+	DisputeRepository   *repository.DisputeRepository
+	PagamentoRepository *repository.PagamentoRepository
+	LedgerService       *LedgerService
+	NotificationService *NotificationService
+	MPClient            *mercadopago.Client
+	Storage             storage.BlobStore
+}
+
+// DisputeServiceNew - construtor do objeto
+func DisputeServiceNew(DB *gorm.DB) *DisputeService {
+	cfg, _ := config.LoadConfig()
+
+	return &DisputeService{
+		DisputeRepository:   repository.DisputeRepositoryNew(DB),
+		PagamentoRepository: repository.PagamentoRepositoryNew(DB),
+		LedgerService:       LedgerServiceNew(DB),
+		NotificationService: NotificationServiceNew(DB),
+		MPClient:            newMercadoPagoClient(cfg),
+		Storage:             newBlobStore(cfg),
+	}
+}
+
+// SyncFromPayment - abre ou encerra a disputa de um pagamento a partir da mudança de status recebida do Mercado Pago
+func (s *DisputeService) SyncFromPayment(payment *model.Pagamento) error {
+
+	dispute, err := s.DisputeRepository.GetUnresolvedByPagamentoID(payment.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return util.WrapError("erro ao buscar disputa do pagamento", err, http.StatusInternalServerError)
+	}
+
+	now := time.Now()
+
+	switch model.StatusPagamento(payment.Status) {
+	case model.StatusInMediation, model.StatusChargedBack:
+		disputeType := model.DisputeMediation
+		if payment.Status == string(model.StatusChargedBack) {
+			disputeType = model.DisputeChargeback
+		}
+
+		if dispute == nil {
+			_, err := s.open(payment, disputeType, nil, payment.StatusDetail)
+			return err
+		}
+
+		// uma mediação não resolvida pode evoluir para chargeback
+		if dispute.Type != string(disputeType) && disputeType == model.DisputeChargeback {
+			dispute.Type = string(disputeType)
+			dispute.UpdatedAt = now
+			if err := s.DisputeRepository.Update(dispute); err != nil {
+				return util.WrapError("erro ao atualizar disputa", err, http.StatusInternalServerError)
+			}
+		}
+		return nil
+
+	case model.StatusApproved:
+		if dispute != nil {
+			return s.resolve(payment, dispute, model.DisputeWon)
+		}
+
+	case model.StatusRefunded, model.StatusCancelled:
+		if dispute != nil {
+			return s.resolve(payment, dispute, model.DisputeLost)
+		}
+	}
+
+	return nil
+}
+
+// ProcessChargeback - atualiza a disputa a partir da notificação de chargeback do Mercado Pago
+func (s *DisputeService) ProcessChargeback(ctx context.Context, chargebackID string) (*contract.WebhookResponse, error) {
+
+	chargeback, err := s.MPClient.GetChargeback(ctx, chargebackID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.PagamentoRepository.GetByMercadoPagoPaymentID(chargeback.PaymentID())
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &contract.WebhookResponse{Message: "Pagamento não encontrado"}, nil
+		}
+		return nil, util.WrapError("erro ao buscar pagamento", err, http.StatusInternalServerError)
+	}
+
+	dispute, err := s.DisputeRepository.GetByChargebackID(chargeback.ID)
+	if err == gorm.ErrRecordNotFound {
+		// a disputa pode ter sido aberta pela mudança de status do pagamento antes desta notificação
+		dispute, err = s.DisputeRepository.GetUnresolvedByPagamentoID(payment.ID)
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, util.WrapError("erro ao buscar disputa", err, http.StatusInternalServerError)
+	}
+
+	if dispute == nil {
+		dispute, err = s.open(payment, model.DisputeChargeback, chargeback, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	applyChargeback(dispute, chargeback)
+	if err := s.DisputeRepository.Update(dispute); err != nil {
+		return nil, util.WrapError("erro ao atualizar disputa", err, http.StatusInternalServerError)
+	}
+
+	// o valor contestado deixa de ser devido à empresa assim que o chargeback é aberto
+	if dispute.Status != string(model.DisputeWon) {
+		if err := s.LedgerService.RecordChargeback(payment); err != nil {
+			log.Printf("erro ao registrar chargeback do pagamento %d: %s", payment.ID, err.Error())
+		}
+	}
+
+	if !dispute.IsResolved() {
+		switch {
+		case chargeback.Won():
+			if err := s.resolve(payment, dispute, model.DisputeWon); err != nil {
+				return nil, err
+			}
+		case chargeback.Lost():
+			if err := s.resolve(payment, dispute, model.DisputeLost); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &contract.WebhookResponse{Message: "Notificação processada com sucesso"}, nil
+}
+
+// List - lista as disputas da empresa
+func (s *DisputeService) List(companyID int, req *contract.ListDisputesRequest) (*contract.ListDisputesResponse, error) {
+
+	disputes, err := s.DisputeRepository.ListByCompanyID(companyID, req.Status)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar disputas", err, http.StatusInternalServerError)
+	}
+
+	response := &contract.ListDisputesResponse{
+		Disputes: make([]contract.DisputeResponse, 0, len(disputes)),
+		Total:    len(disputes),
+	}
+
+	for i := range disputes {
+		response.Disputes = append(response.Disputes, disputeToResponse(&disputes[i]))
+	}
+
+	return response, nil
+}
+
+// Get - obtém uma disputa da empresa com as evidências enviadas
+func (s *DisputeService) Get(id, companyID int) (*contract.DisputeResponse, error) {
+
+	dispute, err := s.DisputeRepository.GetByID(id, companyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, util.WrapError("disputa não encontrada", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao buscar disputa", err, http.StatusInternalServerError)
+	}
+
+	response := disputeToResponse(dispute)
+	return &response, nil
+}
+
+// UploadEvidence - armazena as evidências da empresa e as encaminha ao Mercado Pago quando a disputa é um chargeback
+func (s *DisputeService) UploadEvidence(ctx context.Context, req *contract.UploadDisputeEvidenceRequest, files []*multipart.FileHeader) (*contract.UploadDisputeEvidenceResponse, error) {
+
+	dispute, err := s.DisputeRepository.GetByID(req.DisputeID, req.CompanyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, util.WrapError("disputa não encontrada", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao buscar disputa", err, http.StatusInternalServerError)
+	}
+
+	now := time.Now()
+	if !dispute.AcceptsEvidence(now) {
+		return nil, util.WrapError("prazo para envio de evidências encerrado", nil, http.StatusConflict)
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	if total > maxEvidenceUploadSize {
+		return nil, util.WrapError("arquivos de evidência excedem o limite de 10MB", nil, http.StatusRequestEntityTooLarge)
+	}
+
+	evidences := make([]model.DisputeEvidence, 0, len(files))
+	mpFiles := make([]mercadopago.ChargebackFile, 0, len(files))
+
+	for _, fileHeader := range files {
+		data, mimeType, err := readEvidenceFile(fileHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		token, err := util.GenerateToken()
+		if err != nil {
+			return nil, util.WrapError("erro ao gerar nome do arquivo", err, http.StatusInternalServerError)
+		}
+
+		key := fmt.Sprintf("%s%d/%s%s", evidenceStoragePrefix, dispute.ID, token[:32], evidenceExtensions[mimeType])
+		if err := s.Storage.Put(ctx, key, data, mimeType); err != nil {
+			return nil, util.WrapError("erro ao salvar evidência", err, http.StatusInternalServerError)
+		}
+
+		evidence := model.DisputeEvidence{
+			DisputeID:   dispute.ID,
+			Kind:        req.Kind,
+			Description: req.Description,
+			FileName:    filepath.Base(fileHeader.Filename),
+			FilePath:    key,
+			MimeType:    mimeType,
+			FileSize:    int64(len(data)),
+			CreatedAt:   now,
+		}
+		if err := s.DisputeRepository.CreateEvidence(&evidence); err != nil {
+			if err := s.Storage.Delete(ctx, key); err != nil {
+				log.Printf("erro ao remover evidência %s do armazenamento: %s", key, err.Error())
+			}
+			return nil, util.WrapError("erro ao registrar evidência", err, http.StatusInternalServerError)
+		}
+
+		evidences = append(evidences, evidence)
+		mpFiles = append(mpFiles, mercadopago.ChargebackFile{
+			Name:        evidence.FileName,
+			ContentType: mimeType,
+			Content:     bytes.NewReader(data),
+		})
+	}
+
+	message := "Evidências registradas; a equipe da plataforma as utilizará na mediação"
+
+	// somente chargebacks aceitam documentação pela API; mediações são conduzidas pela plataforma
+	if dispute.MercadoPagoChargebackID != nil && dispute.DocumentationRequired {
+		if err := s.MPClient.SubmitChargebackDocumentation(ctx, *dispute.MercadoPagoChargebackID, mpFiles); err != nil {
+			return nil, util.WrapError("evidências salvas, mas não foi possível enviá-las ao Mercado Pago", err, http.StatusBadGateway)
+		}
+
+		ids := make([]int, len(evidences))
+		for i := range evidences {
+			ids[i] = evidences[i].ID
+			evidences[i].SubmittedAt = &now
+		}
+		if err := s.DisputeRepository.MarkEvidencesSubmitted(ids, now); err != nil {
+			log.Printf("erro ao marcar evidências da disputa %d como enviadas: %s", dispute.ID, err.Error())
+		}
+		message = "Evidências enviadas ao Mercado Pago"
+	}
+
+	dispute.Status = string(model.DisputeEvidenceSubmitted)
+	dispute.UpdatedAt = now
+	if err := s.DisputeRepository.Update(dispute); err != nil {
+		return nil, util.WrapError("erro ao atualizar disputa", err, http.StatusInternalServerError)
+	}
+
+	dispute.Evidences = append(dispute.Evidences, evidences...)
+
+	return &contract.UploadDisputeEvidenceResponse{
+		Dispute: disputeToResponse(dispute),
+		Message: message,
+	}, nil
+}
+
+// open - registra uma nova disputa para o pagamento e notifica a empresa
+func (s *DisputeService) open(payment *model.Pagamento, disputeType model.DisputeType, chargeback *mercadopago.ChargebackResponse, reason string) (*model.Dispute, error) {
+
+	now := time.Now()
+	deadline := now.Add(DefaultDisputeEvidenceWindow)
+
+	dispute := &model.Dispute{
+		PagamentoID:           payment.ID,
+		CompanyID:             payment.EmpresaID,
+		Type:                  string(disputeType),
+		Status:                string(model.DisputeOpen),
+		Reason:                reason,
+		Amount:                payment.Valor,
+		Currency:              payment.Moeda,
+		DocumentationRequired: true,
+		EvidenceDeadline:      &deadline,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if chargeback != nil {
+		applyChargeback(dispute, chargeback)
+	}
+
+	if err := s.DisputeRepository.Create(dispute); err != nil {
+		return nil, util.WrapError("erro ao registrar disputa", err, http.StatusInternalServerError)
+	}
+
+	title := "Nova disputa aberta"
+	message := fmt.Sprintf("O pagamento #%d de R$ %.2f foi contestado pelo cliente. Envie as evidências até %s.",
+		payment.ID, dispute.Amount, dispute.EvidenceDeadline.Format("02/01/2006 15:04"))
+	s.notify(dispute, model.NotificationDisputeOpened, title, message)
+
+	return dispute, nil
+}
+
+// resolve - encerra a disputa, ajusta o razão quando o chargeback é ganho e notifica a empresa
+func (s *DisputeService) resolve(payment *model.Pagamento, dispute *model.Dispute, status model.DisputeStatus) error {
+
+	dispute.Resolve(status, time.Now())
+	if err := s.DisputeRepository.Update(dispute); err != nil {
+		return util.WrapError("erro ao atualizar disputa", err, http.StatusInternalServerError)
+	}
+
+	if status == model.DisputeWon && dispute.Type == string(model.DisputeChargeback) {
+		if err := s.LedgerService.RecordChargebackWon(payment); err != nil {
+			log.Printf("erro ao registrar disputa ganha do pagamento %d: %s", payment.ID, err.Error())
+		}
+	}
+
+	message := fmt.Sprintf("A disputa do pagamento #%d foi decidida a favor do cliente.", payment.ID)
+	if status == model.DisputeWon {
+		message = fmt.Sprintf("A disputa do pagamento #%d foi decidida a favor da empresa.", payment.ID)
+	}
+	s.notify(dispute, model.NotificationDisputeResolved, "Disputa encerrada", message)
+
+	return nil
+}
+
+// notify - notifica a empresa sobre a disputa; falhas não interrompem o processamento do webhook
+func (s *DisputeService) notify(dispute *model.Dispute, notificationType, title, message string) {
+	disputeID := dispute.ID
+	if err := s.NotificationService.NotifyCompany(dispute.CompanyID, notificationType, title, message, &disputeID); err != nil {
+		log.Printf("erro ao notificar empresa %d sobre a disputa %d: %s", dispute.CompanyID, dispute.ID, err.Error())
+	}
+}
+
+// applyChargeback - copia para a disputa os dados do chargeback do Mercado Pago
+func applyChargeback(dispute *model.Dispute, chargeback *mercadopago.ChargebackResponse) {
+
+	chargebackID := chargeback.ID
+	dispute.MercadoPagoChargebackID = &chargebackID
+	dispute.Type = string(model.DisputeChargeback)
+	dispute.DocumentationRequired = chargeback.DocumentationRequired
+	dispute.DocumentationStatus = chargeback.DocumentationStatus
+	dispute.UpdatedAt = time.Now()
+
+	if chargeback.Amount > 0 {
+		dispute.Amount = chargeback.Amount
+	}
+	if chargeback.Currency != "" {
+		dispute.Currency = chargeback.Currency
+	}
+	if deadline, err := time.Parse(mercadopago.DateTimeLayout, chargeback.DateDocumentationDeadline); err == nil {
+		dispute.EvidenceDeadline = &deadline
+	}
+}
+
+// readEvidenceFile - lê o arquivo enviado e valida se é PDF, JPEG ou PNG pelo conteúdo
+func readEvidenceFile(fileHeader *multipart.FileHeader) ([]byte, string, error) {
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, "", util.WrapError("erro ao abrir arquivo de evidência", err, http.StatusBadRequest)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", util.WrapError("erro ao ler arquivo de evidência", err, http.StatusBadRequest)
+	}
+
+	mimeType := http.DetectContentType(data)
+	if _, ok := evidenceExtensions[mimeType]; !ok {
+		return nil, "", util.WrapError(fmt.Sprintf("arquivo %s deve ser PDF, JPEG ou PNG", fileHeader.Filename), nil, http.StatusUnprocessableEntity)
+	}
+
+	return data, mimeType, nil
+}
+
+// disputeToResponse - converte a disputa para a resposta da API
+func disputeToResponse(dispute *model.Dispute) contract.DisputeResponse {
+
+	response := contract.DisputeResponse{
+		ID:                    dispute.ID,
+		PagamentoID:           dispute.PagamentoID,
+		Type:                  dispute.Type,
+		Status:                dispute.Status,
+		Reason:                dispute.Reason,
+		Amount:                dispute.Amount,
+		Currency:              dispute.Currency,
+		DocumentationRequired: dispute.DocumentationRequired,
+		DocumentationStatus:   dispute.DocumentationStatus,
+		EvidenceDeadline:      dispute.EvidenceDeadline,
+		AcceptsEvidence:       dispute.AcceptsEvidence(time.Now()),
+		CreatedAt:             dispute.CreatedAt,
+		UpdatedAt:             dispute.UpdatedAt,
+		ResolvedAt:            dispute.ResolvedAt,
+	}
+
+	if dispute.MercadoPagoChargebackID != nil {
+		response.MercadoPagoChargebackID = *dispute.MercadoPagoChargebackID
+	}
+
+	for _, evidence := range dispute.Evidences {
+		response.Evidences = append(response.Evidences, contract.DisputeEvidenceResponse{
+			ID:          evidence.ID,
+			Kind:        evidence.Kind,
+			Description: evidence.Description,
+			FileName:    evidence.FileName,
+			MimeType:    evidence.MimeType,
+			FileSize:    evidence.FileSize,
+			SubmittedAt: evidence.SubmittedAt,
+			CreatedAt:   evidence.CreatedAt,
+		})
+	}
+
+	return response
+}
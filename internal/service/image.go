@@ -1,43 +1,79 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"image"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"io"
+	"log"
+	"math"
 	"mime/multipart"
 	"net/http"
-	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/HugoSmits86/nativewebp"
 	"github.com/google/uuid"
 	"github.com/jampa_trip/internal/contract"
 	"github.com/jampa_trip/internal/model"
 	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/imagemeta"
+	"github.com/jampa_trip/pkg/placeholder"
+	"github.com/jampa_trip/pkg/storage"
 	"github.com/jampa_trip/pkg/util"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/webp"
 	"gorm.io/gorm"
 )
 
+// placeholderSize - maior lado da cópia reduzida usada no cálculo do BlurHash e da cor predominante
+const placeholderSize = 32
+
+// originalJPEGQuality - qualidade usada ao recodificar originais JPEG que precisaram ser girados
+const originalJPEGQuality = 92
+
+// imageStagingPrefix - área do armazenamento com os arquivos enviados que aguardam processamento
+const imageStagingPrefix = "staging/images/"
+
 // ImageService - objeto de contexto
 type ImageService struct {
-	ImageRepository *repository.ImageRepository
+	ImageRepository   *repository.ImageRepository
+	CompanyRepository *repository.CompanyRepository
+	TourRepository    *repository.TourRepository
+	Storage           storage.BlobStore
+	MediaBaseURL      string
+	RenditionWidths   []int
+	Quotas            ImageQuotas
+	ProcessAsync      bool
+	Signer            *MediaSigner
 }
 
 // ImageServiceNew - construtor do objeto
 func ImageServiceNew(DB *gorm.DB) *ImageService {
+	cfg, _ := config.LoadConfig()
+
 	return &ImageService{
-		ImageRepository: repository.ImageRepositoryNew(DB),
+		ImageRepository:   repository.ImageRepositoryNew(DB),
+		CompanyRepository: repository.CompanyRepositoryNew(DB),
+		TourRepository:    repository.TourRepositoryNew(DB),
+		Storage:           newBlobStore(cfg),
+		MediaBaseURL:      publicMediaBaseURL(cfg),
+		RenditionWidths:   parseRenditionWidths(cfg.ImageRenditionWidths),
+		Quotas:            parseImageQuotas(cfg),
+		ProcessAsync:      imageWorkerCount(cfg) > 0,
+		Signer:            newMediaSigner(cfg),
 	}
 }
 
-// UploadImages - faz upload de múltiplas imagens
+// UploadImages - faz upload de múltiplas imagens, respeitando a cota de armazenamento do usuário
 func (s *ImageService) UploadImages(files []*multipart.FileHeader, request *contract.UploadImagesRequest, userID int) (*contract.UploadImagesResponse, error) {
 	if len(files) == 0 {
 		return nil, util.WrapError("Nenhum arquivo enviado", nil, http.StatusBadRequest)
@@ -47,64 +83,100 @@ func (s *ImageService) UploadImages(files []*multipart.FileHeader, request *cont
 		return nil, util.WrapError("Máximo de 10 imagens por upload", nil, http.StatusBadRequest)
 	}
 
-	uploadDir := fmt.Sprintf("uploads/images/%d", userID)
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return nil, util.WrapError("Erro ao criar diretório de upload", err, http.StatusInternalServerError)
+	// a imagem aparece na listagem pública do passeio, então só a empresa dona pode vinculá-la
+	if request.TourID != nil {
+		if err := s.checkTourOwner(*request.TourID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	quota, _, err := s.quotaFor(userID)
+	if err != nil {
+		return nil, util.WrapError("Erro ao verificar cota de armazenamento", err, http.StatusInternalServerError)
 	}
 
-	var uploadedImages []contract.ImageResponse
+	// verificação antecipada para não processar arquivos de quem já atingiu a cota; a garantia está na gravação de cada imagem
+	if quota > 0 {
+		used, _, err := s.ImageRepository.GetStorageUsage(userID)
+		if err != nil {
+			return nil, util.WrapError("Erro ao calcular armazenamento utilizado", err, http.StatusInternalServerError)
+		}
+		if used >= quota {
+			return nil, quotaExceededError(used, quota)
+		}
+	}
+
+	uploadedImages := make([]contract.ImageResponse, 0, len(files))
 	var totalSize int64
+	results := make([]contract.ImageUploadResult, 0, len(files))
 	successCount := 0
+	failedCount := 0
+	duplicateCount := 0
+	processingCount := 0
 
+	// a falha de um arquivo não interrompe os demais; cada um tem seu resultado na resposta
 	for _, fileHeader := range files {
-		if err := s.validateImageFile(fileHeader); err != nil {
-			continue
-		}
-
-		imageData, err := s.processImageFile(fileHeader, uploadDir, userID, request.TourID)
+		uploaded, err := s.uploadFile(fileHeader, userID, request, quota)
 		if err != nil {
+			code, message := uploadErrorDetails(fileHeader.Filename, err)
+			results = append(results, contract.ImageUploadResult{
+				OriginalName: fileHeader.Filename,
+				Success:      false,
+				Code:         code,
+				Message:      message,
+			})
+			failedCount++
 			continue
 		}
 
-		if err := s.ImageRepository.Create(imageData); err != nil {
-			s.cleanupFiles(imageData.URL, imageData.ThumbnailURL)
-			continue
-		}
-
-		uploadedImages = append(uploadedImages, contract.ImageResponse{
-			ID:           imageData.ID,
-			Filename:     imageData.Filename,
-			OriginalName: imageData.OriginalName,
-			URL:          imageData.URL,
-			ThumbnailURL: imageData.ThumbnailURL,
-			Size:         imageData.Size,
-			Width:        imageData.Width,
-			Height:       imageData.Height,
-			Format:       imageData.Format,
-			Description:  imageData.Description,
-			IsPrimary:    imageData.IsPrimary,
-			TourID:       imageData.TourID,
-			UploadedAt:   imageData.UploadedAt,
+		uploadedImages = append(uploadedImages, *uploaded)
+		results = append(results, contract.ImageUploadResult{
+			OriginalName: fileHeader.Filename,
+			Success:      true,
+			ImageID:      &uploaded.ID,
 		})
 
-		totalSize += int64(imageData.Size)
+		totalSize += int64(uploaded.Size)
 		successCount++
-	}
-
-	if successCount == 0 {
-		return nil, util.WrapError("Nenhuma imagem foi processada com sucesso", nil, http.StatusBadRequest)
+		if uploaded.DuplicateOf != nil {
+			duplicateCount++
+		}
+		if uploaded.Status == model.ImageStatusProcessing {
+			processingCount++
+		}
 	}
 
 	response := &contract.UploadImagesResponse{
-		Success:       true,
-		Images:        uploadedImages,
-		TotalUploaded: successCount,
-		TotalSize:     totalSize,
+		Success:         successCount > 0,
+		Images:          uploadedImages,
+		Results:         results,
+		TotalUploaded:   successCount,
+		TotalFailed:     failedCount,
+		TotalDuplicates: duplicateCount,
+		TotalProcessing: processingCount,
+		TotalSize:       totalSize,
 	}
 
 	return response, nil
 }
 
+// checkTourOwner - verifica se o passeio pertence à empresa que envia as imagens
+func (s *ImageService) checkTourOwner(tourID, userID int) error {
+	tour, err := s.TourRepository.GetByID(tourID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return util.WrapError("Passeio não encontrado", err, http.StatusNotFound)
+		}
+		return util.WrapError("Erro ao buscar passeio", err, http.StatusInternalServerError)
+	}
+
+	if tour.CompanyID != userID {
+		return util.WrapError("Você não tem permissão para enviar imagens para este passeio", nil, http.StatusForbidden)
+	}
+
+	return nil
+}
+
 // ListImages - lista imagens do usuário
 func (s *ImageService) ListImages(request *contract.ListImagesRequest, userID int) (*contract.ListImagesResponse, error) {
 	config := util.NormalizePagination(request.Page, request.Limit)
@@ -116,25 +188,19 @@ func (s *ImageService) ListImages(request *contract.ListImagesRequest, userID in
 		return nil, util.WrapError("Erro ao buscar imagens", err, http.StatusInternalServerError)
 	}
 
+	imageIDs := make([]int, len(images))
+	for i, img := range images {
+		imageIDs[i] = img.ID
+	}
+
+	renditions, err := s.ImageRepository.ListRenditions(imageIDs)
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar versões das imagens", err, http.StatusInternalServerError)
+	}
+
 	var imagesResponse []contract.ImageResponse
 	for _, img := range images {
-		imagesResponse = append(imagesResponse, contract.ImageResponse{
-			ID:           img.ID,
-			Filename:     img.Filename,
-			OriginalName: img.OriginalName,
-			URL:          img.URL,
-			ThumbnailURL: img.ThumbnailURL,
-			Size:         img.Size,
-			Width:        img.Width,
-			Height:       img.Height,
-			Format:       img.Format,
-			Description:  img.Description,
-			AltText:      img.AltText,
-			IsPrimary:    img.IsPrimary,
-			TourID:       img.TourID,
-			UploadedAt:   img.UploadedAt,
-			UpdatedAt:    img.UpdatedAt,
-		})
+		imagesResponse = append(imagesResponse, s.modelToResponse(img, renditions[img.ID]))
 	}
 
 	totalPages := util.CalculateTotalPages(total, limit)
@@ -176,11 +242,17 @@ func (s *ImageService) DeleteImage(imageID, userID int) (*contract.DeleteImageRe
 		return nil, util.WrapError("Erro ao buscar dados da imagem", err, http.StatusInternalServerError)
 	}
 
+	renditions, err := s.ImageRepository.ListRenditions([]int{imageID})
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar versões da imagem", err, http.StatusInternalServerError)
+	}
+
+	// as versões são removidas do banco em cascata junto com a imagem
 	if err := s.ImageRepository.Delete(imageID); err != nil {
 		return nil, util.WrapError("Erro ao deletar imagem do banco", err, http.StatusInternalServerError)
 	}
 
-	s.cleanupFiles(image.URL, image.ThumbnailURL)
+	s.releaseFiles(image, renditions[imageID])
 
 	response := &contract.DeleteImageResponse{
 		Success: true,
@@ -212,6 +284,18 @@ func (s *ImageService) UpdateImage(imageID int, request *contract.UpdateImageReq
 		image.IsPrimary = true
 	}
 
+	if request.FocalPoint != nil || request.Crop != nil {
+		if err := s.editImage(context.Background(), image, request); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.Visibility != "" && request.Visibility != image.Visibility {
+		if err := s.changeVisibility(context.Background(), image, request.Visibility); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.ImageRepository.Update(image); err != nil {
 		return nil, util.WrapError("Erro ao atualizar imagem", err, http.StatusInternalServerError)
 	}
@@ -221,25 +305,14 @@ func (s *ImageService) UpdateImage(imageID int, request *contract.UpdateImageReq
 		return nil, util.WrapError("Erro ao buscar imagem atualizada", err, http.StatusInternalServerError)
 	}
 
+	renditions, err := s.ImageRepository.ListRenditions([]int{imageID})
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar versões da imagem", err, http.StatusInternalServerError)
+	}
+
 	response := &contract.UpdateImageResponse{
 		Success: true,
-		Image: contract.ImageResponse{
-			ID:           updatedImage.ID,
-			Filename:     updatedImage.Filename,
-			OriginalName: updatedImage.OriginalName,
-			URL:          updatedImage.URL,
-			ThumbnailURL: updatedImage.ThumbnailURL,
-			Size:         updatedImage.Size,
-			Width:        updatedImage.Width,
-			Height:       updatedImage.Height,
-			Format:       updatedImage.Format,
-			Description:  updatedImage.Description,
-			AltText:      updatedImage.AltText,
-			IsPrimary:    updatedImage.IsPrimary,
-			TourID:       updatedImage.TourID,
-			UploadedAt:   updatedImage.UploadedAt,
-			UpdatedAt:    updatedImage.UpdatedAt,
-		},
+		Image:   s.modelToResponse(updatedImage, renditions[imageID]),
 	}
 
 	return response, nil
@@ -290,25 +363,14 @@ func (s *ImageService) GetImageInfo(imageID, userID int) (*contract.ImageInfoRes
 		return nil, util.WrapError("Erro ao buscar informações de uso", err, http.StatusInternalServerError)
 	}
 
+	renditions, err := s.ImageRepository.ListRenditions([]int{imageID})
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar versões da imagem", err, http.StatusInternalServerError)
+	}
+
 	response := &contract.ImageInfoResponse{
 		Success: true,
-		Image: contract.ImageResponse{
-			ID:           image.ID,
-			Filename:     image.Filename,
-			OriginalName: image.OriginalName,
-			URL:          image.URL,
-			ThumbnailURL: image.ThumbnailURL,
-			Size:         image.Size,
-			Width:        image.Width,
-			Height:       image.Height,
-			Format:       image.Format,
-			Description:  image.Description,
-			AltText:      image.AltText,
-			IsPrimary:    image.IsPrimary,
-			TourID:       image.TourID,
-			UploadedAt:   image.UploadedAt,
-			UpdatedAt:    image.UpdatedAt,
-		},
+		Image:   s.modelToResponse(image, renditions[imageID]),
 		Usage: contract.ImageUsage{
 			TourName: tourName,
 			IsUsed:   isUsed,
@@ -354,6 +416,16 @@ func (s *ImageService) BatchDeleteImages(request *contract.BatchDeleteImagesRequ
 			continue
 		}
 
+		renditions, err := s.ImageRepository.ListRenditions([]int{imageID})
+		if err != nil {
+			failedCount++
+			errors = append(errors, contract.BatchDeleteError{
+				ImageID: imageID,
+				Error:   "Erro ao buscar versões da imagem",
+			})
+			continue
+		}
+
 		if err := s.ImageRepository.Delete(imageID); err != nil {
 			failedCount++
 			errors = append(errors, contract.BatchDeleteError{
@@ -363,7 +435,7 @@ func (s *ImageService) BatchDeleteImages(request *contract.BatchDeleteImagesRequ
 			continue
 		}
 
-		s.cleanupFiles(image.URL, image.ThumbnailURL)
+		s.releaseFiles(image, renditions[imageID])
 		deletedCount++
 	}
 
@@ -390,7 +462,8 @@ func (s *ImageService) BatchDeleteImages(request *contract.BatchDeleteImagesRequ
 func (s *ImageService) validateImageFile(fileHeader *multipart.FileHeader) error {
 	const maxSize = 10 * 1024 * 1024
 	if fileHeader.Size > maxSize {
-		return fmt.Errorf("arquivo muito grande: %d bytes (máximo: %d bytes)", fileHeader.Size, maxSize)
+		return failUpload(UploadErrorFileTooLarge,
+			fmt.Sprintf("Arquivo muito grande: %s (máximo: %s por imagem)", util.FormatBytes(fileHeader.Size), util.FormatBytes(maxSize)), nil)
 	}
 
 	contentType := fileHeader.Header.Get("Content-Type")
@@ -407,14 +480,20 @@ func (s *ImageService) validateImageFile(fileHeader *multipart.FileHeader) error
 	}
 
 	if !valid {
-		return fmt.Errorf("tipo de arquivo não suportado: %s", contentType)
+		return failUpload(UploadErrorUnsupportedType,
+			fmt.Sprintf("Tipo de arquivo não suportado: %s. Use JPG, PNG, GIF ou WEBP", contentType), nil)
 	}
 
 	return nil
 }
 
-// processImageFile - processa um arquivo de imagem
-func (s *ImageService) processImageFile(fileHeader *multipart.FileHeader, uploadDir string, userID int, tourID *int) (*model.Image, error) {
+// uploadFile - valida e grava um arquivo enviado; conteúdo idêntico a uma imagem já enviada pelo usuário reaproveita os arquivos dela
+// e não consome cota. Com cota zero o armazenamento é ilimitado.
+func (s *ImageService) uploadFile(fileHeader *multipart.FileHeader, userID int, request *contract.UploadImagesRequest, quota int64) (*contract.ImageResponse, error) {
+	if err := s.validateImageFile(fileHeader); err != nil {
+		return nil, err
+	}
+
 	file, err := fileHeader.Open()
 	if err != nil {
 		return nil, err
@@ -426,62 +505,268 @@ func (s *ImageService) processImageFile(fileHeader *multipart.FileHeader, upload
 		return nil, err
 	}
 
-	format, err := s.detectImageFormat(fileData)
+	sum := sha256.Sum256(fileData)
+	contentHash := hex.EncodeToString(sum[:])
+
+	// só imagens com a mesma visibilidade compartilham arquivos: a chave de uma imagem privada não pode ser servida
+	// sem assinatura por também pertencer a uma pública
+	visibility := request.Visibility
+	if visibility == "" {
+		visibility = model.ImageVisibilityPublic
+	}
+
+	source, err := s.ImageRepository.GetByContentHash(userID, contentHash, visibility)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if source != nil {
+		duplicate := &model.Image{
+			UserID:        userID,
+			TourID:        request.TourID,
+			Filename:      source.Filename,
+			OriginalName:  fileHeader.Filename,
+			URL:           source.URL,
+			ThumbnailURL:  source.ThumbnailURL,
+			ThumbnailSize: source.ThumbnailSize,
+			Size:          source.Size,
+			Width:         source.Width,
+			Height:        source.Height,
+			Format:        source.Format,
+			CapturedAt:    source.CapturedAt,
+			ContentHash:   contentHash,
+			BlurHash:      source.BlurHash,
+			DominantColor: source.DominantColor,
+			FocalX:        source.FocalX,
+			FocalY:        source.FocalY,
+			CropX:         source.CropX,
+			CropY:         source.CropY,
+			CropWidth:     source.CropWidth,
+			CropHeight:    source.CropHeight,
+			Visibility:    visibility,
+		}
+
+		created, err := s.ImageRepository.CreateDuplicate(source.ID, duplicate)
+		if err != nil {
+			return nil, err
+		}
+
+		// a imagem de origem foi excluída durante o upload: segue com o processamento normal
+		if created {
+			renditions, err := s.ImageRepository.ListRenditions([]int{duplicate.ID})
+			if err != nil {
+				return nil, err
+			}
+
+			response := s.modelToResponse(duplicate, renditions[duplicate.ID])
+			response.DuplicateOf = &source.ID
+			return &response, nil
+		}
+	}
+
+	if s.ProcessAsync {
+		return s.enqueueImageFile(fileData, fileHeader.Filename, userID, request, contentHash, quota)
+	}
+
+	imageData, renditions, err := s.processImageFile(fileData, fileHeader.Filename, userID, request)
 	if err != nil {
 		return nil, err
 	}
+	imageData.ContentHash = contentHash
+	files := append([]string{imageData.URL, imageData.ThumbnailURL}, renditionKeys(renditions)...)
+
+	if quota > 0 {
+		created, err := s.ImageRepository.CreateWithinQuota(imageData, quota)
+		if err != nil {
+			s.cleanupFiles(files...)
+			return nil, err
+		}
+		if !created {
+			s.cleanupFiles(files...)
+			used, _, _ := s.ImageRepository.GetStorageUsage(userID)
+			return nil, quotaExceededError(used, quota)
+		}
+	} else if err := s.ImageRepository.Create(imageData); err != nil {
+		s.cleanupFiles(files...)
+		return nil, err
+	}
+
+	renditions = s.saveRenditions(imageData.ID, renditions)
+
+	response := s.modelToResponse(imageData, renditions)
+	return &response, nil
+}
+
+// processImageFile - processa o conteúdo de uma imagem, gravando a original, o thumbnail e as versões responsivas
+func (s *ImageService) processImageFile(fileData []byte, originalName string, userID int, request *contract.UploadImagesRequest) (*model.Image, []model.ImageRendition, error) {
+	image := &model.Image{
+		UserID:       userID,
+		TourID:       request.TourID,
+		Filename:     newImageFilename(originalName),
+		OriginalName: originalName,
+		Description:  "",
+		AltText:      "",
+		IsPrimary:    false,
+		SortOrder:    0,
+		Visibility:   request.Visibility,
+		UploadedAt:   time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	renditions, err := s.storeImageFiles(context.Background(), fileData, image)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return image, renditions, nil
+}
+
+// storeImageFiles - grava no armazenamento a original sem metadados, o thumbnail e as versões da imagem,
+// preenchendo chaves, dimensões, tamanho, formato e data de captura a partir do conteúdo enviado
+func (s *ImageService) storeImageFiles(ctx context.Context, fileData []byte, image *model.Image) ([]model.ImageRendition, error) {
+	format, err := s.detectImageFormat(fileData)
+	if err != nil {
+		return nil, failUpload(UploadErrorInvalidImage, "Formato de imagem não reconhecido. Use JPG, PNG, GIF ou WEBP", err)
+	}
 
 	img, err := s.decodeImage(fileData, format)
+	if err != nil {
+		return nil, failUpload(UploadErrorInvalidImage, "Arquivo de imagem corrompido ou incompleto", err)
+	}
+
+	// a orientação do EXIF é aplicada antes de gerar thumbnail e versões, e o original é gravado sem metadados
+	metadata := imagemeta.Read(fileData, format)
+	img = imagemeta.Orient(img, metadata.Orientation)
+
+	originalData, err := s.sanitizeOriginal(fileData, format, img, metadata.Orientation)
 	if err != nil {
 		return nil, err
 	}
 
+	originalKey := imageOriginalKey(image.UserID, image.Filename)
+	if err := s.Storage.Put(ctx, originalKey, originalData, imageContentType(format)); err != nil {
+		return nil, err
+	}
 	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+	image.URL = originalKey
+	image.Size = len(originalData)
+	image.Width = bounds.Dx()
+	image.Height = bounds.Dy()
+	image.Format = format
+	image.CapturedAt = metadata.CapturedAt
+
+	renditions, err := s.storeDerivedFiles(ctx, img, image, image.Filename)
+	if err != nil {
+		s.cleanupFiles(originalKey)
+		return nil, err
+	}
 
-	uniqueID := uuid.New().String()
-	timestamp := time.Now().Unix()
-	filename := fmt.Sprintf("%d_%s_%s", timestamp, uniqueID[:8], fileHeader.Filename)
+	return renditions, nil
+}
 
-	originalPath := filepath.Join(uploadDir, filename)
-	if err := os.WriteFile(originalPath, fileData, 0644); err != nil {
-		return nil, err
+// imagePlaceholder - BlurHash e cor predominante usados pelos clientes enquanto a imagem carrega, calculados sobre uma
+// cópia de no máximo placeholderSize pixels de lado. A falha não impede o upload: a imagem fica sem placeholder.
+func imagePlaceholder(img image.Image) (string, string) {
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", ""
+	}
+
+	scale := math.Min(1, float64(placeholderSize)/float64(max(width, height)))
+	small := image.NewNRGBA(image.Rect(0, 0, max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, bounds, draw.Src, nil)
+
+	// mais componentes no lado maior da imagem
+	xComponents, yComponents := 4, 3
+	if height > width {
+		xComponents, yComponents = 3, 4
+	}
+
+	hash, err := placeholder.BlurHash(small, xComponents, yComponents)
+	if err != nil {
+		log.Printf("erro ao calcular BlurHash: %v", err)
+	}
+
+	return hash, placeholder.DominantColor(small)
+}
+
+// enqueueImageFile - grava o arquivo enviado em área privada e cria a imagem em processamento junto com o job que gera
+// a original sem metadados, o thumbnail e as versões. As dimensões vêm do cabeçalho, sem decodificar a imagem.
+func (s *ImageService) enqueueImageFile(fileData []byte, originalName string, userID int, request *contract.UploadImagesRequest, contentHash string, quota int64) (*contract.ImageResponse, error) {
+	format, err := s.detectImageFormat(fileData)
+	if err != nil {
+		return nil, failUpload(UploadErrorInvalidImage, "Formato de imagem não reconhecido. Use JPG, PNG, GIF ou WEBP", err)
 	}
 
-	thumbnailPath := filepath.Join(uploadDir, "thumb_"+filename)
-	thumbnailURL, err := s.generateThumbnail(img, thumbnailPath, format)
+	imageConfig, err := s.decodeImageConfig(fileData, format)
 	if err != nil {
-		thumbnailURL = ""
+		return nil, failUpload(UploadErrorInvalidImage, "Arquivo de imagem corrompido ou incompleto", err)
 	}
 
-	baseURL := "http://localhost:8080" // TODO: configurar via variável de ambiente
-	imageURL := fmt.Sprintf("%s/uploads/images/%d/%s", baseURL, userID, filename)
-	thumbnailImageURL := ""
-	if thumbnailURL != "" {
-		thumbnailImageURL = fmt.Sprintf("%s/uploads/images/%d/thumb_%s", baseURL, userID, filename)
+	// orientações 5 a 8 giram a imagem em 90 graus
+	metadata := imagemeta.Read(fileData, format)
+	width, height := imageConfig.Width, imageConfig.Height
+	if metadata.Orientation >= 5 {
+		width, height = height, width
+	}
+
+	filename := newImageFilename(originalName)
+	sourceKey := imageStagingKey(userID, filename)
+	if err := s.Storage.Put(context.Background(), sourceKey, fileData, imageContentType(format)); err != nil {
+		return nil, err
 	}
 
 	image := &model.Image{
 		UserID:       userID,
-		TourID:       tourID,
+		TourID:       request.TourID,
 		Filename:     filename,
-		OriginalName: fileHeader.Filename,
-		URL:          imageURL,
-		ThumbnailURL: thumbnailImageURL,
-		Size:         int(fileHeader.Size),
+		OriginalName: originalName,
+		URL:          imageOriginalKey(userID, filename),
+		Size:         len(fileData),
 		Width:        width,
 		Height:       height,
 		Format:       format,
-		Description:  "",
-		AltText:      "",
-		IsPrimary:    false,
-		SortOrder:    0,
 		UploadedAt:   time.Now(),
 		UpdatedAt:    time.Now(),
+		CapturedAt:   metadata.CapturedAt,
+		ContentHash:  contentHash,
+		Status:       model.ImageStatusProcessing,
+		Visibility:   request.Visibility,
+	}
+
+	created, err := s.ImageRepository.CreateForProcessing(image, &model.ImageJob{SourceKey: sourceKey}, quota)
+	if err != nil {
+		s.cleanupFiles(sourceKey)
+		return nil, err
 	}
+	if !created {
+		s.cleanupFiles(sourceKey)
+		used, _, _ := s.ImageRepository.GetStorageUsage(userID)
+		return nil, quotaExceededError(used, quota)
+	}
+
+	notifyImageWorkers()
+
+	response := s.modelToResponse(image, nil)
+	return &response, nil
+}
+
+// newImageFilename - nome único do arquivo gravado, preservando o nome original
+func newImageFilename(originalName string) string {
+	uniqueID := uuid.New().String()
+	return fmt.Sprintf("%d_%s_%s", time.Now().Unix(), uniqueID[:8], originalName)
+}
 
-	return image, nil
+// imageOriginalKey - chave do arquivo original da imagem
+func imageOriginalKey(userID int, filename string) string {
+	return fmt.Sprintf("images/%d/%s", userID, filename)
+}
+
+// imageStagingKey - chave do arquivo enviado aguardando processamento; fica fora de images/ para não ser servido pela rota de mídia
+func imageStagingKey(userID int, filename string) string {
+	return fmt.Sprintf("%s%d/%s", imageStagingPrefix, userID, filename)
 }
 
 // detectImageFormat - detecta o formato da imagem
@@ -508,6 +793,24 @@ func (s *ImageService) detectImageFormat(data []byte) (string, error) {
 	return "", fmt.Errorf("formato de imagem não suportado")
 }
 
+// decodeImageConfig - lê as dimensões da imagem a partir do cabeçalho
+func (s *ImageService) decodeImageConfig(data []byte, format string) (image.Config, error) {
+	reader := bytes.NewReader(data)
+
+	switch format {
+	case "jpg", "jpeg":
+		return jpeg.DecodeConfig(reader)
+	case "png":
+		return png.DecodeConfig(reader)
+	case "gif":
+		return gif.DecodeConfig(reader)
+	case "webp":
+		return webp.DecodeConfig(reader)
+	default:
+		return image.Config{}, fmt.Errorf("formato não suportado: %s", format)
+	}
+}
+
 // decodeImage - decodifica uma imagem
 func (s *ImageService) decodeImage(data []byte, format string) (image.Image, error) {
 	switch format {
@@ -524,8 +827,43 @@ func (s *ImageService) decodeImage(data []byte, format string) (image.Image, err
 	}
 }
 
-// generateThumbnail - gera thumbnail da imagem
-func (s *ImageService) generateThumbnail(img image.Image, thumbnailPath, format string) (string, error) {
+// sanitizeOriginal - remove os metadados do arquivo original; quando a orientação precisou ser aplicada,
+// a imagem é recodificada já na posição correta, pois a tag Orientation deixa de existir
+func (s *ImageService) sanitizeOriginal(data []byte, format string, img image.Image, orientation int) ([]byte, error) {
+	stripped, err := imagemeta.Strip(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if orientation <= 1 {
+		return stripped, nil
+	}
+
+	var buf bytes.Buffer
+
+	switch format {
+	case "jpg", "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: originalJPEGQuality}); err != nil {
+			return nil, err
+		}
+		return imagemeta.CopyJPEGProfile(buf.Bytes(), stripped), nil
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case "webp":
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return stripped, nil
+	}
+}
+
+// generateThumbnail - gera thumbnail da imagem, retornando o conteúdo codificado e o formato usado
+func (s *ImageService) generateThumbnail(img image.Image, format string) ([]byte, string, error) {
 	bounds := img.Bounds()
 	width := bounds.Dx()
 	height := bounds.Dy()
@@ -538,19 +876,35 @@ func (s *ImageService) generateThumbnail(img image.Image, thumbnailPath, format
 	// Redimensionar usando interpolação Lanczos para melhor qualidade
 	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, img.Bounds(), draw.Over, nil)
 
-	file, err := os.Create(thumbnailPath)
-	if err != nil {
-		return "", err
+	var buf bytes.Buffer
+
+	switch format {
+	case "png":
+		if err := png.Encode(&buf, thumb); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "png", nil
+	default:
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "jpg", nil
 	}
-	defer file.Close()
+}
 
+// imageContentType - retorna o Content-Type do formato de imagem
+func imageContentType(format string) string {
 	switch format {
 	case "jpg", "jpeg":
-		return thumbnailPath, jpeg.Encode(file, thumb, &jpeg.Options{Quality: 80})
+		return "image/jpeg"
 	case "png":
-		return thumbnailPath, png.Encode(file, thumb)
+		return "image/png"
+	case "gif":
+		return "image/gif"
+	case "webp":
+		return "image/webp"
 	default:
-		return thumbnailPath, jpeg.Encode(file, thumb, &jpeg.Options{Quality: 80})
+		return "application/octet-stream"
 	}
 }
 
@@ -569,43 +923,116 @@ func (s *ImageService) CalculateThumbnailDimensions(width, height int) (int, int
 	return newWidth, newHeight
 }
 
-// cleanupFiles - remove arquivos do sistema de arquivos
-func (s *ImageService) cleanupFiles(imageURL, thumbnailURL string) {
-	if imageURL != "" {
-		s.RemoveFileFromURL(imageURL)
-	}
-	if thumbnailURL != "" {
-		s.RemoveFileFromURL(thumbnailURL)
+// cleanupFiles - remove do armazenamento os arquivos de uma imagem (original, thumbnail e versões);
+// retorna a quantidade de arquivos que não puderam ser removidos
+func (s *ImageService) cleanupFiles(keys ...string) int {
+	failed := 0
+	for _, key := range keys {
+		// a falha na remoção não deve interromper a operação principal; o arquivo que ficar para trás é
+		// removido pela coleta de arquivos órfãos
+		if err := s.RemoveFile(key); err != nil {
+			log.Printf("erro ao remover arquivo %s do armazenamento: %v", key, err)
+			failed++
+		}
 	}
+	return failed
 }
 
-// RemoveFileFromURL - remove arquivo do sistema de arquivos baseado na URL
-func (s *ImageService) RemoveFileFromURL(fileURL string) {
-	if fileURL == "" {
-		return
+// releaseFiles - remove os arquivos de uma imagem excluída quando nenhuma outra imagem aponta para eles;
+// retorna a quantidade de arquivos que não puderam ser removidos
+func (s *ImageService) releaseFiles(image *model.Image, renditions []model.ImageRendition) int {
+	failed := 0
+
+	// o job é excluído junto com a imagem, então o arquivo aguardando processamento não seria mais removido
+	if image.Status == model.ImageStatusProcessing {
+		failed += s.cleanupFiles(imageStagingKey(image.UserID, image.Filename))
 	}
 
-	// Extrair o caminho do arquivo a partir da URL
-	parsedURL, err := url.Parse(fileURL)
+	references, err := s.ImageRepository.CountByURL(image.URL)
 	if err != nil {
-		// Log silencioso - não queremos falhar a operação principal
-		return
+		// na dúvida os arquivos são mantidos
+		log.Printf("erro ao contar referências dos arquivos da imagem %d: %v", image.ID, err)
+		return failed
 	}
 
-	// Remover o prefixo da URL base para obter o caminho relativo
-	// URL: http://localhost:8080/uploads/images/{user_id}/{filename}
-	// Path: uploads/images/{user_id}/{filename}
-	filePath := strings.TrimPrefix(parsedURL.Path, "/")
+	if references > 0 {
+		return failed
+	}
 
-	// Verificar se o arquivo existe antes de tentar removê-lo
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		// Arquivo não existe, nada a fazer
-		return
+	return failed + s.cleanupFiles(append([]string{image.URL, image.ThumbnailURL}, renditionKeys(renditions)...)...)
+}
+
+// RemoveFile - remove o arquivo do armazenamento a partir da chave gravada no banco.
+// Referências vazias, externas ou que não apontam para o armazenamento são ignoradas.
+func (s *ImageService) RemoveFile(ref string) error {
+	if ref == "" {
+		return nil
 	}
 
-	// Remover o arquivo
-	if err := os.Remove(filePath); err != nil {
-		// Log silencioso - não queremos falhar a operação principal
-		// Em produção, poderia ser logado para monitoramento
+	key, ok := storageKey(ref)
+	if !ok {
+		return nil
 	}
+
+	return s.Storage.Delete(context.Background(), key)
+}
+
+// imageURLBuilder - função que monta as URLs dos arquivos da imagem e, para imagens privadas, a expiração comum
+// das assinaturas. URLs absolutas antigas são devolvidas sem assinatura.
+func (s *ImageService) imageURLBuilder(img *model.Image, now time.Time) (func(string) string, *time.Time) {
+	if !img.IsPrivate() || s.Signer == nil {
+		return func(ref string) string { return mediaURL(s.MediaBaseURL, ref) }, nil
+	}
+
+	expiresAt := s.Signer.Expiration(now)
+
+	return func(ref string) string {
+		if ref == "" || strings.Contains(ref, "://") {
+			return mediaURL(s.MediaBaseURL, ref)
+		}
+		expires, signature := s.Signer.Sign(ref, now)
+		return signedMediaURL(s.MediaBaseURL, ref, expires, signature)
+	}, &expiresAt
+}
+
+// modelToResponse - converte model para response, montando as URLs públicas a partir das chaves
+// (assinadas e com validade quando a imagem é privada)
+func (s *ImageService) modelToResponse(img *model.Image, renditions []model.ImageRendition) contract.ImageResponse {
+	urlFor, expiresAt := s.imageURLBuilder(img, time.Now())
+
+	response := contract.ImageResponse{
+		ID:            img.ID,
+		Filename:      img.Filename,
+		OriginalName:  img.OriginalName,
+		URL:           urlFor(img.URL),
+		ThumbnailURL:  urlFor(img.ThumbnailURL),
+		Size:          img.Size,
+		Width:         img.Width,
+		Height:        img.Height,
+		Format:        img.Format,
+		Description:   img.Description,
+		AltText:       img.AltText,
+		IsPrimary:     img.IsPrimary,
+		TourID:        img.TourID,
+		UploadedAt:    img.UploadedAt,
+		UpdatedAt:     img.UpdatedAt,
+		CapturedAt:    img.CapturedAt,
+		Status:        img.Status,
+		Visibility:    img.Visibility,
+		URLExpiresAt:  expiresAt,
+		BlurHash:      img.BlurHash,
+		DominantColor: img.DominantColor,
+	}
+
+	if img.HasFocalPoint() {
+		response.FocalPoint = &contract.ImageFocalPoint{X: *img.FocalX, Y: *img.FocalY}
+	}
+	if img.CropX != nil {
+		crop := img.CropRect()
+		response.Crop = &contract.ImageCrop{X: crop.Min.X, Y: crop.Min.Y, Width: crop.Dx(), Height: crop.Dy()}
+	}
+
+	response.Renditions, response.SrcSet = s.renditionsToResponse(renditions, urlFor)
+
+	return response
 }
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/pkg/util"
+	"golang.org/x/image/draw"
+)
+
+// editImage - aplica o ponto focal e o recorte pedidos e regenera, a partir do original, o thumbnail, as versões e os
+// placeholders. Como a mídia é servida com cache imutável, os novos arquivos recebem chaves novas em vez de
+// sobrescrever os antigos; estes são removidos depois, se nenhuma duplicata continuar usando os mesmos arquivos.
+func (s *ImageService) editImage(ctx context.Context, img *model.Image, request *contract.UpdateImageRequest) error {
+
+	if img.Status != model.ImageStatusReady {
+		return util.WrapError("A imagem só pode ser recortada depois de processada", nil, http.StatusConflict)
+	}
+
+	originalKey, ok := storageKey(img.URL)
+	if !ok {
+		return util.WrapError("Imagens externas não podem ser recortadas", nil, http.StatusConflict)
+	}
+
+	if request.Crop != nil {
+		crop := image.Rect(request.Crop.X, request.Crop.Y, request.Crop.X+request.Crop.Width, request.Crop.Y+request.Crop.Height)
+		full := image.Rect(0, 0, img.Width, img.Height)
+		if !crop.In(full) {
+			return util.WrapError(fmt.Sprintf("O recorte deve estar dentro da imagem (%dx%d)", img.Width, img.Height), nil, http.StatusUnprocessableEntity)
+		}
+
+		// o recorte da imagem inteira equivale a remover o recorte
+		img.CropX, img.CropY, img.CropWidth, img.CropHeight = nil, nil, nil, nil
+		if !crop.Eq(full) {
+			x, y, width, height := crop.Min.X, crop.Min.Y, crop.Dx(), crop.Dy()
+			img.CropX, img.CropY, img.CropWidth, img.CropHeight = &x, &y, &width, &height
+		}
+	}
+
+	if request.FocalPoint != nil {
+		x, y := request.FocalPoint.X, request.FocalPoint.Y
+		img.FocalX, img.FocalY = &x, &y
+	}
+
+	data, err := s.Storage.Get(ctx, originalKey)
+	if err != nil {
+		return util.WrapError("Erro ao ler o original da imagem", err, http.StatusInternalServerError)
+	}
+
+	src, err := s.decodeImage(data, img.Format)
+	if err != nil {
+		return util.WrapError("Erro ao decodificar o original da imagem", err, http.StatusInternalServerError)
+	}
+
+	previous, err := s.ImageRepository.ListRenditions([]int{img.ID})
+	if err != nil {
+		return util.WrapError("Erro ao buscar versões da imagem", err, http.StatusInternalServerError)
+	}
+	previousThumbnail := img.ThumbnailURL
+
+	renditions, err := s.storeDerivedFiles(ctx, src, img, editedImageFilename(img.Filename))
+	if err != nil {
+		return util.WrapError("Erro ao gerar as versões recortadas da imagem", err, http.StatusInternalServerError)
+	}
+
+	replaced, err := s.ImageRepository.ReplaceDerivatives(img, previousThumbnail, renditions)
+	if err != nil || !replaced {
+		s.cleanupFiles(append([]string{img.ThumbnailURL}, renditionKeys(renditions)...)...)
+		if err != nil {
+			return util.WrapError("Erro ao gravar as versões recortadas da imagem", err, http.StatusInternalServerError)
+		}
+		return util.WrapError("A imagem foi alterada ou excluída durante o recorte; tente novamente", nil, http.StatusConflict)
+	}
+
+	s.releaseReplacedFiles(append([]string{previousThumbnail}, renditionKeys(previous[img.ID])...)...)
+
+	return nil
+}
+
+// storeDerivedFiles - grava o thumbnail e as versões da imagem e calcula os placeholders, aplicando o recorte e o
+// ponto focal gravados. O thumbnail de uma imagem com ponto focal é quadrado e centrado nele, para os cards.
+// Os arquivos são nomeados a partir de filename; a falha no thumbnail não impede a gravação da imagem.
+func (s *ImageService) storeDerivedFiles(ctx context.Context, src image.Image, img *model.Image, filename string) ([]model.ImageRendition, error) {
+
+	crop := img.CropRect()
+	region := cropImage(src, crop)
+
+	thumbnailSource := region
+	if img.HasFocalPoint() {
+		thumbnailSource = cropImage(src, focalSquare(crop, *img.FocalX*float64(img.Width), *img.FocalY*float64(img.Height)))
+	}
+
+	img.ThumbnailURL = ""
+	img.ThumbnailSize = 0
+	thumbnailData, thumbnailFormat, err := s.generateThumbnail(thumbnailSource, img.Format)
+	if err == nil {
+		key := fmt.Sprintf("images/%d/thumb_%s", img.UserID, filename)
+		if err := s.Storage.Put(ctx, key, thumbnailData, imageContentType(thumbnailFormat)); err != nil {
+			log.Printf("erro ao gravar thumbnail %s: %v", key, err)
+		} else {
+			img.ThumbnailURL = key
+			img.ThumbnailSize = len(thumbnailData)
+		}
+	}
+
+	renditions, err := s.generateRenditions(ctx, region, img.UserID, filename)
+	if err != nil {
+		s.cleanupFiles(img.ThumbnailURL)
+		return nil, err
+	}
+
+	img.BlurHash, img.DominantColor = imagePlaceholder(region)
+
+	return renditions, nil
+}
+
+// changeVisibility - troca a visibilidade da imagem copiando o original, o thumbnail e as versões para chaves novas.
+// As chaves antigas podem ser compartilhadas com duplicatas de outra visibilidade ou já estar em caches como
+// públicas, então a imagem nunca troca de visibilidade mantendo os mesmos arquivos. As cópias antigas são removidas
+// depois, se nenhuma duplicata continuar usando os mesmos arquivos.
+func (s *ImageService) changeVisibility(ctx context.Context, img *model.Image, visibility string) error {
+
+	originalKey, ok := storageKey(img.URL)
+	if img.Status != model.ImageStatusReady || !ok {
+		// a imagem em processamento ainda não tem arquivos publicados e a externa não é servida pela aplicação
+		img.Visibility = visibility
+		return nil
+	}
+
+	previous, err := s.ImageRepository.ListRenditions([]int{img.ID})
+	if err != nil {
+		return util.WrapError("Erro ao buscar versões da imagem", err, http.StatusInternalServerError)
+	}
+
+	prefix := uuid.New().String()[:8]
+	copied := []string{}
+	copyFile := func(key, contentType string) (string, error) {
+		data, err := s.Storage.Get(ctx, key)
+		if err != nil {
+			return "", err
+		}
+
+		newKey := path.Join(path.Dir(key), fmt.Sprintf("v%s_%s", prefix, path.Base(key)))
+		if err := s.Storage.Put(ctx, newKey, data, contentType); err != nil {
+			return "", err
+		}
+
+		copied = append(copied, newKey)
+		return newKey, nil
+	}
+
+	updated := *img
+	updated.Visibility = visibility
+
+	updated.URL, err = copyFile(originalKey, imageContentType(img.Format))
+	if err == nil && img.ThumbnailURL != "" {
+		if thumbnailKey, ok := storageKey(img.ThumbnailURL); ok {
+			updated.ThumbnailURL, err = copyFile(thumbnailKey, imageContentType(strings.TrimPrefix(path.Ext(thumbnailKey), ".")))
+		}
+	}
+
+	renditions := append([]model.ImageRendition(nil), previous[img.ID]...)
+	for i := 0; err == nil && i < len(renditions); i++ {
+		renditions[i].StorageKey, err = copyFile(renditions[i].StorageKey, imageContentType(renditions[i].Format))
+	}
+
+	if err != nil {
+		s.cleanupFiles(copied...)
+		return util.WrapError("Erro ao copiar os arquivos da imagem", err, http.StatusInternalServerError)
+	}
+	updated.Filename = path.Base(updated.URL)
+
+	replaced, err := s.ImageRepository.ReplaceFiles(&updated, img.URL, renditions)
+	if err != nil || !replaced {
+		s.cleanupFiles(copied...)
+		if err != nil {
+			return util.WrapError("Erro ao gravar a visibilidade da imagem", err, http.StatusInternalServerError)
+		}
+		return util.WrapError("A imagem foi alterada ou excluída durante a troca de visibilidade; tente novamente", nil, http.StatusConflict)
+	}
+
+	s.releaseReplacedFiles(append([]string{originalKey, img.ThumbnailURL}, renditionKeys(previous[img.ID])...)...)
+
+	*img = updated
+	return nil
+}
+
+// releaseReplacedFiles - remove os arquivos substituídos na edição que nenhuma imagem ou versão referencia mais;
+// os compartilhados com duplicatas continuam no armazenamento
+func (s *ImageService) releaseReplacedFiles(keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		references, err := s.ImageRepository.CountFileReferences(key)
+		if err != nil {
+			// na dúvida o arquivo é mantido
+			log.Printf("erro ao contar referências do arquivo %s: %v", key, err)
+			continue
+		}
+
+		if references == 0 {
+			s.cleanupFiles(key)
+		}
+	}
+}
+
+// editedImageFilename - nome base dos arquivos gerados em uma edição, diferente a cada edição
+func editedImageFilename(filename string) string {
+	return fmt.Sprintf("e%s_%s", uuid.New().String()[:8], filename)
+}
+
+// cropImage - região da imagem, em coordenadas relativas ao canto da imagem, sem copiar os pixels quando o tipo
+// da imagem permite
+func cropImage(src image.Image, rect image.Rectangle) image.Image {
+	bounds := src.Bounds()
+	rect = rect.Add(bounds.Min).Intersect(bounds)
+	if rect.Empty() || rect.Eq(bounds) {
+		return src
+	}
+
+	if sub, ok := src.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, rect.Min, draw.Src)
+	return dst
+}
+
+// focalSquare - maior quadrado dentro do recorte com o ponto (x, y) o mais perto possível do centro
+func focalSquare(crop image.Rectangle, x, y float64) image.Rectangle {
+	side := min(crop.Dx(), crop.Dy())
+	left := max(crop.Min.X, min(int(x)-side/2, crop.Max.X-side))
+	top := max(crop.Min.Y, min(int(y)-side/2, crop.Max.Y-side))
+	return image.Rect(left, top, left+side, top+side)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// imageGCGracePeriod - idade mínima dos arquivos e registros conferidos entre o armazenamento e o banco, para não
+// tratar como órfão o arquivo de um upload ou processamento ainda em andamento
+const imageGCGracePeriod = time.Hour
+
+// CollectGarbage - exclui as imagens públicas que estão sem passeio há mais de maxAge e confronta o armazenamento com a
+// tabela de imagens nos dois sentidos: remove os arquivos que nenhum registro referencia, marca como falhas as
+// imagens cujo original sumiu e exclui as versões sem arquivo. Com dryRun nada é alterado e o relatório lista o
+// que seria feito.
+func (s *ImageService) CollectGarbage(ctx context.Context, maxAge time.Duration, limit int, dryRun bool) (*contract.ImageGCReport, error) {
+
+	report := &contract.ImageGCReport{
+		StartedAt: time.Now(),
+		DryRun:    dryRun,
+		Items:     []contract.ImageGCItem{},
+	}
+
+	if err := s.collectUnusedImages(ctx, report, report.StartedAt.Add(-maxAge), limit); err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool)
+	refs, err := s.ImageRepository.ListStorageReferences()
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar arquivos referenciados pelas imagens", err, http.StatusInternalServerError)
+	}
+	for _, ref := range refs {
+		if key, ok := storageKey(ref); ok {
+			referenced[key] = true
+		}
+	}
+
+	var objects []storage.Object
+	for _, prefix := range []string{"images/", imageStagingPrefix} {
+		listed, err := s.Storage.List(ctx, prefix)
+		if err != nil {
+			return nil, util.WrapError("erro ao listar arquivos do armazenamento", err, http.StatusInternalServerError)
+		}
+		objects = append(objects, listed...)
+	}
+
+	cutoff := report.StartedAt.Add(-imageGCGracePeriod)
+	s.collectOrphanedFiles(ctx, report, objects, referenced, cutoff)
+
+	if err := s.collectMissingFiles(ctx, report, objects, cutoff); err != nil {
+		return nil, err
+	}
+
+	report.FinishedAt = time.Now()
+
+	return report, nil
+}
+
+// collectUnusedImages - exclui as imagens públicas sem passeio desde antes da data informada, liberando seus arquivos
+func (s *ImageService) collectUnusedImages(ctx context.Context, report *contract.ImageGCReport, unattachedBefore time.Time, limit int) error {
+
+	images, err := s.ImageRepository.ListUnused(unattachedBefore, limit)
+	if err != nil {
+		return util.WrapError("erro ao buscar imagens sem passeio", err, http.StatusInternalServerError)
+	}
+
+	ids := make([]int, 0, len(images))
+	for _, image := range images {
+		ids = append(ids, image.ID)
+	}
+
+	renditions, err := s.ImageRepository.ListRenditions(ids)
+	if err != nil {
+		return util.WrapError("erro ao buscar versões das imagens sem passeio", err, http.StatusInternalServerError)
+	}
+
+	for _, image := range images {
+		if ctx.Err() != nil {
+			break
+		}
+
+		imageID := image.ID
+		item := contract.ImageGCItem{Action: contract.ImageGCDeleteImage, ImageID: &imageID, Key: image.URL, Size: int64(image.Size)}
+
+		if !applyGCAction(report, &item, func() (bool, error) { return s.ImageRepository.DeleteUnused(image.ID, unattachedBefore) }) {
+			continue
+		}
+
+		if !report.DryRun && s.releaseFiles(image, renditions[image.ID]) > 0 {
+			item.Error = "arquivos da imagem não removidos do armazenamento"
+			report.Failed++
+		}
+
+		report.UnusedImages++
+		report.UnusedBytes += int64(image.Size)
+		report.Items = append(report.Items, item)
+	}
+
+	return nil
+}
+
+// collectOrphanedFiles - remove os arquivos que nenhuma imagem, versão ou job referencia
+func (s *ImageService) collectOrphanedFiles(ctx context.Context, report *contract.ImageGCReport, objects []storage.Object, referenced map[string]bool, cutoff time.Time) {
+
+	for _, object := range objects {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if referenced[object.Key] || object.LastModified.After(cutoff) {
+			continue
+		}
+
+		item := contract.ImageGCItem{Action: contract.ImageGCDeleteFile, Key: object.Key, Size: object.Size}
+
+		if !applyGCAction(report, &item, func() (bool, error) { return true, s.Storage.Delete(ctx, object.Key) }) {
+			continue
+		}
+
+		report.OrphanedFiles++
+		report.OrphanedBytes += object.Size
+		report.Items = append(report.Items, item)
+	}
+}
+
+// collectMissingFiles - marca como falhas as imagens prontas cujo original não está no armazenamento e exclui as
+// versões cujo arquivo não existe mais
+func (s *ImageService) collectMissingFiles(ctx context.Context, report *contract.ImageGCReport, objects []storage.Object, cutoff time.Time) error {
+
+	// armazenamento vazio indica configuração errada (diretório ou bucket), não a perda de todos os arquivos
+	if len(objects) == 0 {
+		log.Printf("coleta de imagens: nenhum arquivo encontrado no armazenamento, conferência das imagens ignorada")
+		return nil
+	}
+
+	stored := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		stored[object.Key] = true
+	}
+
+	originals, err := s.ImageRepository.ListReadyOriginals(cutoff)
+	if err != nil {
+		return util.WrapError("erro ao buscar imagens prontas", err, http.StatusInternalServerError)
+	}
+
+	ids := make([]int, 0, len(originals))
+	for id := range originals {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// URLs externas não estão no armazenamento
+		key, ok := storageKey(originals[id])
+		if !ok || stored[key] {
+			continue
+		}
+
+		imageID := id
+		item := contract.ImageGCItem{Action: contract.ImageGCMarkFailed, ImageID: &imageID, Key: key}
+
+		if !applyGCAction(report, &item, func() (bool, error) { return true, s.ImageRepository.SetStatus(imageID, model.ImageStatusFailed) }) {
+			continue
+		}
+
+		report.MissingOriginals++
+		report.Items = append(report.Items, item)
+	}
+
+	keys, err := s.ImageRepository.ListRenditionKeys(cutoff)
+	if err != nil {
+		return util.WrapError("erro ao buscar versões das imagens", err, http.StatusInternalServerError)
+	}
+
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if stored[key] {
+			continue
+		}
+
+		item := contract.ImageGCItem{Action: contract.ImageGCDeleteRendition, Key: key}
+
+		if !applyGCAction(report, &item, func() (bool, error) { return true, s.ImageRepository.DeleteRenditionsByKey(key) }) {
+			continue
+		}
+
+		report.MissingRenditions++
+		report.Items = append(report.Items, item)
+	}
+
+	return nil
+}
+
+// applyGCAction - executa a ação do item fora da simulação. Retorna false quando o item não deve ser contabilizado:
+// a ação falhou (o item entra no relatório com o erro) ou o registro não foi mais encontrado.
+func applyGCAction(report *contract.ImageGCReport, item *contract.ImageGCItem, action func() (bool, error)) bool {
+	if report.DryRun {
+		return true
+	}
+
+	done, err := action()
+	if err != nil {
+		log.Printf("erro na coleta de imagens (%s %s): %v", item.Action, item.Key, err)
+		item.Error = err.Error()
+		report.Failed++
+		report.Items = append(report.Items, *item)
+		return false
+	}
+
+	return done
+}
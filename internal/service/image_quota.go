@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// DefaultImageQuotaCompany - cota padrão de armazenamento de imagens por empresa
+const DefaultImageQuotaCompany = 1 << 30
+
+// ImageQuotas - limites de armazenamento de imagens das empresas; o plano, quando configurado, prevalece sobre o limite padrão.
+// Zero indica armazenamento ilimitado.
+type ImageQuotas struct {
+	Company int64
+	Plans   map[string]int64
+}
+
+// parseImageQuotas - lê as cotas da configuração
+func parseImageQuotas(cfg *config.Config) ImageQuotas {
+	return ImageQuotas{
+		Company: util.ParseByteSizeOrDefault(cfg.ImageQuotaCompany, DefaultImageQuotaCompany),
+		Plans:   parsePlanQuotas(cfg.ImageQuotaPlans),
+	}
+}
+
+// parsePlanQuotas - converte a lista "plano=tamanho" separada por vírgulas, ignorando entradas inválidas
+func parsePlanQuotas(value string) map[string]int64 {
+	plans := make(map[string]int64)
+
+	for _, entry := range strings.Split(value, ",") {
+		plan, size, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+
+		quota, err := util.ParseByteSize(size)
+		if err != nil {
+			continue
+		}
+
+		plans[strings.ToLower(strings.TrimSpace(plan))] = quota
+	}
+
+	return plans
+}
+
+// quotaFor - cota de armazenamento da empresa e o plano que a definiu; só empresas enviam imagens
+func (s *ImageService) quotaFor(companyID int) (int64, string, error) {
+
+	plan, err := s.CompanyRepository.GetPlan(companyID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	plan = strings.ToLower(plan)
+	if quota, ok := s.Quotas.Plans[plan]; ok {
+		return quota, plan, nil
+	}
+
+	return s.Quotas.Company, plan, nil
+}
+
+// GetQuota - uso e limite de armazenamento de imagens da empresa
+func (s *ImageService) GetQuota(userID int) (*contract.ImageQuotaResponse, error) {
+
+	quota, plan, err := s.quotaFor(userID)
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar cota de armazenamento", err, http.StatusInternalServerError)
+	}
+
+	used, totalImages, err := s.ImageRepository.GetStorageUsage(userID)
+	if err != nil {
+		return nil, util.WrapError("Erro ao calcular armazenamento utilizado", err, http.StatusInternalServerError)
+	}
+
+	response := &contract.ImageQuotaResponse{
+		Success:     true,
+		Plan:        plan,
+		Unlimited:   quota == 0,
+		QuotaBytes:  quota,
+		UsedBytes:   used,
+		TotalImages: totalImages,
+	}
+
+	if quota > 0 {
+		response.AvailableBytes = max(quota-used, 0)
+		response.UsagePercent = float64(used) * 100 / float64(quota)
+	}
+
+	return response, nil
+}
+
+// quotaExceededError - erro retornado quando o upload ultrapassaria a cota
+func quotaExceededError(used, quota int64) error {
+	message := fmt.Sprintf("Cota de armazenamento de imagens excedida: %s de %s utilizados. Exclua imagens não usadas ou contrate um plano maior",
+		util.FormatBytes(used), util.FormatBytes(quota))
+	return util.WrapError(message, nil, http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"golang.org/x/image/draw"
+)
+
+// DefaultImageRenditionWidths - larguras padrão das versões responsivas geradas no upload
+const DefaultImageRenditionWidths = "320,640,1280,1920"
+
+// renditionJPEGQuality - qualidade das versões em JPEG
+const renditionJPEGQuality = 82
+
+// encodedImage - conteúdo codificado de uma versão e o formato usado
+type encodedImage struct {
+	data   []byte
+	format string
+}
+
+// parseRenditionWidths - converte a lista de larguras separadas por vírgula, ignorando valores inválidos
+func parseRenditionWidths(value string) []int {
+
+	if strings.TrimSpace(value) == "" {
+		value = DefaultImageRenditionWidths
+	}
+
+	seen := make(map[int]bool)
+	var widths []int
+	for _, part := range strings.Split(value, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || width <= 0 || seen[width] {
+			continue
+		}
+		seen[width] = true
+		widths = append(widths, width)
+	}
+
+	if len(widths) == 0 && value != DefaultImageRenditionWidths {
+		return parseRenditionWidths(DefaultImageRenditionWidths)
+	}
+
+	sort.Ints(widths)
+	return widths
+}
+
+// generateRenditions - gera e grava as versões redimensionadas da imagem; larguras maiores que a original são ignoradas.
+// Cada largura é gravada em JPEG (ou PNG, quando há transparência) e também em WebP.
+func (s *ImageService) generateRenditions(ctx context.Context, img image.Image, userID int, filename string) ([]model.ImageRendition, error) {
+
+	bounds := img.Bounds()
+	base := strings.TrimSuffix(filename, path.Ext(filename))
+
+	var renditions []model.ImageRendition
+	for _, width := range s.RenditionWidths {
+		if width >= bounds.Dx() {
+			continue
+		}
+
+		height := max(1, (bounds.Dy()*width+bounds.Dx()/2)/bounds.Dx())
+		resized := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+
+		data, format, err := encodeRendition(resized)
+		if err != nil {
+			s.cleanupFiles(renditionKeys(renditions)...)
+			return nil, err
+		}
+
+		// o encoder WebP disponível é sem perdas; a versão é mantida mesmo quando fica maior que o JPEG,
+		// pois o navegador escolhe o formato pelo srcset e o JPEG continua disponível como alternativa
+		var webp bytes.Buffer
+		if err := nativewebp.Encode(&webp, resized, nil); err != nil {
+			s.cleanupFiles(renditionKeys(renditions)...)
+			return nil, err
+		}
+
+		encoded := []encodedImage{{data, format}, {webp.Bytes(), "webp"}}
+
+		for _, e := range encoded {
+			key := fmt.Sprintf("images/%d/%dw_%s.%s", userID, width, base, e.format)
+			if err := s.Storage.Put(ctx, key, e.data, imageContentType(e.format)); err != nil {
+				s.cleanupFiles(renditionKeys(renditions)...)
+				return nil, err
+			}
+
+			renditions = append(renditions, model.ImageRendition{
+				Width:      width,
+				Height:     height,
+				Format:     e.format,
+				StorageKey: key,
+				Size:       len(e.data),
+			})
+		}
+	}
+
+	return renditions, nil
+}
+
+// saveRenditions - vincula as versões à imagem gravada; em caso de falha os arquivos são removidos e a imagem segue sem elas
+func (s *ImageService) saveRenditions(imageID int, renditions []model.ImageRendition) []model.ImageRendition {
+
+	if len(renditions) == 0 {
+		return nil
+	}
+
+	for i := range renditions {
+		renditions[i].ImageID = imageID
+	}
+
+	if err := s.ImageRepository.CreateRenditions(renditions); err != nil {
+		log.Printf("erro ao gravar versões da imagem %d: %v", imageID, err)
+		s.cleanupFiles(renditionKeys(renditions)...)
+		return nil
+	}
+
+	return renditions
+}
+
+// encodeRendition - codifica em JPEG as imagens opacas e em PNG as que têm transparência
+func encodeRendition(img *image.RGBA) ([]byte, string, error) {
+
+	var buf bytes.Buffer
+
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: renditionJPEGQuality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "jpg", nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "png", nil
+}
+
+// renditionKeys - chaves de armazenamento das versões
+func renditionKeys(renditions []model.ImageRendition) []string {
+	keys := make([]string, len(renditions))
+	for i, rendition := range renditions {
+		keys[i] = rendition.StorageKey
+	}
+	return keys
+}
+
+// renditionsToResponse - converte as versões para a resposta e monta o srcset de cada formato, com as URLs
+// montadas por urlFor
+func (s *ImageService) renditionsToResponse(renditions []model.ImageRendition, urlFor func(string) string) ([]contract.ImageRenditionResponse, map[string]string) {
+
+	if len(renditions) == 0 {
+		return nil, nil
+	}
+
+	response := make([]contract.ImageRenditionResponse, len(renditions))
+	candidates := make(map[string][]string)
+
+	for i, rendition := range renditions {
+		url := urlFor(rendition.StorageKey)
+		mimeType := imageContentType(rendition.Format)
+
+		response[i] = contract.ImageRenditionResponse{
+			URL:      url,
+			Width:    rendition.Width,
+			Height:   rendition.Height,
+			Format:   rendition.Format,
+			MimeType: mimeType,
+			Size:     rendition.Size,
+		}
+		candidates[mimeType] = append(candidates[mimeType], fmt.Sprintf("%s %dw", url, rendition.Width))
+	}
+
+	srcset := make(map[string]string, len(candidates))
+	for mimeType, list := range candidates {
+		srcset[mimeType] = strings.Join(list, ", ")
+	}
+
+	return response, srcset
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// Códigos de erro informados no resultado de cada arquivo do upload
+const (
+	UploadErrorFileTooLarge    = "file_too_large"
+	UploadErrorUnsupportedType = "unsupported_type"
+	UploadErrorInvalidImage    = "invalid_image"
+	UploadErrorQuotaExceeded   = "quota_exceeded"
+	UploadErrorInternal        = "internal_error"
+)
+
+// uploadFailure - falha de um arquivo do upload com o código e a mensagem exibidos ao cliente
+type uploadFailure struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+// Error - implementação da interface error
+func (e *uploadFailure) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap - expõe o erro original para errors.Is e errors.As
+func (e *uploadFailure) Unwrap() error {
+	return e.Err
+}
+
+// failUpload - cria a falha de um arquivo do upload
+func failUpload(code, message string, err error) error {
+	return &uploadFailure{Code: code, Message: message, Err: err}
+}
+
+// uploadErrorDetails - código e mensagem do resultado de um arquivo que falhou; erros internos
+// recebem mensagem genérica e são registrados no log
+func uploadErrorDetails(filename string, err error) (string, string) {
+	var failure *uploadFailure
+	if errors.As(err, &failure) {
+		return failure.Code, failure.Message
+	}
+
+	var appErr *util.AppError
+	if errors.As(err, &appErr) && appErr.StatusCode == http.StatusRequestEntityTooLarge {
+		return UploadErrorQuotaExceeded, appErr.Msg
+	}
+
+	log.Printf("erro ao enviar imagem %s: %v", filename, err)
+	return UploadErrorInternal, "Erro ao salvar a imagem. Tente novamente"
+}
+
+// UploadStatusCode - status HTTP da resposta do upload: 201 quando todos os arquivos foram gravados, 202 quando algum
+// ainda está em processamento e 207 quando parte falhou. Se todos falharam, 413 quando todos excederam a cota,
+// 500 quando todos falharam por erro interno e 400 nos demais casos.
+func UploadStatusCode(response *contract.UploadImagesResponse) int {
+
+	if response.TotalFailed == 0 {
+		if response.TotalProcessing > 0 {
+			return http.StatusAccepted
+		}
+		return http.StatusCreated
+	}
+
+	if response.TotalUploaded > 0 {
+		return http.StatusMultiStatus
+	}
+
+	quotaFailures, internalFailures := 0, 0
+	for _, result := range response.Results {
+		switch result.Code {
+		case UploadErrorQuotaExceeded:
+			quotaFailures++
+		case UploadErrorInternal:
+			internalFailures++
+		}
+	}
+
+	switch response.TotalFailed {
+	case quotaFailures:
+		return http.StatusRequestEntityTooLarge
+	case internalFailures:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
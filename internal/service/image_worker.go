@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/pkg/config"
+	"gorm.io/gorm"
+)
+
+// Valores do processamento assíncrono de imagens
+const (
+	DefaultImageWorkers  = 4
+	imageJobMaxAttempts  = 3
+	imageJobPollInterval = 5 * time.Second
+	imageJobStaleAfter   = 10 * time.Minute
+	imageJobRetryBackoff = 30 * time.Second
+)
+
+// imageJobSignal - acorda um worker desta instância quando um job é criado, sem esperar o próximo ciclo de consulta.
+// Jobs criados por outras réplicas ou devolvidos para nova tentativa são encontrados pela consulta periódica.
+var imageJobSignal = make(chan struct{}, 1)
+
+// notifyImageWorkers - avisa os workers que há job disponível, sem bloquear quando o aviso já está pendente
+func notifyImageWorkers() {
+	select {
+	case imageJobSignal <- struct{}{}:
+	default:
+	}
+}
+
+// imageWorkerCount - quantidade de workers configurada em IMAGE_WORKERS; zero processa as imagens durante o upload
+func imageWorkerCount(cfg *config.Config) int {
+	if cfg.ImageWorkers == "" {
+		return DefaultImageWorkers
+	}
+
+	workers, err := strconv.Atoi(cfg.ImageWorkers)
+	if err != nil || workers < 0 {
+		return DefaultImageWorkers
+	}
+
+	return workers
+}
+
+// ImageWorkerPool - objeto de contexto
+type ImageWorkerPool struct {
+	ImageService        *ImageService
+	NotificationService *NotificationService
+	Workers             int
+}
+
+// ImageWorkerPoolNew - construtor do objeto
+func ImageWorkerPoolNew(DB *gorm.DB) *ImageWorkerPool {
+	cfg, _ := config.LoadConfig()
+
+	return &ImageWorkerPool{
+		ImageService:        ImageServiceNew(DB),
+		NotificationService: NotificationServiceNew(DB),
+		Workers:             imageWorkerCount(cfg),
+	}
+}
+
+// Start - inicia os workers, cada um em sua goroutine, até o contexto ser cancelado
+func (p *ImageWorkerPool) Start(ctx context.Context) {
+	if p.Workers <= 0 {
+		log.Printf("processamento assíncrono de imagens desabilitado")
+		return
+	}
+
+	for i := 0; i < p.Workers; i++ {
+		go p.loop(ctx)
+	}
+}
+
+// loop - processa os jobs disponíveis e aguarda um aviso ou o próximo ciclo de consulta
+func (p *ImageWorkerPool) loop(ctx context.Context) {
+	ticker := time.NewTicker(imageJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			processed, err := p.RunNext(ctx)
+			if err != nil {
+				log.Printf("erro ao buscar job de processamento de imagem: %s", err.Error())
+				break
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-imageJobSignal:
+		}
+	}
+}
+
+// RunNext - reserva e processa o próximo job disponível; retorna false quando a fila está vazia
+func (p *ImageWorkerPool) RunNext(ctx context.Context) (bool, error) {
+	job, err := p.ImageService.ImageRepository.ClaimJob(imageJobStaleAfter)
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	// pode haver mais jobs na fila: acorda outro worker para processá-los em paralelo
+	notifyImageWorkers()
+
+	image, err := p.process(ctx, job)
+	if err != nil {
+		p.handleFailure(job, image, err)
+		return true, nil
+	}
+
+	if image != nil {
+		p.notify(image, model.NotificationImageReady, "Imagem pronta",
+			fmt.Sprintf("A imagem %s foi processada e já pode ser usada", image.OriginalName))
+	}
+
+	return true, nil
+}
+
+// process - gera os arquivos da imagem a partir do arquivo enviado e conclui o job.
+// Retorna a imagem nil quando ela foi excluída antes do fim do processamento.
+func (p *ImageWorkerPool) process(ctx context.Context, job *model.ImageJob) (*model.Image, error) {
+	s := p.ImageService
+
+	image, err := s.ImageRepository.GetByID(job.ImageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.cleanupFiles(job.SourceKey)
+		return nil, s.ImageRepository.DeleteJob(job.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// job reassumido depois que outro worker já concluiu a imagem
+	if image.Status != model.ImageStatusProcessing {
+		s.cleanupFiles(job.SourceKey)
+		return nil, s.ImageRepository.DeleteJob(job.ID)
+	}
+
+	fileData, err := s.Storage.Get(ctx, job.SourceKey)
+	if err != nil {
+		return image, err
+	}
+
+	renditions, err := s.storeImageFiles(ctx, fileData, image)
+	if err != nil {
+		return image, err
+	}
+	files := append([]string{image.URL, image.ThumbnailURL}, renditionKeys(renditions)...)
+
+	completed, err := s.ImageRepository.CompleteProcessing(image, renditions, job.ID)
+	if err != nil {
+		s.cleanupFiles(files...)
+		return image, err
+	}
+
+	s.cleanupFiles(job.SourceKey)
+
+	if !completed {
+		// as chaves dos arquivos são as mesmas entre tentativas: só são removidos se a imagem foi excluída,
+		// e não quando outro worker concluiu o mesmo job
+		if exists, err := s.ImageRepository.Exists(image.ID); err == nil && !exists {
+			s.cleanupFiles(files...)
+		}
+		return nil, nil
+	}
+
+	return image, nil
+}
+
+// handleFailure - devolve o job para a fila com espera crescente ou, esgotadas as tentativas, marca a imagem como falha
+func (p *ImageWorkerPool) handleFailure(job *model.ImageJob, image *model.Image, cause error) {
+	s := p.ImageService
+	log.Printf("erro ao processar imagem %d (tentativa %d): %s", job.ImageID, job.Attempts, cause.Error())
+
+	if job.Attempts < imageJobMaxAttempts {
+		delay := time.Duration(job.Attempts*job.Attempts) * imageJobRetryBackoff
+		if err := s.ImageRepository.RetryJob(job.ID, cause.Error(), delay); err != nil {
+			log.Printf("erro ao reagendar processamento da imagem %d: %s", job.ImageID, err.Error())
+		}
+		return
+	}
+
+	if err := s.ImageRepository.FailProcessing(job, cause.Error()); err != nil {
+		log.Printf("erro ao registrar falha no processamento da imagem %d: %s", job.ImageID, err.Error())
+		return
+	}
+
+	s.cleanupFiles(job.SourceKey)
+
+	if image != nil {
+		p.notify(image, model.NotificationImageFailed, "Falha ao processar imagem",
+			fmt.Sprintf("Não foi possível processar a imagem %s. Envie o arquivo novamente", image.OriginalName))
+	}
+}
+
+// notify - avisa a empresa dona da imagem sobre o fim do processamento; só empresas enviam imagens
+func (p *ImageWorkerPool) notify(image *model.Image, notificationType, title, message string) {
+	if p.NotificationService == nil {
+		return
+	}
+
+	// a falha na notificação não altera o resultado do processamento
+	if err := p.NotificationService.NotifyCompany(image.UserID, notificationType, title, message, &image.ID); err != nil {
+		log.Printf("erro ao notificar processamento da imagem %d: %s", image.ID, err.Error())
+	}
+}
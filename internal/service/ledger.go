@@ -0,0 +1,465 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/util"
+	"gorm.io/gorm"
+)
+
+// DefaultCommissionRate - percentual de comissão aplicado às empresas sem configuração própria
+const DefaultCommissionRate = 10.0
+
+// LedgerService - objeto de contexto
+type LedgerService struct {
+	LedgerRepository            *repository.LedgerRepository
+	CompanyCommissionRepository *repository.CompanyCommissionRepository
+	SellerTokenCipher           *SellerTokenCipher
+	DefaultRate                 float64
+}
+
+// LedgerServiceNew - construtor do objeto
+func LedgerServiceNew(DB *gorm.DB) *LedgerService {
+	cfg, _ := config.LoadConfig()
+
+	defaultRate := DefaultCommissionRate
+	if rate, err := strconv.ParseFloat(cfg.PlatformCommissionRate, 64); err == nil && rate >= 0 && rate <= 100 {
+		defaultRate = rate
+	}
+
+	return &LedgerService{
+		LedgerRepository:            repository.LedgerRepositoryNew(DB),
+		CompanyCommissionRepository: repository.CompanyCommissionRepositoryNew(DB),
+		SellerTokenCipher:           newSellerTokenCipher(cfg),
+		DefaultRate:                 defaultRate,
+	}
+}
+
+// CommissionFor - retorna a comissão da empresa, ou a comissão padrão da plataforma quando não configurada
+func (s *LedgerService) CommissionFor(companyID int) (*model.CompanyCommission, error) {
+
+	commission, err := s.CompanyCommissionRepository.GetByCompanyID(companyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &model.CompanyCommission{CompanyID: companyID, Rate: s.DefaultRate}, nil
+		}
+		return nil, util.WrapError("erro ao buscar comissão da empresa", err, http.StatusInternalServerError)
+	}
+
+	return commission, nil
+}
+
+// SellerToken - decifra o access token da empresa vinculada ao marketplace
+func (s *LedgerService) SellerToken(commission *model.CompanyCommission) (string, error) {
+
+	token, err := s.SellerTokenCipher.Decrypt(commission.MercadoPagoSellerToken)
+	if err != nil {
+		return "", util.WrapError("erro ao decifrar access token da empresa", err, http.StatusInternalServerError)
+	}
+
+	return token, nil
+}
+
+// GetCommission - retorna a comissão aplicada à empresa
+func (s *LedgerService) GetCommission(companyID int) (*contract.CompanyCommissionResponse, error) {
+
+	commission, err := s.CommissionFor(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contract.CompanyCommissionResponse{
+		CompanyID: commission.CompanyID,
+		Rate:      commission.Rate,
+		IsDefault: commission.CreatedAt.IsZero(),
+		HasSplit:  commission.HasSplit(),
+	}, nil
+}
+
+// SetCommission - configura a comissão e o vínculo de marketplace de uma empresa
+func (s *LedgerService) SetCommission(companyID int, rate float64, sellerToken string) (*contract.CompanyCommissionResponse, error) {
+
+	if rate < 0 || rate > 100 {
+		return nil, util.WrapError("percentual de comissão deve estar entre 0 e 100", nil, http.StatusUnprocessableEntity)
+	}
+
+	commission, err := s.CompanyCommissionRepository.GetByCompanyID(companyID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, util.WrapError("erro ao buscar comissão da empresa", err, http.StatusInternalServerError)
+	}
+
+	now := time.Now()
+	if commission == nil {
+		commission = &model.CompanyCommission{CompanyID: companyID, CreatedAt: now}
+	}
+
+	commission.Rate = rate
+	if sellerToken != "" {
+		encrypted, err := s.SellerTokenCipher.Encrypt(sellerToken)
+		if err != nil {
+			return nil, util.WrapError("erro ao cifrar access token da empresa", err, http.StatusInternalServerError)
+		}
+		commission.MercadoPagoSellerToken = encrypted
+	}
+	commission.UpdatedAt = now
+
+	if err := s.CompanyCommissionRepository.Save(commission); err != nil {
+		return nil, util.WrapError("erro ao salvar comissão da empresa", err, http.StatusInternalServerError)
+	}
+
+	return &contract.CompanyCommissionResponse{
+		CompanyID: commission.CompanyID,
+		Rate:      commission.Rate,
+		IsDefault: false,
+		HasSplit:  commission.HasSplit(),
+	}, nil
+}
+
+// RecordSale - registra o lançamento de venda de um pagamento aprovado, uma única vez por pagamento
+func (s *LedgerService) RecordSale(payment *model.Pagamento) error {
+
+	exists, err := s.LedgerRepository.ExistsForPayment(payment.ID, string(model.LedgerEntrySale))
+	if err != nil {
+		return util.WrapError("erro ao verificar lançamentos do pagamento", err, http.StatusInternalServerError)
+	}
+	if exists {
+		return nil
+	}
+
+	rate := s.DefaultRate
+	fee := payment.TaxaPlataforma
+	if commission, err := s.CommissionFor(payment.EmpresaID); err == nil {
+		rate = commission.Rate
+		if fee == 0 {
+			fee = commission.Fee(payment.Valor)
+		}
+	}
+
+	if err := s.create(payment, model.LedgerEntrySale, "", payment.Valor, fee, rate); err != nil {
+		return err
+	}
+
+	// no split o Mercado Pago repassa o líquido direto à empresa, que é quitado no mesmo momento
+	if payment.SplitMarketplace {
+		net := model.RoundCents(payment.Valor - fee)
+		return s.create(payment, model.LedgerEntrySplitSettlement, "Repasse direto via split do Mercado Pago", -net, 0, rate)
+	}
+
+	return nil
+}
+
+// RecordRefund - registra o estorno proporcional do valor reembolsado ainda não lançado; reembolsos parciais
+// mantêm o pagamento aprovado e geram um lançamento a cada aumento do valor reembolsado
+func (s *LedgerService) RecordRefund(payment *model.Pagamento) error {
+
+	refunded := payment.TransactionAmountRefunded
+	if refunded > payment.Valor || (refunded <= 0 && payment.Status == string(model.StatusRefunded)) {
+		refunded = payment.Valor
+	}
+	if refunded <= 0 {
+		return nil
+	}
+
+	sold, err := s.LedgerRepository.ExistsForPayment(payment.ID, string(model.LedgerEntrySale))
+	if err != nil {
+		return util.WrapError("erro ao verificar lançamentos do pagamento", err, http.StatusInternalServerError)
+	}
+	if !sold {
+		return nil
+	}
+
+	// os estornos já lançados têm valor bruto negativo
+	recorded, err := s.LedgerRepository.SumGrossForPayment(payment.ID, string(model.LedgerEntryRefund))
+	if err != nil {
+		return util.WrapError("erro ao somar estornos do pagamento", err, http.StatusInternalServerError)
+	}
+
+	amount := model.RoundCents(refunded + recorded)
+	if amount <= 0 {
+		return nil
+	}
+
+	fee, rate := s.proportionalFee(payment, amount)
+
+	return s.create(payment, model.LedgerEntryRefund, "", -amount, -fee, rate)
+}
+
+// RecordChargeback - registra a reversão da venda quando o pagamento sofre chargeback; o valor já estornado por
+// reembolsos parciais não é revertido de novo
+func (s *LedgerService) RecordChargeback(payment *model.Pagamento) error {
+
+	exists, err := s.LedgerRepository.ExistsForPayment(payment.ID, string(model.LedgerEntryChargeback))
+	if err != nil {
+		return util.WrapError("erro ao verificar lançamentos do pagamento", err, http.StatusInternalServerError)
+	}
+	if exists {
+		return nil
+	}
+
+	sold, err := s.LedgerRepository.ExistsForPayment(payment.ID, string(model.LedgerEntrySale))
+	if err != nil {
+		return util.WrapError("erro ao verificar lançamentos do pagamento", err, http.StatusInternalServerError)
+	}
+	if !sold {
+		return nil
+	}
+
+	// os estornos já lançados têm valor bruto negativo
+	refunded, err := s.LedgerRepository.SumGrossForPayment(payment.ID, string(model.LedgerEntryRefund))
+	if err != nil {
+		return util.WrapError("erro ao somar estornos do pagamento", err, http.StatusInternalServerError)
+	}
+
+	amount := model.RoundCents(payment.Valor + refunded)
+	if amount <= 0 {
+		return nil
+	}
+
+	fee, rate := s.proportionalFee(payment, amount)
+
+	return s.create(payment, model.LedgerEntryChargeback, "", -amount, -fee, rate)
+}
+
+// RecordChargebackWon - devolve à empresa exatamente o valor revertido pelo chargeback quando a disputa é ganha
+func (s *LedgerService) RecordChargebackWon(payment *model.Pagamento) error {
+
+	exists, err := s.LedgerRepository.ExistsForPayment(payment.ID, string(model.LedgerEntryChargebackWon))
+	if err != nil {
+		return util.WrapError("erro ao verificar lançamentos do pagamento", err, http.StatusInternalServerError)
+	}
+	if exists {
+		return nil
+	}
+
+	chargeback, err := s.LedgerRepository.GetForPayment(payment.ID, string(model.LedgerEntryChargeback))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return util.WrapError("erro ao buscar chargeback do pagamento", err, http.StatusInternalServerError)
+	}
+
+	return s.create(payment, model.LedgerEntryChargebackWon, "Disputa de chargeback ganha", -chargeback.GrossAmount, -chargeback.FeeAmount, chargeback.CommissionRate)
+}
+
+// proportionalFee - calcula a comissão proporcional a um valor do pagamento e o percentual aplicado
+func (s *LedgerService) proportionalFee(payment *model.Pagamento, amount float64) (float64, float64) {
+
+	rate := s.DefaultRate
+	if commission, err := s.CommissionFor(payment.EmpresaID); err == nil {
+		rate = commission.Rate
+	}
+
+	fee := model.RoundCents(amount * rate / 100)
+	if payment.TaxaPlataforma > 0 && payment.Valor > 0 {
+		fee = model.RoundCents(payment.TaxaPlataforma * amount / payment.Valor)
+	}
+
+	return fee, rate
+}
+
+// RecordPayout - registra um repasse feito pela plataforma à empresa
+func (s *LedgerService) RecordPayout(companyID int, amount float64, reference string) (*contract.StatementTransaction, error) {
+
+	if amount <= 0 {
+		return nil, util.WrapError("valor do repasse deve ser maior que zero", nil, http.StatusUnprocessableEntity)
+	}
+
+	entry := &model.LedgerEntry{
+		CompanyID:   companyID,
+		Type:        string(model.LedgerEntryPayout),
+		Description: reference,
+		GrossAmount: -model.RoundCents(amount),
+		NetAmount:   -model.RoundCents(amount),
+		Currency:    string(model.MoedaBRL),
+		CreatedAt:   time.Now(),
+	}
+	entry.Postings = entry.BuildPostings()
+
+	if err := s.LedgerRepository.Create(entry); err != nil {
+		return nil, util.WrapError("erro ao registrar repasse", err, http.StatusInternalServerError)
+	}
+
+	transaction := toStatementTransaction(entry)
+	return &transaction, nil
+}
+
+// GetBalance - retorna o saldo devido à empresa e o resumo dos lançamentos
+func (s *LedgerService) GetBalance(companyID int) (*contract.CompanyBalanceResponse, error) {
+
+	totals, err := s.LedgerRepository.TotalsByType(companyID)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar lançamentos da empresa", err, http.StatusInternalServerError)
+	}
+
+	balance, err := s.LedgerRepository.PayableBalance(companyID, time.Now())
+	if err != nil {
+		return nil, util.WrapError("erro ao calcular saldo da empresa", err, http.StatusInternalServerError)
+	}
+
+	response := &contract.CompanyBalanceResponse{
+		CompanyID: companyID,
+		Currency:  string(model.MoedaBRL),
+		Balance:   model.RoundCents(balance),
+	}
+
+	for _, total := range totals {
+		response.Fees += total.FeeAmount
+		switch model.LedgerEntryType(total.Type) {
+		case model.LedgerEntrySale:
+			response.GrossSales = total.GrossAmount
+		case model.LedgerEntryRefund:
+			response.Refunds = -total.GrossAmount
+		case model.LedgerEntryChargeback, model.LedgerEntryChargebackWon:
+			response.Chargebacks += -total.GrossAmount
+		case model.LedgerEntrySplitSettlement, model.LedgerEntryPayout:
+			response.Settled += -total.NetAmount
+		}
+	}
+	response.Fees = model.RoundCents(response.Fees)
+	response.Chargebacks = model.RoundCents(response.Chargebacks)
+	response.Settled = model.RoundCents(response.Settled)
+
+	return response, nil
+}
+
+// GetStatement - monta o extrato mensal da empresa; o período segue o formato AAAA-MM
+func (s *LedgerService) GetStatement(companyID int, period string) (*contract.StatementResponse, error) {
+
+	start, err := time.ParseInLocation("2006-01", period, time.Local)
+	if err != nil {
+		return nil, util.WrapError("período inválido, utilize o formato AAAA-MM", err, http.StatusBadRequest)
+	}
+	end := start.AddDate(0, 1, 0)
+
+	opening, err := s.LedgerRepository.PayableBalance(companyID, start)
+	if err != nil {
+		return nil, util.WrapError("erro ao calcular saldo inicial", err, http.StatusInternalServerError)
+	}
+
+	entries, err := s.LedgerRepository.GetByCompanyIDAndPeriod(companyID, start, end)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar lançamentos do período", err, http.StatusInternalServerError)
+	}
+
+	response := &contract.StatementResponse{
+		CompanyID:      companyID,
+		Period:         period,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Currency:       string(model.MoedaBRL),
+		OpeningBalance: model.RoundCents(opening),
+		Transactions:   make([]contract.StatementTransaction, 0, len(entries)),
+	}
+
+	for i := range entries {
+		response.TotalGross += entries[i].GrossAmount
+		response.TotalFees += entries[i].FeeAmount
+		response.TotalNet += entries[i].NetAmount
+		response.Transactions = append(response.Transactions, toStatementTransaction(&entries[i]))
+	}
+
+	response.TotalGross = model.RoundCents(response.TotalGross)
+	response.TotalFees = model.RoundCents(response.TotalFees)
+	response.TotalNet = model.RoundCents(response.TotalNet)
+	response.ClosingBalance = model.RoundCents(response.OpeningBalance + response.TotalNet)
+
+	return response, nil
+}
+
+// StatementCSV - converte o extrato mensal para CSV
+func (s *LedgerService) StatementCSV(statement *contract.StatementResponse) ([]byte, error) {
+
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+
+	money := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	rows := [][]string{{"data", "lancamento_id", "pagamento_id", "tipo", "descricao", "valor_bruto", "comissao", "valor_liquido", "percentual_comissao"}}
+	for _, transaction := range statement.Transactions {
+		pagamentoID := ""
+		if transaction.PagamentoID != nil {
+			pagamentoID = strconv.Itoa(*transaction.PagamentoID)
+		}
+		rows = append(rows, []string{
+			transaction.Date.Format("2006-01-02 15:04:05"),
+			strconv.Itoa(transaction.EntryID),
+			pagamentoID,
+			transaction.Type,
+			transaction.Description,
+			money(transaction.GrossAmount),
+			money(transaction.FeeAmount),
+			money(transaction.NetAmount),
+			money(transaction.CommissionRate),
+		})
+	}
+	rows = append(rows,
+		[]string{"", "", "", "saldo_inicial", "", "", "", money(statement.OpeningBalance), ""},
+		[]string{"", "", "", "total", "", money(statement.TotalGross), money(statement.TotalFees), money(statement.TotalNet), ""},
+		[]string{"", "", "", "saldo_final", "", "", "", money(statement.ClosingBalance), ""},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, util.WrapError("erro ao gerar CSV do extrato", err, http.StatusInternalServerError)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// create - persiste um lançamento e suas partidas dobradas, calculando o valor líquido
+func (s *LedgerService) create(payment *model.Pagamento, entryType model.LedgerEntryType, description string, gross, fee, rate float64) error {
+
+	pagamentoID := payment.ID
+	entry := &model.LedgerEntry{
+		PagamentoID:    &pagamentoID,
+		CompanyID:      payment.EmpresaID,
+		Type:           string(entryType),
+		Description:    description,
+		GrossAmount:    model.RoundCents(gross),
+		FeeAmount:      model.RoundCents(fee),
+		NetAmount:      model.RoundCents(gross - fee),
+		CommissionRate: rate,
+		Currency:       payment.Moeda,
+		CreatedAt:      time.Now(),
+	}
+	entry.Postings = entry.BuildPostings()
+
+	// o líquido do split nunca passou pelo saldo devido à empresa, que já foi quitado no repasse da venda
+	switch entryType {
+	case model.LedgerEntryRefund, model.LedgerEntryChargeback, model.LedgerEntryChargebackWon:
+		if payment.SplitMarketplace {
+			entry.Postings = entry.BuildSplitPostings()
+		}
+	}
+
+	if err := s.LedgerRepository.Create(entry); err != nil {
+		return util.WrapError("erro ao registrar lançamento do pagamento", err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// toStatementTransaction - converte um lançamento para o formato do extrato
+func toStatementTransaction(entry *model.LedgerEntry) contract.StatementTransaction {
+	return contract.StatementTransaction{
+		EntryID:        entry.ID,
+		PagamentoID:    entry.PagamentoID,
+		Date:           entry.CreatedAt,
+		Type:           entry.Type,
+		Description:    entry.Description,
+		GrossAmount:    entry.GrossAmount,
+		FeeAmount:      entry.FeeAmount,
+		NetAmount:      entry.NetAmount,
+		CommissionRate: entry.CommissionRate,
+	}
+}
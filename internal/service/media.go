@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+	"gorm.io/gorm"
+)
+
+// MediaCacheControl - as chaves são únicas por upload, então o conteúdo pode ficar em cache indefinidamente
+const MediaCacheControl = "public, max-age=31536000, immutable"
+
+// privateMediaCacheControl - arquivos privados ficam apenas no cache do navegador, até a URL expirar
+const privateMediaCacheControl = "private, max-age=%d"
+
+// mediaPublicPrefix - apenas as imagens são servidas publicamente; os demais arquivos do armazenamento ficam restritos
+const mediaPublicPrefix = "images/"
+
+// MediaService - objeto de contexto
+type MediaService struct {
+	Storage         storage.BlobStore
+	ImageRepository *repository.ImageRepository
+	Signer          *MediaSigner
+}
+
+// MediaFile - arquivo lido do armazenamento com os metadados usados na resposta HTTP
+type MediaFile struct {
+	Key         string
+	Data        []byte
+	ContentType string
+	ETag        string
+	ExpiresAt   *time.Time
+}
+
+// MediaServiceNew - construtor do objeto
+func MediaServiceNew(DB *gorm.DB) *MediaService {
+	cfg, _ := config.LoadConfig()
+
+	return &MediaService{
+		Storage:         newBlobStore(cfg),
+		ImageRepository: repository.ImageRepositoryNew(DB),
+		Signer:          newMediaSigner(cfg),
+	}
+}
+
+// Get - lê o arquivo da chave informada. Com assinatura, a URL precisa ser válida e não expirada; sem ela, arquivos
+// de imagens privadas respondem como inexistentes, para não revelar que existem.
+func (s *MediaService) Get(ctx context.Context, key, expires, signature string) (*MediaFile, error) {
+
+	if !strings.HasPrefix(key, mediaPublicPrefix) || storage.ValidateKey(key) != nil {
+		return nil, util.WrapError("arquivo não encontrado", nil, http.StatusNotFound)
+	}
+
+	var expiresAt *time.Time
+	if signature != "" || expires != "" {
+		validUntil, ok := s.Signer.Verify(key, expires, signature, time.Now())
+		if !ok {
+			return nil, util.WrapError("link do arquivo inválido ou expirado", nil, http.StatusForbidden)
+		}
+		expiresAt = &validUntil
+	} else {
+		private, err := s.ImageRepository.IsPrivateKey(key)
+		if err != nil {
+			return nil, util.WrapError("erro ao verificar acesso ao arquivo", err, http.StatusInternalServerError)
+		}
+		if private {
+			return nil, util.WrapError("arquivo não encontrado", nil, http.StatusNotFound)
+		}
+	}
+
+	data, err := s.Storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, util.WrapError("arquivo não encontrado", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao ler arquivo do armazenamento", err, http.StatusInternalServerError)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return &MediaFile{
+		Key:         key,
+		Data:        data,
+		ContentType: mediaContentType(key, data),
+		ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// CacheControl - cabeçalho Cache-Control da resposta: arquivos entregues por URL assinada não vão para caches
+// compartilhados e expiram junto com a URL
+func (f *MediaFile) CacheControl(now time.Time) string {
+	if f.ExpiresAt == nil {
+		return MediaCacheControl
+	}
+
+	maxAge := int(f.ExpiresAt.Sub(now).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	return fmt.Sprintf(privateMediaCacheControl, maxAge)
+}
+
+// NotModified - indica se o cabeçalho If-None-Match já contém a versão atual do arquivo
+func (f *MediaFile) NotModified(ifNoneMatch string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == f.ETag {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaContentType - Content-Type pela extensão da chave, recorrendo ao conteúdo quando ela é desconhecida
+func mediaContentType(key string, data []byte) string {
+	format := strings.TrimPrefix(strings.ToLower(path.Ext(key)), ".")
+	if contentType := imageContentType(format); contentType != "application/octet-stream" {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
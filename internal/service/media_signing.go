@@ -0,0 +1,88 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// DefaultMediaURLTTL - validade mínima das URLs assinadas das imagens privadas
+const DefaultMediaURLTTL = time.Hour
+
+// MediaSigner - assina e valida as URLs com validade usadas para entregar as imagens privadas
+type MediaSigner struct {
+	Secret []byte
+	TTL    time.Duration
+}
+
+// newMediaSigner - cria o assinador com MEDIA_URL_SECRET; sem ela, a chave é derivada de JWT_SECRET
+func newMediaSigner(cfg *config.Config) *MediaSigner {
+
+	secret := []byte(cfg.MediaURLSecret)
+	if len(secret) == 0 {
+		mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+		mac.Write([]byte("media-url"))
+		secret = mac.Sum(nil)
+	}
+
+	return &MediaSigner{
+		Secret: secret,
+		TTL:    util.ParseDurationOrDefault(cfg.MediaURLTTL, DefaultMediaURLTTL),
+	}
+}
+
+// Expiration - expiração das URLs assinadas agora. Ela é alinhada a múltiplos da validade, de modo que a URL de
+// uma imagem não muda dentro de cada janela e continua aproveitando o cache do navegador; a URL fica válida por um
+// tempo entre TTL e 2×TTL.
+func (s *MediaSigner) Expiration(now time.Time) time.Time {
+	return now.Truncate(s.TTL).Add(2 * s.TTL)
+}
+
+// Sign - assina a chave e retorna a expiração e a assinatura
+func (s *MediaSigner) Sign(key string, now time.Time) (time.Time, string) {
+	expires := s.Expiration(now)
+	return expires, s.signature(key, expires.Unix())
+}
+
+// Verify - valida a assinatura e a expiração recebidas na query string da URL
+func (s *MediaSigner) Verify(key, expires, signature string, now time.Time) (time.Time, bool) {
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	expiresAt := time.Unix(unix, 0)
+	if !now.Before(expiresAt) {
+		return time.Time{}, false
+	}
+
+	received, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(s.mac(key, unix), received) {
+		return time.Time{}, false
+	}
+
+	return expiresAt, true
+}
+
+// signature - assinatura da chave e da expiração, em hexadecimal
+func (s *MediaSigner) signature(key string, expires int64) string {
+	return hex.EncodeToString(s.mac(key, expires))
+}
+
+// mac - HMAC-SHA256 da chave e da expiração
+func (s *MediaSigner) mac(key string, expires int64) []byte {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return mac.Sum(nil)
+}
+
+// signedMediaURL - URL pública da chave com a expiração e a assinatura na query string
+func signedMediaURL(baseURL, key string, expires time.Time, signature string) string {
+	return mediaURL(baseURL, key) + "?expires=" + strconv.FormatInt(expires.Unix(), 10) + "&signature=" + signature
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/mercadopago"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// newMercadoPagoClient - cria o cliente do Mercado Pago com o timeout, as retentativas e o circuit breaker configurados
+func newMercadoPagoClient(cfg *config.Config) *mercadopago.Client {
+
+	options := mercadopago.DefaultOptions()
+	options.Timeout = util.ParseDurationOrDefault(cfg.MercadoPagoTimeout, mercadopago.DefaultTimeout)
+	options.BreakerCooldown = util.ParseDurationOrDefault(cfg.MercadoPagoBreakerCooldown, mercadopago.DefaultBreakerCooldown)
+
+	if retries, err := strconv.Atoi(cfg.MercadoPagoMaxRetries); err == nil && retries >= 0 {
+		options.MaxRetries = retries
+	}
+
+	if threshold, err := strconv.Atoi(cfg.MercadoPagoBreakerThreshold); err == nil && threshold >= 0 {
+		options.BreakerThreshold = threshold
+	}
+
+	return mercadopago.NewClientWithOptions(cfg.MercadoPagoAccessToken, cfg.MercadoPagoBaseURL, options)
+}
+
+// mercadoPagoPaymentError - traduz a falha na criação de um pagamento, separando a recusa do emissor da indisponibilidade do gateway
+func mercadoPagoPaymentError(err error) error {
+	switch {
+	case errors.Is(err, mercadopago.ErrDeclined):
+		return util.WrapError("pagamento recusado pelo emissor, verifique os dados ou utilize outro meio de pagamento", err, http.StatusPaymentRequired)
+	case errors.Is(err, mercadopago.ErrUnavailable):
+		return util.WrapError("não foi possível processar o pagamento agora, o Mercado Pago está indisponível; tente novamente em instantes", err, http.StatusServiceUnavailable)
+	default:
+		return err
+	}
+}
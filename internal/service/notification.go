@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/pkg/util"
+	"gorm.io/gorm"
+)
+
+// notificationsLimit - quantidade máxima de notificações retornadas na listagem
+const notificationsLimit = 100
+
+// NotificationService - objeto de contexto
+type NotificationService struct {
+	NotificationRepository *repository.NotificationRepository
+}
+
+// NotificationServiceNew - construtor do objeto
+func NotificationServiceNew(DB *gorm.DB) *NotificationService {
+	return &NotificationService{
+		NotificationRepository: repository.NotificationRepositoryNew(DB),
+	}
+}
+
+// NotifyCompany - registra uma notificação para a empresa
+func (s *NotificationService) NotifyCompany(companyID int, notificationType, title, message string, referenceID *int) error {
+
+	notification := &model.Notification{
+		CompanyID:   companyID,
+		Type:        notificationType,
+		Title:       title,
+		Message:     message,
+		ReferenceID: referenceID,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.NotificationRepository.Create(notification); err != nil {
+		return util.WrapError("erro ao registrar notificação", err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// List - lista as notificações da empresa
+func (s *NotificationService) List(companyID int, unreadOnly bool) (*contract.ListNotificationsResponse, error) {
+
+	notifications, err := s.NotificationRepository.ListByCompanyID(companyID, unreadOnly, notificationsLimit)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar notificações", err, http.StatusInternalServerError)
+	}
+
+	response := &contract.ListNotificationsResponse{
+		Notifications: make([]contract.NotificationResponse, 0, len(notifications)),
+		Total:         len(notifications),
+	}
+
+	for _, notification := range notifications {
+		response.Notifications = append(response.Notifications, contract.NotificationResponse{
+			ID:          notification.ID,
+			Type:        notification.Type,
+			Title:       notification.Title,
+			Message:     notification.Message,
+			ReferenceID: notification.ReferenceID,
+			ReadAt:      notification.ReadAt,
+			CreatedAt:   notification.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// MarkAsRead - marca uma notificação da empresa como lida
+func (s *NotificationService) MarkAsRead(id, companyID int) (*contract.MarkNotificationReadResponse, error) {
+
+	found, err := s.NotificationRepository.MarkAsRead(id, companyID, time.Now())
+	if err != nil {
+		return nil, util.WrapError("erro ao atualizar notificação", err, http.StatusInternalServerError)
+	}
+
+	if !found {
+		return nil, util.WrapError("notificação não encontrada", nil, http.StatusNotFound)
+	}
+
+	return &contract.MarkNotificationReadResponse{Message: "Notificação marcada como lida"}, nil
+}
@@ -2,23 +2,52 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jampa_trip/internal/contract"
 	"github.com/jampa_trip/internal/model"
 	"github.com/jampa_trip/internal/repository"
 	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/database"
 	"github.com/jampa_trip/pkg/mercadopago"
 	"github.com/jampa_trip/pkg/util"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
+const (
+	// DefaultPIXExpiration - prazo padrão para pagamento de um PIX
+	DefaultPIXExpiration = 30 * time.Minute
+
+	// DefaultBoletoExpiration - prazo padrão de vencimento de um boleto
+	DefaultBoletoExpiration = 72 * time.Hour
+
+	installmentsCacheTTL   = time.Hour
+	paymentMethodsCacheKey = "mercadopago:payment_methods"
+	paymentMethodsCacheTTL = 24 * time.Hour
+)
+
 // PagamentoService - objeto de contexto
 type PagamentoService struct {
 	PagamentoRepository *repository.PagamentoRepository
+	ReservaRepository   *repository.ReservaRepository
+	TourRepository      *repository.TourRepository
+	CompanyRepository   *repository.CompanyRepository
+	ClientRepository    *repository.ClientRepository
+	LedgerService       *LedgerService
+	CartaoService       *CartaoService
+	DisputeService      *DisputeService
 	MPClient            *mercadopago.Client
+	Redis               *redis.Client
+	PIXExpiration       time.Duration
+	BoletoExpiration    time.Duration
 }
 
 // PagamentoServiceNew - construtor do objeto
@@ -27,7 +56,17 @@ func PagamentoServiceNew(DB *gorm.DB) *PagamentoService {
 
 	return &PagamentoService{
 		PagamentoRepository: repository.PagamentoRepositoryNew(DB),
-		MPClient:            mercadopago.NewClient(cfg.MercadoPagoAccessToken, cfg.MercadoPagoBaseURL),
+		ReservaRepository:   repository.ReservaRepositoryNew(DB),
+		TourRepository:      repository.TourRepositoryNew(DB),
+		CompanyRepository:   repository.CompanyRepositoryNew(DB),
+		ClientRepository:    repository.ClientRepositoryNew(DB),
+		LedgerService:       LedgerServiceNew(DB),
+		CartaoService:       CartaoServiceNew(DB),
+		DisputeService:      DisputeServiceNew(DB),
+		MPClient:            newMercadoPagoClient(cfg),
+		Redis:               database.RedisClient,
+		PIXExpiration:       util.ParseDurationOrDefault(cfg.PIXExpiration, DefaultPIXExpiration),
+		BoletoExpiration:    util.ParseDurationOrDefault(cfg.BoletoExpiration, DefaultBoletoExpiration),
 	}
 }
 
@@ -53,12 +92,19 @@ func (s *PagamentoService) modelToResponse(p *model.Pagamento) contract.PaymentR
 		CardholderName:            p.CardholderName,
 		Captured:                  p.Captured,
 		TransactionAmountRefunded: p.TransactionAmountRefunded,
+		TaxaPlataforma:            p.TaxaPlataforma,
 		MomentoCriacao:            p.MomentoCriacao,
 		MomentoAtualizacao:        p.MomentoAtualizacao,
 		MomentoAprovacao:          p.MomentoAprovacao,
 		MomentoCancelamento:       p.MomentoCancelamento,
 		MomentoAutorizacao:        p.MomentoAutorizacao,
 		MomentoCaptura:            p.MomentoCaptura,
+		ExpiraEm:                  p.ExpiraEm,
+		CodigoBarras:              p.CodigoBarras,
+		LinhaDigitavel:            p.LinhaDigitavel,
+		BoletoURL:                 p.BoletoURL,
+		QRCode:                    p.QRCode,
+		PixEndToEndID:             p.PixEndToEndID,
 		StatusDisplay:             p.GetStatusDisplay(),
 		MetodoPagamentoDisplay:    p.GetMetodoPagamentoDisplay(),
 	}
@@ -71,15 +117,21 @@ func (s *PagamentoService) CreateCreditCardPayment(ctx context.Context, req *con
 		return nil, util.WrapError("erro de validação", err, http.StatusBadRequest)
 	}
 
+	line, err := s.reservationCharge(req.ClienteID, req.ReservaID)
+	if err != nil {
+		return nil, err
+	}
+	reserva, amount := line.Reserva, line.Amount
+
 	mpReq := &mercadopago.CreditCardPaymentRequest{
-		TransactionAmount: req.TransactionAmount,
+		TransactionAmount: amount,
 		Token:             req.Token,
 		Description:       req.Description,
 		Installments:      req.Installments,
 		PaymentMethodID:   req.PaymentMethodID,
 		IssuerID:          req.IssuerID,
 		Capture:           req.Capture,
-		ExternalReference: req.ExternalReference,
+		ExternalReference: reservationReference(reserva, req.ExternalReference),
 		Payer: mercadopago.CreditCardPayer{
 			Email: req.Payer.Email,
 			Identification: mercadopago.CreditCardIdentification{
@@ -90,22 +142,50 @@ func (s *PagamentoService) CreateCreditCardPayment(ctx context.Context, req *con
 			LastName:  req.Payer.LastName,
 		},
 		Metadata: map[string]string{
-			"cliente_id": strconv.Itoa(req.ClienteID),
-			"empresa_id": strconv.Itoa(req.EmpresaID),
+			"cliente_id": strconv.Itoa(reserva.ClienteID),
+			"empresa_id": strconv.Itoa(reserva.EmpresaID),
+			"reserva_id": strconv.Itoa(reserva.ID),
 		},
 	}
 
-	mpResp, err := s.MPClient.CreateCreditCardPayment(ctx, mpReq)
+	split, err := s.prepareSplit(reserva.EmpresaID, amount)
 	if err != nil {
 		return nil, err
 	}
+	if split.Enabled {
+		mpReq.ApplicationFee = split.Fee
+	}
+
+	if req.UsesSavedCard() {
+		// o customer e os cartões são da conta da plataforma e não existem na conta do vendedor usada no split
+		if split.Enabled {
+			return nil, util.WrapError("cartões salvos não podem ser usados nos passeios desta empresa; informe os dados do cartão", nil, http.StatusUnprocessableEntity)
+		}
+
+		customer, card, err := s.CartaoService.ResolveCard(ctx, req.ClienteID, req.CardID)
+		if err != nil {
+			return nil, err
+		}
+
+		mpReq.PaymentMethodID = card.PaymentMethod.ID
+		mpReq.IssuerID = card.Issuer.ID
+		mpReq.Payer = mercadopago.CreditCardPayer{
+			Type: "customer",
+			ID:   customer.ID,
+		}
+	}
+
+	mpResp, err := split.Client.CreateCreditCardPayment(ctx, mpReq)
+	if err != nil {
+		return nil, mercadoPagoPaymentError(err)
+	}
 
 	statusMessage := s.getStatusDetailMessage(mpResp.StatusDetail)
 
 	now := time.Now()
 	payment := &model.Pagamento{
-		ClienteID:            req.ClienteID,
-		EmpresaID:            req.EmpresaID,
+		ClienteID:            reserva.ClienteID,
+		EmpresaID:            reserva.EmpresaID,
 		MercadoPagoPaymentID: strconv.FormatInt(mpResp.ID, 10),
 		Status:               mpResp.Status,
 		StatusDetail:         mpResp.StatusDetail,
@@ -120,6 +200,8 @@ func (s *PagamentoService) CreateCreditCardPayment(ctx context.Context, req *con
 		FirstSixDigits:       mpResp.Card.FirstSixDigits,
 		CardholderName:       mpResp.Card.Cardholder.Name,
 		Captured:             mpResp.Captured,
+		TaxaPlataforma:       split.Fee,
+		SplitMarketplace:     split.Enabled,
 		MomentoCriacao:       now,
 		MomentoAtualizacao:   now,
 	}
@@ -136,6 +218,12 @@ func (s *PagamentoService) CreateCreditCardPayment(ctx context.Context, req *con
 		return nil, util.WrapError("erro ao salvar pagamento", err, http.StatusInternalServerError)
 	}
 
+	if err := s.ReservaRepository.LinkPayment(reserva.ID, payment.ID); err != nil {
+		log.Printf("erro ao vincular pagamento %d à reserva %d: %v", payment.ID, reserva.ID, err)
+	}
+
+	s.recordLedger(payment)
+
 	return &contract.CreateCreditCardPaymentResponse{
 		Pagamento: s.modelToResponse(payment),
 		Message:   statusMessage,
@@ -149,15 +237,21 @@ func (s *PagamentoService) CreateDebitCardPayment(ctx context.Context, req *cont
 		return nil, util.WrapError("erro de validação", err, http.StatusBadRequest)
 	}
 
+	line, err := s.reservationCharge(req.ClienteID, req.ReservaID)
+	if err != nil {
+		return nil, err
+	}
+	reserva, amount := line.Reserva, line.Amount
+
 	mpReq := &mercadopago.CreditCardPaymentRequest{
-		TransactionAmount: req.TransactionAmount,
+		TransactionAmount: amount,
 		Token:             req.Token,
 		Description:       req.Description,
 		Installments:      1,
 		PaymentMethodID:   req.PaymentMethodID,
 		IssuerID:          req.IssuerID,
 		Capture:           true,
-		ExternalReference: req.ExternalReference,
+		ExternalReference: reservationReference(reserva, req.ExternalReference),
 		Payer: mercadopago.CreditCardPayer{
 			Email: req.Payer.Email,
 			Identification: mercadopago.CreditCardIdentification{
@@ -168,22 +262,31 @@ func (s *PagamentoService) CreateDebitCardPayment(ctx context.Context, req *cont
 			LastName:  req.Payer.LastName,
 		},
 		Metadata: map[string]string{
-			"cliente_id": strconv.Itoa(req.ClienteID),
-			"empresa_id": strconv.Itoa(req.EmpresaID),
+			"cliente_id": strconv.Itoa(reserva.ClienteID),
+			"empresa_id": strconv.Itoa(reserva.EmpresaID),
+			"reserva_id": strconv.Itoa(reserva.ID),
 		},
 	}
 
-	mpResp, err := s.MPClient.CreateCreditCardPayment(ctx, mpReq)
+	split, err := s.prepareSplit(reserva.EmpresaID, amount)
 	if err != nil {
 		return nil, err
 	}
+	if split.Enabled {
+		mpReq.ApplicationFee = split.Fee
+	}
+
+	mpResp, err := split.Client.CreateCreditCardPayment(ctx, mpReq)
+	if err != nil {
+		return nil, mercadoPagoPaymentError(err)
+	}
 
 	statusMessage := s.getStatusDetailMessage(mpResp.StatusDetail)
 
 	now := time.Now()
 	payment := &model.Pagamento{
-		ClienteID:            req.ClienteID,
-		EmpresaID:            req.EmpresaID,
+		ClienteID:            reserva.ClienteID,
+		EmpresaID:            reserva.EmpresaID,
 		MercadoPagoPaymentID: strconv.FormatInt(mpResp.ID, 10),
 		Status:               mpResp.Status,
 		StatusDetail:         mpResp.StatusDetail,
@@ -198,6 +301,8 @@ func (s *PagamentoService) CreateDebitCardPayment(ctx context.Context, req *cont
 		FirstSixDigits:       mpResp.Card.FirstSixDigits,
 		CardholderName:       mpResp.Card.Cardholder.Name,
 		Captured:             mpResp.Captured,
+		TaxaPlataforma:       split.Fee,
+		SplitMarketplace:     split.Enabled,
 		MomentoCriacao:       now,
 		MomentoAtualizacao:   now,
 	}
@@ -210,6 +315,12 @@ func (s *PagamentoService) CreateDebitCardPayment(ctx context.Context, req *cont
 		return nil, util.WrapError("erro ao salvar pagamento", err, http.StatusInternalServerError)
 	}
 
+	if err := s.ReservaRepository.LinkPayment(reserva.ID, payment.ID); err != nil {
+		log.Printf("erro ao vincular pagamento %d à reserva %d: %v", payment.ID, reserva.ID, err)
+	}
+
+	s.recordLedger(payment)
+
 	return &contract.CreateDebitCardPaymentResponse{
 		Pagamento: s.modelToResponse(payment),
 		Message:   statusMessage,
@@ -223,30 +334,55 @@ func (s *PagamentoService) CreatePIXPayment(ctx context.Context, req *contract.C
 		return nil, util.WrapError("erro de validação", err, http.StatusBadRequest)
 	}
 
+	line, err := s.reservationCharge(req.ClienteID, req.ReservaID)
+	if err != nil {
+		return nil, err
+	}
+	reserva, amount := line.Reserva, line.Amount
+
+	expiraEm := time.Now().Add(s.PIXExpiration)
+
 	mpReq := &mercadopago.PIXRequest{
-		TransactionAmount: req.TransactionAmount,
+		TransactionAmount: amount,
 		Description:       req.Description,
 		PaymentMethodID:   "pix",
+		ExternalReference: reservationReference(reserva, req.ExternalReference),
 		Payer: mercadopago.PaymentPayer{
 			Email: req.Payer.Email,
 		},
+		DateOfExpiration: expiraEm.Format(mercadopago.DateTimeLayout),
 		Metadata: map[string]string{
-			"cliente_id": strconv.Itoa(req.ClienteID),
-			"empresa_id": strconv.Itoa(req.EmpresaID),
+			"cliente_id": strconv.Itoa(reserva.ClienteID),
+			"empresa_id": strconv.Itoa(reserva.EmpresaID),
+			"reserva_id": strconv.Itoa(reserva.ID),
 		},
 	}
 
-	mpResp, err := s.MPClient.CreatePIXPayment(mpReq)
+	split, err := s.prepareSplit(reserva.EmpresaID, amount)
 	if err != nil {
 		return nil, err
 	}
+	if split.Enabled {
+		mpReq.ApplicationFee = split.Fee
+	}
+
+	mpResp, err := split.Client.CreatePIXPayment(ctx, mpReq)
+	if err != nil {
+		return nil, mercadoPagoPaymentError(err)
+	}
 
 	statusMessage := s.getStatusDetailMessage(mpResp.StatusDetail)
 
+	if mpResp.DateOfExpiration != "" {
+		if parsed, err := time.Parse(mercadopago.DateTimeLayout, mpResp.DateOfExpiration); err == nil {
+			expiraEm = parsed
+		}
+	}
+
 	now := time.Now()
 	payment := &model.Pagamento{
-		ClienteID:            req.ClienteID,
-		EmpresaID:            req.EmpresaID,
+		ClienteID:            reserva.ClienteID,
+		EmpresaID:            reserva.EmpresaID,
 		MercadoPagoPaymentID: strconv.FormatInt(mpResp.ID, 10),
 		Status:               mpResp.Status,
 		StatusDetail:         mpResp.StatusDetail,
@@ -255,8 +391,12 @@ func (s *PagamentoService) CreatePIXPayment(ctx context.Context, req *contract.C
 		MetodoPagamento:      "pix",
 		Descricao:            mpResp.Description,
 		NumeroParcelas:       1,
+		TaxaPlataforma:       split.Fee,
+		SplitMarketplace:     split.Enabled,
 		MomentoCriacao:       now,
 		MomentoAtualizacao:   now,
+		ExpiraEm:             &expiraEm,
+		QRCode:               mpResp.PointOfInteraction.TransactionData.QRCode,
 	}
 
 	if mpResp.Status == "approved" {
@@ -267,21 +407,132 @@ func (s *PagamentoService) CreatePIXPayment(ctx context.Context, req *contract.C
 		return nil, util.WrapError("erro ao salvar pagamento", err, http.StatusInternalServerError)
 	}
 
-	qrCode := ""
-	qrCodeBase64 := ""
-	ticketURL := ""
-
-	if mpResp.PointOfInteraction.Type == "PIX" {
-		// Aqui você pode extrair os dados do PIX da resposta
-		// Dependendo da estrutura da resposta do Mercado Pago
+	if err := s.ReservaRepository.LinkPayment(reserva.ID, payment.ID); err != nil {
+		log.Printf("erro ao vincular pagamento %d à reserva %d: %v", payment.ID, reserva.ID, err)
 	}
 
+	s.recordLedger(payment)
+
+	transactionData := mpResp.PointOfInteraction.TransactionData
+
 	return &contract.CreatePIXPaymentResponse{
 		Pagamento:    s.modelToResponse(payment),
 		Message:      statusMessage,
-		QRCode:       qrCode,
-		QRCodeBase64: qrCodeBase64,
-		TicketURL:    ticketURL,
+		QRCode:       transactionData.QRCode,
+		QRCodeBase64: transactionData.QRCodeBase64,
+		TicketURL:    transactionData.TicketURL,
+	}, nil
+}
+
+// CreateBoletoPayment - cria um pagamento com boleto; a aprovação chega pelo webhook após a compensação
+func (s *PagamentoService) CreateBoletoPayment(ctx context.Context, req *contract.CreateBoletoPaymentRequest) (*contract.CreateBoletoPaymentResponse, error) {
+
+	if err := req.Validate(); err != nil {
+		return nil, util.WrapError("erro de validação", err, http.StatusBadRequest)
+	}
+
+	line, err := s.reservationCharge(req.ClienteID, req.ReservaID)
+	if err != nil {
+		return nil, err
+	}
+	reserva, amount := line.Reserva, line.Amount
+
+	paymentMethodID := req.PaymentMethodID
+	if paymentMethodID == "" {
+		paymentMethodID = mercadopago.BoletoBradesco
+	}
+
+	expiraEm := time.Now().Add(s.BoletoExpiration)
+
+	mpReq := &mercadopago.BoletoRequest{
+		TransactionAmount: amount,
+		Description:       req.Description,
+		PaymentMethodID:   paymentMethodID,
+		ExternalReference: reservationReference(reserva, req.ExternalReference),
+		Payer: mercadopago.BoletoPayer{
+			Email:     req.Payer.Email,
+			FirstName: req.Payer.FirstName,
+			LastName:  req.Payer.LastName,
+			Identification: mercadopago.CreditCardIdentification{
+				Type:   "CPF",
+				Number: util.SomenteDigitos(req.Payer.CPF),
+			},
+			Address: mercadopago.BoletoAddress{
+				ZipCode:      util.SomenteDigitos(req.Payer.Address.ZipCode),
+				StreetName:   req.Payer.Address.StreetName,
+				StreetNumber: req.Payer.Address.StreetNumber,
+				Neighborhood: req.Payer.Address.Neighborhood,
+				City:         req.Payer.Address.City,
+				FederalUnit:  strings.ToUpper(req.Payer.Address.FederalUnit),
+			},
+		},
+		DateOfExpiration: expiraEm.Format(mercadopago.DateTimeLayout),
+		Metadata: map[string]string{
+			"cliente_id": strconv.Itoa(reserva.ClienteID),
+			"empresa_id": strconv.Itoa(reserva.EmpresaID),
+			"reserva_id": strconv.Itoa(reserva.ID),
+		},
+	}
+
+	split, err := s.prepareSplit(reserva.EmpresaID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if split.Enabled {
+		mpReq.ApplicationFee = split.Fee
+	}
+
+	mpResp, err := split.Client.CreateBoletoPayment(ctx, mpReq)
+	if err != nil {
+		return nil, mercadoPagoPaymentError(err)
+	}
+
+	if mpResp.DateOfExpiration != "" {
+		if parsed, err := time.Parse(mercadopago.DateTimeLayout, mpResp.DateOfExpiration); err == nil {
+			expiraEm = parsed
+		}
+	}
+
+	now := time.Now()
+	payment := &model.Pagamento{
+		ClienteID:            reserva.ClienteID,
+		EmpresaID:            reserva.EmpresaID,
+		MercadoPagoPaymentID: strconv.FormatInt(mpResp.ID, 10),
+		Status:               mpResp.Status,
+		StatusDetail:         mpResp.StatusDetail,
+		Valor:                mpResp.TransactionAmount,
+		Moeda:                "BRL",
+		MetodoPagamento:      string(model.MetodoBoleto),
+		Descricao:            mpResp.Description,
+		NumeroParcelas:       1,
+		PaymentMethodID:      mpResp.PaymentMethodID,
+		CodigoBarras:         mpResp.BarcodeContent(),
+		LinhaDigitavel:       mpResp.TransactionDetails.DigitableLine,
+		BoletoURL:            mpResp.TransactionDetails.ExternalResourceURL,
+		TaxaPlataforma:       split.Fee,
+		SplitMarketplace:     split.Enabled,
+		MomentoCriacao:       now,
+		MomentoAtualizacao:   now,
+		ExpiraEm:             &expiraEm,
+	}
+
+	if err := s.PagamentoRepository.Create(payment); err != nil {
+		return nil, util.WrapError("erro ao salvar pagamento", err, http.StatusInternalServerError)
+	}
+
+	if err := s.ReservaRepository.LinkPayment(reserva.ID, payment.ID); err != nil {
+		log.Printf("erro ao vincular pagamento %d à reserva %d: %v", payment.ID, reserva.ID, err)
+	}
+
+	s.recordLedger(payment)
+
+	return &contract.CreateBoletoPaymentResponse{
+		Pagamento:      s.modelToResponse(payment),
+		Message:        s.getStatusDetailMessage(mpResp.StatusDetail),
+		CodigoBarras:   payment.CodigoBarras,
+		LinhaDigitavel: payment.LinhaDigitavel,
+		BoletoURL:      payment.BoletoURL,
+		ExpiraEm:       payment.ExpiraEm,
 	}, nil
 }
 
@@ -336,17 +587,8 @@ func (s *PagamentoService) Get(ctx context.Context, paymentID int64) (*contract.
 		return nil, util.WrapError("erro ao buscar pagamento", err, http.StatusInternalServerError)
 	}
 
-	mpResp, err := s.MPClient.GetCreditCardPayment(ctx, paymentID)
-	if err == nil {
-		if mpResp.Status != payment.Status || mpResp.StatusDetail != payment.StatusDetail {
-			payment.Status = mpResp.Status
-			payment.StatusDetail = mpResp.StatusDetail
-			payment.Captured = mpResp.Captured
-			payment.TransactionAmountRefunded = mpResp.TransactionAmountRefunded
-			payment.MomentoAtualizacao = time.Now()
-			s.PagamentoRepository.Update(payment)
-		}
-	}
+	// falhas na consulta ao Mercado Pago não impedem o retorno do pagamento local
+	s.SyncWithMercadoPago(ctx, payment)
 
 	return &contract.GetPaymentResponse{
 		Pagamento: s.modelToResponse(payment),
@@ -395,12 +637,543 @@ func (s *PagamentoService) Update(ctx context.Context, req *contract.UpdatePayme
 	}, nil
 }
 
+// SyncWithMercadoPago - consulta o pagamento no Mercado Pago e persiste eventuais mudanças de status
+func (s *PagamentoService) SyncWithMercadoPago(ctx context.Context, payment *model.Pagamento) (*mercadopago.PaymentResponse, bool, error) {
+
+	remote, err := s.fetchRemotePayment(ctx, payment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	previousStatus := payment.Status
+	if !s.applyRemoteStatus(payment, remote) {
+		return remote, false, nil
+	}
+
+	if err := s.persistStatusChange(payment, previousStatus); err != nil {
+		return remote, false, err
+	}
+
+	return remote, true, nil
+}
+
+// persistStatusChange - salva o pagamento e aplica os efeitos da mudança de status sobre a reserva vinculada
+func (s *PagamentoService) persistStatusChange(payment *model.Pagamento, previousStatus string) error {
+
+	if err := s.PagamentoRepository.Update(payment); err != nil {
+		return util.WrapError("erro ao atualizar pagamento", err, http.StatusInternalServerError)
+	}
+
+	if payment.Status == previousStatus {
+		// o reembolso parcial não altera o status do pagamento aprovado, apenas o valor reembolsado
+		s.recordRefund(payment)
+		return nil
+	}
+
+	s.recordLedger(payment)
+
+	// disputas são acompanhadas à parte; falhas não impedem a atualização do pagamento
+	if err := s.DisputeService.SyncFromPayment(payment); err != nil {
+		log.Printf("erro ao sincronizar disputa do pagamento %d: %s", payment.ID, err.Error())
+	}
+
+	if payment.IsCancelled() || payment.IsRejected() {
+		return s.releaseReservation(payment)
+	}
+
+	return nil
+}
+
+// recordLedger - registra os lançamentos financeiros correspondentes ao status do pagamento
+func (s *PagamentoService) recordLedger(payment *model.Pagamento) {
+
+	var err error
+	switch model.StatusPagamento(payment.Status) {
+	case model.StatusApproved:
+		err = s.LedgerService.RecordSale(payment)
+	case model.StatusChargedBack:
+		err = s.LedgerService.RecordChargeback(payment)
+	}
+
+	// o lançamento não desfaz a operação no Mercado Pago; falhas são corrigidas na próxima sincronização
+	if err != nil {
+		log.Printf("erro ao registrar lançamento do pagamento %d: %s", payment.ID, err.Error())
+	}
+
+	s.recordRefund(payment)
+}
+
+// recordRefund - registra o estorno do valor reembolsado que ainda não tem lançamento
+func (s *PagamentoService) recordRefund(payment *model.Pagamento) {
+
+	// o chargeback já reverte a venda inteira
+	if payment.Status == string(model.StatusChargedBack) {
+		return
+	}
+	if payment.TransactionAmountRefunded <= 0 && payment.Status != string(model.StatusRefunded) {
+		return
+	}
+
+	if err := s.LedgerService.RecordRefund(payment); err != nil {
+		log.Printf("erro ao registrar estorno do pagamento %d: %s", payment.ID, err.Error())
+	}
+}
+
+// paymentSplit - representa o cliente e a comissão usados na criação de um pagamento
+type paymentSplit struct {
+	Client  *mercadopago.Client
+	Fee     float64
+	Enabled bool
+}
+
+// reservationLine - reserva a ser cobrada, com o passeio e o valor calculado no servidor
+type reservationLine struct {
+	Reserva *model.Reserva
+	Tour    *model.Tour
+	Amount  float64
+}
+
+// reservationCharge - valida que a reserva pertence ao cliente autenticado e calcula o valor a cobrar
+// a partir do preço do passeio e da quantidade de pessoas
+func (s *PagamentoService) reservationCharge(clienteID, reservaID int) (*reservationLine, error) {
+
+	if clienteID < 1 {
+		return nil, util.WrapError("cliente não identificado", nil, http.StatusUnauthorized)
+	}
+
+	reserva, err := s.ReservaRepository.GetByID(reservaID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, util.WrapError("reserva não encontrada", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao buscar reserva", err, http.StatusInternalServerError)
+	}
+
+	if reserva.ClienteID != clienteID {
+		return nil, util.WrapError("reserva não pertence ao cliente autenticado", nil, http.StatusForbidden)
+	}
+
+	if !reserva.CanBePaid() {
+		return nil, util.WrapError("reserva não está pendente de pagamento", nil, http.StatusConflict)
+	}
+
+	if reserva.PagamentoID > 0 {
+		pagamento, err := s.PagamentoRepository.GetByID(reserva.PagamentoID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, util.WrapError("erro ao buscar pagamento da reserva", err, http.StatusInternalServerError)
+		}
+		if pagamento != nil && (pagamento.IsApproved() || !pagamento.IsFinal()) {
+			return nil, util.WrapError("reserva já possui pagamento em andamento ou aprovado", nil, http.StatusConflict)
+		}
+	}
+
+	tour, err := s.TourRepository.GetByID(reserva.TourID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, util.WrapError("passeio da reserva não encontrado", err, http.StatusNotFound)
+		}
+		return nil, util.WrapError("erro ao buscar passeio da reserva", err, http.StatusInternalServerError)
+	}
+
+	if tour.CompanyID != reserva.EmpresaID {
+		return nil, util.WrapError("passeio não pertence à empresa da reserva", nil, http.StatusConflict)
+	}
+
+	amount := model.RoundCents(tour.Price * float64(reserva.QuantidadePessoas))
+	if amount <= 0 {
+		return nil, util.WrapError("valor da reserva inválido", nil, http.StatusUnprocessableEntity)
+	}
+
+	return &reservationLine{Reserva: reserva, Tour: tour, Amount: amount}, nil
+}
+
+// reservationReference - referência externa enviada ao Mercado Pago para identificar a reserva
+func reservationReference(reserva *model.Reserva, reference string) string {
+	if reference != "" {
+		return reference
+	}
+	return fmt.Sprintf("reserva:%d", reserva.ID)
+}
+
+// prepareSplit - calcula a comissão da plataforma e escolhe o cliente do vendedor quando a empresa está vinculada ao marketplace
+func (s *PagamentoService) prepareSplit(empresaID int, amount float64) (*paymentSplit, error) {
+
+	commission, err := s.LedgerService.CommissionFor(empresaID)
+	if err != nil {
+		return nil, err
+	}
+
+	split := &paymentSplit{
+		Client: s.MPClient,
+		Fee:    commission.Fee(amount),
+	}
+
+	if commission.HasSplit() {
+		token, err := s.LedgerService.SellerToken(commission)
+		if err != nil {
+			return nil, err
+		}
+		split.Client = s.MPClient.WithAccessToken(token)
+		split.Enabled = true
+	}
+
+	return split, nil
+}
+
+// clientForPayment - retorna o cliente do Mercado Pago com o qual o pagamento foi criado
+func (s *PagamentoService) clientForPayment(payment *model.Pagamento) *mercadopago.Client {
+
+	if !payment.SplitMarketplace {
+		return s.MPClient
+	}
+
+	commission, err := s.LedgerService.CommissionFor(payment.EmpresaID)
+	if err != nil || !commission.HasSplit() {
+		return s.MPClient
+	}
+
+	token, err := s.LedgerService.SellerToken(commission)
+	if err != nil {
+		log.Printf("erro ao decifrar access token da empresa %d: %s", payment.EmpresaID, err.Error())
+		return s.MPClient
+	}
+
+	return s.MPClient.WithAccessToken(token)
+}
+
+// releaseReservation - cancela as reservas vinculadas ao pagamento, liberando as vagas dos passeios
+func (s *PagamentoService) releaseReservation(payment *model.Pagamento) error {
+
+	reservas, err := s.ReservaRepository.ListByPagamentoID(payment.ID)
+	if err != nil {
+		return util.WrapError("erro ao buscar reservas do pagamento", err, http.StatusInternalServerError)
+	}
+
+	for _, reserva := range reservas {
+		if !reserva.CanBeCancelled() {
+			continue
+		}
+
+		if err := s.ReservaRepository.Cancel(reserva.ID); err != nil {
+			return util.WrapError("erro ao cancelar reserva do pagamento", err, http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+// applyRemoteStatus - aplica no pagamento local o estado retornado pelo Mercado Pago, indicando se houve alteração
+func (s *PagamentoService) applyRemoteStatus(payment *model.Pagamento, remote *mercadopago.PaymentResponse) bool {
+
+	e2eID := remote.PointOfInteraction.TransactionData.E2EID
+
+	if remote.Status == payment.Status &&
+		remote.StatusDetail == payment.StatusDetail &&
+		remote.Captured == payment.Captured &&
+		remote.TransactionAmountRefunded == payment.TransactionAmountRefunded &&
+		(e2eID == "" || e2eID == payment.PixEndToEndID) {
+		return false
+	}
+
+	now := time.Now()
+
+	if remote.Status != payment.Status {
+		switch model.StatusPagamento(remote.Status) {
+		case model.StatusApproved:
+			if payment.MomentoAprovacao == nil {
+				payment.MomentoAprovacao = &now
+			}
+		case model.StatusAuthorized:
+			if payment.MomentoAutorizacao == nil {
+				payment.MomentoAutorizacao = &now
+			}
+		case model.StatusCancelled:
+			if payment.MomentoCancelamento == nil {
+				payment.MomentoCancelamento = &now
+			}
+		}
+	}
+
+	if remote.Captured && !payment.Captured && payment.MomentoCaptura == nil {
+		payment.MomentoCaptura = &now
+	}
+
+	payment.Status = remote.Status
+	payment.StatusDetail = remote.StatusDetail
+	payment.Captured = remote.Captured
+	payment.TransactionAmountRefunded = remote.TransactionAmountRefunded
+	payment.MomentoAtualizacao = now
+
+	// o identificador end-to-end só existe depois que o PIX é pago
+	if e2eID != "" {
+		payment.PixEndToEndID = e2eID
+	}
+
+	return true
+}
+
+// ProcessWebhook - processa uma notificação de pagamento enviada pelo Mercado Pago
+func (s *PagamentoService) ProcessWebhook(ctx context.Context, req *contract.MercadoPagoWebhookRequest) (*contract.WebhookResponse, error) {
+
+	if req.Data.ID == "" {
+		return &contract.WebhookResponse{Message: "Notificação ignorada"}, nil
+	}
+
+	var payment *model.Pagamento
+	var err error
+	switch req.Type {
+	case "payment":
+		payment, err = s.PagamentoRepository.GetByMercadoPagoPaymentID(req.Data.ID)
+	case "order":
+		payment, err = s.PagamentoRepository.GetByMercadoPagoOrderID(req.Data.ID)
+	case "chargebacks", "topic_chargebacks_wh":
+		return s.DisputeService.ProcessChargeback(ctx, req.Data.ID)
+	default:
+		return &contract.WebhookResponse{Message: "Notificação ignorada"}, nil
+	}
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &contract.WebhookResponse{Message: "Pagamento não encontrado"}, nil
+		}
+		return nil, util.WrapError("erro ao buscar pagamento", err, http.StatusInternalServerError)
+	}
+
+	if _, _, err := s.SyncWithMercadoPago(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return &contract.WebhookResponse{Message: "Notificação processada com sucesso"}, nil
+}
+
+// ReconcilePayments - confronta os pagamentos em status não finais com o Mercado Pago, percorrendo todos em páginas de
+// limit pagamentos para que os mais novos não fiquem de fora quando os antigos continuam pendentes
+func (s *PagamentoService) ReconcilePayments(ctx context.Context, minAge time.Duration, limit int) (*contract.PaymentReconciliationReport, error) {
+
+	report := &contract.PaymentReconciliationReport{
+		StartedAt:     time.Now(),
+		Discrepancies: []contract.PaymentDiscrepancy{},
+	}
+
+	afterID := 0
+	for ctx.Err() == nil {
+		payments, err := s.PagamentoRepository.ListForReconciliation(model.StatusNaoFinais, report.StartedAt.Add(-minAge), afterID, limit)
+		if err != nil {
+			return nil, util.WrapError("erro ao buscar pagamentos para conciliação", err, http.StatusInternalServerError)
+		}
+
+		for i := range payments {
+			if ctx.Err() != nil {
+				break
+			}
+			s.reconcilePayment(ctx, &payments[i], report)
+		}
+
+		if limit <= 0 || len(payments) < limit {
+			break
+		}
+		afterID = payments[len(payments)-1].ID
+	}
+
+	report.FinishedAt = time.Now()
+
+	return report, nil
+}
+
+// reconcilePayment - sincroniza um pagamento com o Mercado Pago e registra no relatório a falha ou a atualização
+func (s *PagamentoService) reconcilePayment(ctx context.Context, payment *model.Pagamento, report *contract.PaymentReconciliationReport) {
+
+	discrepancy := contract.PaymentDiscrepancy{
+		PagamentoID:          payment.ID,
+		MercadoPagoPaymentID: payment.MercadoPagoPaymentID,
+		LocalStatus:          payment.Status,
+		LocalStatusDetail:    payment.StatusDetail,
+	}
+	report.Scanned++
+
+	remote, updated, err := s.SyncWithMercadoPago(ctx, payment)
+	if remote != nil {
+		discrepancy.RemoteStatus = remote.Status
+		discrepancy.RemoteStatusDetail = remote.StatusDetail
+	}
+
+	switch {
+	case err != nil:
+		discrepancy.Error = err.Error()
+		report.Failed++
+		report.Discrepancies = append(report.Discrepancies, discrepancy)
+	case updated:
+		report.Updated++
+		report.Discrepancies = append(report.Discrepancies, discrepancy)
+	}
+}
+
+// ExpirePIXPayments - cancela os pagamentos PIX vencidos e libera as reservas vinculadas
+func (s *PagamentoService) ExpirePIXPayments(ctx context.Context, limit int) (int, error) {
+
+	payments, err := s.PagamentoRepository.ListExpiredPIX(time.Now(), limit)
+	if err != nil {
+		return 0, util.WrapError("erro ao buscar pagamentos PIX expirados", err, http.StatusInternalServerError)
+	}
+
+	expired := 0
+	for i := range payments {
+		if ctx.Err() != nil {
+			break
+		}
+
+		payment := &payments[i]
+
+		// o pagamento pode ter sido confirmado após o último webhook recebido; sem a confirmação do Mercado Pago
+		// o PIX fica para a próxima execução, já que o cancelamento local é definitivo
+		if _, _, err := s.SyncWithMercadoPago(ctx, payment); err != nil {
+			log.Printf("erro ao sincronizar PIX %s antes de expirar: %s", payment.MercadoPagoPaymentID, err.Error())
+			continue
+		}
+		if !payment.IsPending() && payment.Status != string(model.StatusInProcess) {
+			continue
+		}
+
+		if err := s.cancelRemotePayment(ctx, payment); err != nil {
+			log.Printf("erro ao cancelar PIX %s no Mercado Pago: %s", payment.MercadoPagoPaymentID, err.Error())
+			continue
+		}
+
+		previousStatus := payment.Status
+		now := time.Now()
+		payment.Status = string(model.StatusCancelled)
+		payment.StatusDetail = "expired"
+		payment.MomentoCancelamento = &now
+		payment.MomentoAtualizacao = now
+
+		if err := s.persistStatusChange(payment, previousStatus); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// ListInstallments - simula os planos de parcelamento disponíveis para o valor e o BIN do cartão
+func (s *PagamentoService) ListInstallments(ctx context.Context, req *contract.ListInstallmentsRequest) (*contract.ListInstallmentsResponse, error) {
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("installments:%s:%.2f", req.Bin, req.Amount)
+
+	response := &contract.ListInstallmentsResponse{}
+	if s.getCache(ctx, cacheKey, response) {
+		return response, nil
+	}
+
+	options, err := s.MPClient.GetInstallments(ctx, req.Amount, req.Bin)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options) == 0 {
+		return nil, util.WrapError("nenhum plano de parcelamento disponível para o cartão informado", nil, http.StatusNotFound)
+	}
+
+	methodNames := s.paymentMethodNames(ctx)
+
+	response = &contract.ListInstallmentsResponse{
+		Amount:  req.Amount,
+		Bin:     req.Bin,
+		Options: make([]contract.InstallmentOptionResponse, 0, len(options)),
+	}
+
+	for _, option := range options {
+		optionResponse := contract.InstallmentOptionResponse{
+			PaymentMethodID:   option.PaymentMethodID,
+			PaymentMethodName: methodNames[option.PaymentMethodID],
+			PaymentTypeID:     option.PaymentTypeID,
+			Thumbnail:         option.Thumbnail,
+			IssuerID:          option.Issuer.ID,
+			IssuerName:        option.Issuer.Name,
+			Plans:             make([]contract.InstallmentPlanResponse, 0, len(option.PayerCosts)),
+		}
+
+		for _, cost := range option.PayerCosts {
+			optionResponse.Plans = append(optionResponse.Plans, contract.InstallmentPlanResponse{
+				Installments:       cost.Installments,
+				InstallmentAmount:  cost.InstallmentAmount,
+				TotalAmount:        cost.TotalAmount,
+				InterestRate:       cost.InstallmentRate,
+				InterestFree:       cost.InstallmentRate == 0,
+				RecommendedMessage: cost.RecommendedMessage,
+			})
+		}
+
+		response.Options = append(response.Options, optionResponse)
+	}
+
+	s.setCache(ctx, cacheKey, response, installmentsCacheTTL)
+
+	return response, nil
+}
+
+// paymentMethodNames - retorna o nome de exibição de cada meio de pagamento do Mercado Pago
+func (s *PagamentoService) paymentMethodNames(ctx context.Context) map[string]string {
+
+	names := map[string]string{}
+	if s.getCache(ctx, paymentMethodsCacheKey, &names) {
+		return names
+	}
+
+	methods, err := s.MPClient.GetPaymentMethods(ctx)
+	if err != nil {
+		return names
+	}
+
+	for _, method := range *methods {
+		names[method.ID] = method.Name
+	}
+
+	s.setCache(ctx, paymentMethodsCacheKey, names, paymentMethodsCacheTTL)
+
+	return names
+}
+
+// getCache - lê um valor em cache no Redis, indicando se foi encontrado
+func (s *PagamentoService) getCache(ctx context.Context, key string, target interface{}) bool {
+	if s.Redis == nil {
+		return false
+	}
+
+	data, err := s.Redis.Get(ctx, key).Bytes()
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(data, target) == nil
+}
+
+// setCache - grava um valor em cache no Redis; falhas apenas deixam de aproveitar o cache
+func (s *PagamentoService) setCache(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	if s.Redis == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	if err := s.Redis.Set(ctx, key, data, ttl).Err(); err != nil {
+		log.Printf("erro ao gravar cache %s: %s", key, err.Error())
+	}
+}
+
 // getStatusDetailMessage - retorna mensagens amigáveis para status_detail
 func (s *PagamentoService) getStatusDetailMessage(statusDetail string) string {
 	messages := map[string]string{
 		"accredited":                           "Pagamento aprovado",
 		"pending_contingency":                  "Aguardando confirmação",
 		"pending_review_manual":                "Em revisão manual",
+		"pending_waiting_payment":              "Aguardando pagamento do boleto",
 		"cc_rejected_bad_filled_card_number":   "Número do cartão inválido",
 		"cc_rejected_bad_filled_date":          "Data de validade inválida",
 		"cc_rejected_bad_filled_other":         "Dados do cartão inválidos",
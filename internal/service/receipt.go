@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/model"
+	"github.com/jampa_trip/pkg/pdf"
+	"github.com/jampa_trip/pkg/util"
+	"gorm.io/gorm"
+)
+
+// Layout do recibo em PDF
+const (
+	receiptMarginX     = 50.0
+	receiptValueX      = 200.0
+	receiptLineHeight  = 16.0
+	receiptSectionSkip = 26.0
+	receiptTopY        = 60.0
+	receiptBottomY     = pdf.PageHeight - 80
+)
+
+// receiptLine - representa um passeio pago exibido no recibo
+type receiptLine struct {
+	Tour      string
+	Date      time.Time
+	People    int
+	Amount    float64
+	Cancelled bool
+}
+
+// Receipt - gera o recibo em PDF de um pagamento aprovado; acessível ao cliente pagador e à empresa recebedora
+// O ID é o do Mercado Pago: numérico nos pagamentos avulsos e alfanumérico nos pagamentos de order do carrinho.
+func (s *PagamentoService) Receipt(ctx context.Context, paymentID string, userID int, userType string) ([]byte, string, error) {
+
+	if paymentID == "" {
+		return nil, "", util.WrapError("ID do pagamento inválido", nil, http.StatusBadRequest)
+	}
+
+	payment, err := s.PagamentoRepository.GetByMercadoPagoPaymentID(paymentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, "", util.WrapError("pagamento não encontrado", err, http.StatusNotFound)
+		}
+		return nil, "", util.WrapError("erro ao buscar pagamento", err, http.StatusInternalServerError)
+	}
+
+	switch {
+	case userType == "client" && payment.ClienteID == userID:
+	case userType == "company" && payment.EmpresaID == userID:
+	default:
+		return nil, "", util.WrapError("usuário sem permissão para acessar este recibo", nil, http.StatusForbidden)
+	}
+
+	if payment.MomentoAprovacao == nil {
+		return nil, "", util.WrapError("recibo disponível apenas para pagamentos aprovados", nil, http.StatusConflict)
+	}
+
+	company, err := s.CompanyRepository.GetByID(payment.EmpresaID)
+	if err != nil {
+		return nil, "", util.WrapError("erro ao buscar empresa do pagamento", err, http.StatusInternalServerError)
+	}
+
+	client, err := s.ClientRepository.GetByID(payment.ClienteID)
+	if err != nil {
+		return nil, "", util.WrapError("erro ao buscar cliente do pagamento", err, http.StatusInternalServerError)
+	}
+
+	lines, err := s.receiptLines(payment)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := renderReceipt(payment, company, client, lines, time.Now())
+	if err != nil {
+		return nil, "", util.WrapError("erro ao gerar recibo", err, http.StatusInternalServerError)
+	}
+
+	return data, fmt.Sprintf("recibo-%s.pdf", receiptNumber(payment)), nil
+}
+
+// ListInvoices - lista os pagamentos aprovados da empresa no período, com o link do recibo de cada um
+func (s *PagamentoService) ListInvoices(req *contract.ListInvoicesRequest) (*contract.ListInvoicesResponse, error) {
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	pagination := util.NormalizePagination(req.Page, req.Limit)
+
+	start, err := time.ParseInLocation("2006-01", req.Period, time.Local)
+	if err != nil {
+		return nil, util.WrapError("período inválido, utilize o formato AAAA-MM", err, http.StatusBadRequest)
+	}
+
+	payments, total, err := s.PagamentoRepository.ListInvoicesByEmpresaID(req.CompanyID, start, start.AddDate(0, 1, 0), pagination.Page, pagination.Limit)
+	if err != nil {
+		return nil, util.WrapError("erro ao listar faturas", err, http.StatusInternalServerError)
+	}
+
+	invoices := make([]contract.InvoiceResponse, len(payments))
+	for i := range payments {
+		invoices[i] = invoiceToResponse(&payments[i])
+	}
+
+	return &contract.ListInvoicesResponse{
+		Period:   req.Period,
+		Invoices: invoices,
+		Pagination: contract.PaginationResponse{
+			CurrentPage:  pagination.Page,
+			TotalPages:   util.CalculateTotalPages(total, pagination.Limit),
+			TotalItems:   int(total),
+			ItemsPerPage: pagination.Limit,
+		},
+	}, nil
+}
+
+// receiptLines - monta os passeios pagos, que podem ser vários quando o pagamento veio do carrinho
+func (s *PagamentoService) receiptLines(payment *model.Pagamento) ([]receiptLine, error) {
+
+	reservas, err := s.ReservaRepository.ListByPagamentoID(payment.ID)
+	if err != nil {
+		return nil, util.WrapError("erro ao buscar reservas do pagamento", err, http.StatusInternalServerError)
+	}
+
+	lines := make([]receiptLine, 0, len(reservas))
+	for _, reserva := range reservas {
+		line := receiptLine{
+			Date:      reserva.DataPasseio,
+			People:    reserva.QuantidadePessoas,
+			Amount:    reserva.ValorTotal,
+			Cancelled: reserva.IsCancelled(),
+		}
+
+		tour, err := s.TourRepository.GetByID(reserva.TourID)
+		if err != nil {
+			// o recibo continua válido sem o nome do passeio excluído
+			log.Printf("erro ao buscar passeio %d do recibo do pagamento %d: %v", reserva.TourID, payment.ID, err)
+			line.Tour = fmt.Sprintf("Passeio #%d", reserva.TourID)
+		} else {
+			line.Tour = tour.Name
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// renderReceipt - desenha o recibo em páginas A4; o pagamento do carrinho com muitos passeios continua nas páginas
+// seguintes, com o número do recibo no topo de cada uma
+func renderReceipt(payment *model.Pagamento, company *model.Company, client *model.Client, lines []receiptLine, generatedAt time.Time) ([]byte, error) {
+
+	doc := pdf.New(fmt.Sprintf("Recibo %s", receiptNumber(payment)))
+	doc.Author = company.Name
+	doc.CreatedAt = generatedAt
+
+	var page *pdf.Page
+	addPage := func() {
+		page = doc.AddPage()
+		page.Text(receiptMarginX, pdf.PageHeight-50, pdf.Helvetica, 8,
+			"Recibo emitido pela Jampa Trip em nome da empresa acima. Este documento não substitui a nota fiscal.")
+	}
+	addPage()
+
+	page.FillRect(0, 0, pdf.PageWidth, 90, 0.92)
+	page.Text(receiptMarginX, 50, pdf.HelveticaBold, 20, "Jampa Trip")
+	page.Text(receiptMarginX, 72, pdf.Helvetica, 12, "Recibo de pagamento")
+	page.Text(400, 50, pdf.HelveticaBold, 12, fmt.Sprintf("Nº %s", receiptNumber(payment)))
+	page.Text(400, 72, pdf.Helvetica, 10, fmt.Sprintf("Emitido em %s", generatedAt.Local().Format("02/01/2006 15:04")))
+
+	y := 125.0
+	// reserve - abre uma nova página quando a altura pedida não cabe na atual
+	reserve := func(height float64) {
+		if y+height <= receiptBottomY {
+			return
+		}
+		addPage()
+		page.Text(receiptMarginX, receiptTopY-20, pdf.Helvetica, 9, fmt.Sprintf("Recibo Nº %s (continuação)", receiptNumber(payment)))
+		y = receiptTopY
+	}
+	section := func(title string) {
+		reserve(2*receiptLineHeight + 6)
+		page.Text(receiptMarginX, y, pdf.HelveticaBold, 12, title)
+		page.Line(receiptMarginX, y+5, pdf.PageWidth-receiptMarginX, y+5, 0.5)
+		y += receiptLineHeight + 6
+	}
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		reserve(receiptLineHeight)
+		page.Text(receiptMarginX, y, pdf.Helvetica, 10, label)
+		page.Text(receiptValueX, y, pdf.Helvetica, 10, value)
+		y += receiptLineHeight
+	}
+
+	section("Empresa")
+	field("Razão social", company.Name)
+	field("CNPJ", formatCNPJ(company.CNPJ))
+	field("Endereço", company.Address)
+	field("E-mail", company.Email)
+	y += receiptSectionSkip - receiptLineHeight
+
+	section("Cliente")
+	field("Nome", client.Name)
+	field("CPF", maskCPF(client.CPF))
+	y += receiptSectionSkip - receiptLineHeight
+
+	section("Passeios")
+	if len(lines) == 0 {
+		field("Descrição", payment.Descricao)
+	}
+	for _, line := range lines {
+		people := "1 pessoa"
+		if line.People != 1 {
+			people = fmt.Sprintf("%d pessoas", line.People)
+		}
+		detail := fmt.Sprintf("%s  ·  %s  ·  %s", line.Date.Format("02/01/2006"), people, formatBRL(line.Amount))
+		if line.Cancelled {
+			detail += "  ·  cancelada"
+		}
+		reserve(receiptLineHeight + 13)
+		page.Text(receiptMarginX, y, pdf.HelveticaBold, 10, line.Tour)
+		page.Text(receiptMarginX, y+13, pdf.Helvetica, 10, detail)
+		y += receiptLineHeight + 13
+	}
+	y += receiptSectionSkip - receiptLineHeight
+
+	section("Pagamento")
+	field("Situação", payment.GetStatusDisplay())
+	field("Forma de pagamento", payment.GetMetodoPagamentoDisplay())
+	if payment.NumeroParcelas > 1 {
+		field("Parcelas", fmt.Sprintf("%dx", payment.NumeroParcelas))
+	}
+	if payment.LastFourDigits != "" {
+		field("Cartão", fmt.Sprintf("**** **** **** %s", payment.LastFourDigits))
+	}
+	if payment.CardholderName != "" {
+		field("Titular", payment.CardholderName)
+	}
+	field("ID end-to-end PIX", payment.PixEndToEndID)
+	field("Transação Mercado Pago", payment.MercadoPagoPaymentID)
+	field("Aprovado em", payment.MomentoAprovacao.Local().Format("02/01/2006 15:04"))
+	if payment.TransactionAmountRefunded > 0 {
+		field("Valor estornado", formatBRL(payment.TransactionAmountRefunded))
+	}
+
+	y += 8
+	reserve(receiptLineHeight)
+	page.Line(receiptMarginX, y-12, pdf.PageWidth-receiptMarginX, y-12, 0.5)
+	page.Text(receiptMarginX, y+4, pdf.HelveticaBold, 13, "Valor total")
+	page.Text(receiptValueX, y+4, pdf.HelveticaBold, 13, formatBRL(payment.Valor))
+
+	return doc.Bytes()
+}
+
+// invoiceToResponse - converte o pagamento aprovado para a resposta da listagem de faturas
+func invoiceToResponse(p *model.Pagamento) contract.InvoiceResponse {
+
+	receiptURL := ""
+	if p.MercadoPagoPaymentID != "" {
+		receiptURL = fmt.Sprintf("/payments/%s/receipt.pdf", url.PathEscape(p.MercadoPagoPaymentID))
+	}
+
+	return contract.InvoiceResponse{
+		Number:               receiptNumber(p),
+		PagamentoID:          p.ID,
+		MercadoPagoPaymentID: p.MercadoPagoPaymentID,
+		ClienteNome:          p.Cliente.Name,
+		Status:               p.Status,
+		MetodoPagamento:      p.MetodoPagamento,
+		NumeroParcelas:       p.NumeroParcelas,
+		Valor:                p.Valor,
+		ValorEstornado:       p.TransactionAmountRefunded,
+		TaxaPlataforma:       p.TaxaPlataforma,
+		ValorLiquido:         model.RoundCents(p.Valor - p.TaxaPlataforma - p.TransactionAmountRefunded),
+		Moeda:                p.Moeda,
+		AprovadoEm:           p.MomentoAprovacao,
+		ReceiptURL:           receiptURL,
+	}
+}
+
+// receiptNumber - número sequencial do recibo, derivado do ID do pagamento
+func receiptNumber(p *model.Pagamento) string {
+	return fmt.Sprintf("REC-%06d", p.ID)
+}
+
+// formatBRL - formata um valor em reais no padrão brasileiro (R$ 1.234,56)
+func formatBRL(value float64) string {
+
+	cents := int64(model.RoundCents(value)*100 + 0.5)
+	if value < 0 {
+		cents = int64(model.RoundCents(value)*100 - 0.5)
+	}
+
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	integer := strconv.FormatInt(cents/100, 10)
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
+
+// formatCNPJ - formata o CNPJ como 00.000.000/0000-00
+func formatCNPJ(cnpj string) string {
+	digits := util.SomenteDigitos(cnpj)
+	if len(digits) != 14 {
+		return cnpj
+	}
+	return fmt.Sprintf("%s.%s.%s/%s-%s", digits[0:2], digits[2:5], digits[5:8], digits[8:12], digits[12:14])
+}
+
+// maskCPF - exibe apenas os dígitos centrais do CPF (***.456.789-**)
+func maskCPF(cpf string) string {
+	digits := util.SomenteDigitos(cpf)
+	if len(digits) != 11 {
+		return ""
+	}
+	return fmt.Sprintf("***.%s.%s-**", digits[3:6], digits[6:9])
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/jampa_trip/pkg/config"
+)
+
+// sellerTokenPrefix - identifica os tokens cifrados; valores sem o prefixo foram gravados antes da cifragem
+const sellerTokenPrefix = "enc:v1:"
+
+// SellerTokenCipher - cifra com AES-256-GCM os access tokens das empresas vinculadas ao marketplace
+type SellerTokenCipher struct {
+	Key []byte
+}
+
+// newSellerTokenCipher - cria a cifra com MERCADO_PAGO_SELLER_TOKEN_KEY; sem ela, a chave é derivada de JWT_SECRET
+func newSellerTokenCipher(cfg *config.Config) *SellerTokenCipher {
+
+	if cfg.MercadoPagoSellerTokenKey != "" {
+		key := sha256.Sum256([]byte(cfg.MercadoPagoSellerTokenKey))
+		return &SellerTokenCipher{Key: key[:]}
+	}
+
+	mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+	mac.Write([]byte("seller-token"))
+	return &SellerTokenCipher{Key: mac.Sum(nil)}
+}
+
+// Encrypt - cifra o token com um nonce aleatório, retornando o valor gravado no banco
+func (c *SellerTokenCipher) Encrypt(token string) (string, error) {
+
+	aead, err := c.aead()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(token), nil)
+	return sellerTokenPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt - decifra o valor gravado no banco; tokens gravados antes da cifragem são retornados como estão
+func (c *SellerTokenCipher) Decrypt(value string) (string, error) {
+
+	encoded, found := strings.CutPrefix(value, sellerTokenPrefix)
+	if !found {
+		return value, nil
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := c.aead()
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("token cifrado incompleto")
+	}
+
+	token, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(token), nil
+}
+
+// aead - cria o AES-GCM com a chave da cifra
+func (c *SellerTokenCipher) aead() (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(c.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
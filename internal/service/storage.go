@@ -0,0 +1,77 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jampa_trip/pkg/config"
+	"github.com/jampa_trip/pkg/storage"
+)
+
+// Valores padrão do armazenamento de arquivos
+const (
+	DefaultStorageLocalDir    = "uploads"
+	DefaultPublicMediaBaseURL = "/jampa-trip/api/v1/media"
+)
+
+// legacyUploadsPrefix - caminho das URLs absolutas gravadas antes de as imagens guardarem apenas a chave
+const legacyUploadsPrefix = "/uploads/"
+
+// newBlobStore - cria o armazenamento de arquivos configurado em STORAGE_BACKEND (disco local por padrão)
+func newBlobStore(cfg *config.Config) storage.BlobStore {
+
+	if cfg.StorageBackend == "s3" {
+		usePathStyle, _ := strconv.ParseBool(cfg.S3UsePathStyle)
+		return storage.NewS3Store(storage.S3Options{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			UsePathStyle:    usePathStyle,
+		})
+	}
+
+	dir := cfg.StorageLocalDir
+	if dir == "" {
+		dir = DefaultStorageLocalDir
+	}
+
+	return storage.NewLocalStore(dir)
+}
+
+// publicMediaBaseURL - URL base pública das imagens, sem barra final
+func publicMediaBaseURL(cfg *config.Config) string {
+	if cfg.PublicMediaBaseURL == "" {
+		return DefaultPublicMediaBaseURL
+	}
+	return strings.TrimSuffix(cfg.PublicMediaBaseURL, "/")
+}
+
+// mediaURL - monta a URL pública a partir da chave gravada no banco; URLs absolutas antigas são mantidas
+func mediaURL(baseURL, ref string) string {
+	if ref == "" || strings.Contains(ref, "://") {
+		return ref
+	}
+	return baseURL + "/" + storage.EscapeKey(ref)
+}
+
+// storageKey - obtém a chave do objeto a partir do valor gravado no banco, aceitando as URLs absolutas antigas
+func storageKey(ref string) (string, bool) {
+
+	key := ref
+	if strings.Contains(ref, "://") {
+		parsed, err := url.Parse(ref)
+		if err != nil || !strings.HasPrefix(parsed.Path, legacyUploadsPrefix) {
+			return "", false
+		}
+		key = strings.TrimPrefix(parsed.Path, legacyUploadsPrefix)
+	}
+
+	if storage.ValidateKey(key) != nil {
+		return "", false
+	}
+
+	return key, true
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
@@ -15,20 +16,25 @@ import (
 // TourService - objeto de contexto
 type TourService struct {
 	TourRepository *repository.TourRepository
+	ImageService   *ImageService
 }
 
 // TourServiceNew - construtor do objeto
 func TourServiceNew(DB *gorm.DB) *TourService {
 	return &TourService{
 		TourRepository: repository.TourRepositoryNew(DB),
+		ImageService:   ImageServiceNew(DB),
 	}
 }
 
 // Create - cria um novo passeio
 func (s *TourService) Create(request *contract.CreateTourRequest, companyID int) (*contract.CreateTourResponse, error) {
 
+	if err := s.checkTourImages(request.ImageIDs, companyID, 0); err != nil {
+		return nil, err
+	}
+
 	dates := pq.StringArray(request.Dates)
-	images := pq.StringArray(request.Images)
 
 	tour := &model.Tour{
 		CompanyID:     companyID,
@@ -38,21 +44,29 @@ func (s *TourService) Create(request *contract.CreateTourRequest, companyID int)
 		ArrivalTime:   request.ArrivalTime,
 		MaxPeople:     request.MaxPeople,
 		Description:   request.Description,
-		Images:        images,
 		Price:         request.Price,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	if err := s.TourRepository.Create(tour); err != nil {
+	created, err := s.TourRepository.CreateWithImages(tour, request.ImageIDs)
+	if err != nil {
 		return nil, util.WrapError("Erro ao criar passeio", err, http.StatusInternalServerError)
 	}
+	if !created {
+		return nil, tourImagesUnavailableError()
+	}
 
 	tourWithCompany, companyName, err := s.TourRepository.GetTourWithCompanyName(tour.ID)
 	if err != nil {
 		return nil, util.WrapError("Erro ao buscar passeio criado", err, http.StatusInternalServerError)
 	}
 
+	images, err := s.tourImages([]int{tour.ID}, true)
+	if err != nil {
+		return nil, err
+	}
+
 	response := &contract.CreateTourResponse{
 		Success: true,
 		Tour: contract.TourResponse{
@@ -63,7 +77,7 @@ func (s *TourService) Create(request *contract.CreateTourRequest, companyID int)
 			ArrivalTime:   tourWithCompany.ArrivalTime,
 			MaxPeople:     tourWithCompany.MaxPeople,
 			Description:   tourWithCompany.Description,
-			Images:        tourWithCompany.Images,
+			Images:        images[tourWithCompany.ID],
 			Price:         tourWithCompany.Price,
 			CompanyID:     tourWithCompany.CompanyID,
 			CompanyName:   companyName,
@@ -86,8 +100,11 @@ func (s *TourService) Update(request *contract.UpdateTourRequest, companyID int)
 		return nil, util.WrapError("Passeio não encontrado ou você não tem permissão para editá-lo", nil, http.StatusForbidden)
 	}
 
+	if err := s.checkTourImages(request.ImageIDs, companyID, request.ID); err != nil {
+		return nil, err
+	}
+
 	dates := pq.StringArray(request.Dates)
-	images := pq.StringArray(request.Images)
 
 	tour := &model.Tour{
 		ID:            request.ID,
@@ -98,20 +115,28 @@ func (s *TourService) Update(request *contract.UpdateTourRequest, companyID int)
 		ArrivalTime:   request.ArrivalTime,
 		MaxPeople:     request.MaxPeople,
 		Description:   request.Description,
-		Images:        images,
 		Price:         request.Price,
 		UpdatedAt:     time.Now(),
 	}
 
-	if err := s.TourRepository.Update(tour); err != nil {
+	updated, err := s.TourRepository.UpdateWithImages(tour, request.ImageIDs)
+	if err != nil {
 		return nil, util.WrapError("Erro ao atualizar passeio", err, http.StatusInternalServerError)
 	}
+	if !updated {
+		return nil, tourImagesUnavailableError()
+	}
 
 	tourWithCompany, companyName, err := s.TourRepository.GetTourWithCompanyName(tour.ID)
 	if err != nil {
 		return nil, util.WrapError("Erro ao buscar passeio atualizado", err, http.StatusInternalServerError)
 	}
 
+	images, err := s.tourImages([]int{tour.ID}, true)
+	if err != nil {
+		return nil, err
+	}
+
 	response := &contract.UpdateTourResponse{
 		Success: true,
 		Tour: contract.TourResponse{
@@ -122,7 +147,7 @@ func (s *TourService) Update(request *contract.UpdateTourRequest, companyID int)
 			ArrivalTime:   tourWithCompany.ArrivalTime,
 			MaxPeople:     tourWithCompany.MaxPeople,
 			Description:   tourWithCompany.Description,
-			Images:        tourWithCompany.Images,
+			Images:        images[tourWithCompany.ID],
 			Price:         tourWithCompany.Price,
 			CompanyID:     tourWithCompany.CompanyID,
 			CompanyName:   companyName,
@@ -146,6 +171,11 @@ func (s *TourService) List(request *contract.ListToursRequest) (*contract.ListTo
 		return nil, util.WrapError("Erro ao buscar passeios", err, http.StatusInternalServerError)
 	}
 
+	images, err := s.tourImages(tourIDs(tours), false)
+	if err != nil {
+		return nil, err
+	}
+
 	var toursResponse []contract.TourResponse
 	for _, tour := range tours {
 		_, companyName, err := s.TourRepository.GetTourWithCompanyName(tour.ID)
@@ -161,7 +191,7 @@ func (s *TourService) List(request *contract.ListToursRequest) (*contract.ListTo
 			ArrivalTime:   tour.ArrivalTime,
 			MaxPeople:     tour.MaxPeople,
 			Description:   tour.Description,
-			Images:        tour.Images,
+			Images:        images[tour.ID],
 			Price:         tour.Price,
 			CompanyID:     tour.CompanyID,
 			CompanyName:   companyName,
@@ -198,6 +228,11 @@ func (s *TourService) GetMyTours(companyID int, page, limit int) (*contract.GetM
 		return nil, util.WrapError("Erro ao buscar passeios da empresa", err, http.StatusInternalServerError)
 	}
 
+	images, err := s.tourImages(tourIDs(tours), true)
+	if err != nil {
+		return nil, err
+	}
+
 	var toursResponse []contract.MyTourResponse
 	for _, tour := range tours {
 		reservationsCount, err := s.TourRepository.CountReservationsByTourID(tour.ID)
@@ -213,7 +248,7 @@ func (s *TourService) GetMyTours(companyID int, page, limit int) (*contract.GetM
 			ArrivalTime:       tour.ArrivalTime,
 			MaxPeople:         tour.MaxPeople,
 			Description:       tour.Description,
-			Images:            tour.Images,
+			Images:            images[tour.ID],
 			Price:             tour.Price,
 			CreatedAt:         tour.CreatedAt.Format("2006-01-02 15:04:05"),
 			ReservationsCount: reservationsCount,
@@ -266,3 +301,75 @@ func (s *TourService) Delete(tourID, companyID int) (*contract.DeleteTourRespons
 
 	return response, nil
 }
+
+// checkTourImages - confere se as imagens informadas pertencem à empresa e não estão vinculadas a outro passeio
+// (tourID é zero na criação)
+func (s *TourService) checkTourImages(imageIDs []int, companyID, tourID int) error {
+	if len(imageIDs) == 0 {
+		return nil
+	}
+
+	images, err := s.ImageService.ImageRepository.GetByIDs(imageIDs, companyID)
+	if err != nil {
+		return util.WrapError("Erro ao buscar imagens do passeio", err, http.StatusInternalServerError)
+	}
+
+	if len(images) != len(imageIDs) {
+		return util.WrapError("Algumas imagens não foram encontradas ou não pertencem à empresa", nil, http.StatusForbidden)
+	}
+
+	for _, image := range images {
+		if image.TourID != nil && *image.TourID != tourID {
+			return util.WrapError(fmt.Sprintf("A imagem %d já está vinculada a outro passeio", image.ID), nil, http.StatusConflict)
+		}
+	}
+
+	return nil
+}
+
+// tourImagesUnavailableError - erro de quando as imagens mudaram de dono ou de passeio entre a conferência e a gravação
+func tourImagesUnavailableError() error {
+	return util.WrapError("Algumas imagens não estão mais disponíveis para o passeio", nil, http.StatusConflict)
+}
+
+// tourImages - imagens dos passeios já convertidas para resposta, agrupadas pelo ID do passeio, na ordem de
+// exibição e com a principal primeiro; passeios sem imagens recebem uma lista vazia. As imagens privadas e as
+// ainda não processadas só são incluídas para a empresa dona dos passeios (owner).
+func (s *TourService) tourImages(tourIDs []int, owner bool) (map[int][]contract.ImageResponse, error) {
+
+	images, err := s.ImageService.ImageRepository.ListByTourIDs(tourIDs, owner)
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar imagens dos passeios", err, http.StatusInternalServerError)
+	}
+
+	var imageIDs []int
+	for _, tourImages := range images {
+		for _, image := range tourImages {
+			imageIDs = append(imageIDs, image.ID)
+		}
+	}
+
+	renditions, err := s.ImageService.ImageRepository.ListRenditions(imageIDs)
+	if err != nil {
+		return nil, util.WrapError("Erro ao buscar versões das imagens dos passeios", err, http.StatusInternalServerError)
+	}
+
+	responses := make(map[int][]contract.ImageResponse, len(tourIDs))
+	for _, tourID := range tourIDs {
+		responses[tourID] = make([]contract.ImageResponse, 0, len(images[tourID]))
+		for _, image := range images[tourID] {
+			responses[tourID] = append(responses[tourID], s.ImageService.modelToResponse(image, renditions[image.ID]))
+		}
+	}
+
+	return responses, nil
+}
+
+// tourIDs - IDs dos passeios da página
+func tourIDs(tours []*model.Tour) []int {
+	ids := make([]int, 0, len(tours))
+	for _, tour := range tours {
+		ids = append(ids, tour.ID)
+	}
+	return ids
+}
@@ -24,11 +24,19 @@ type Config struct {
 	DatabaseLog                       string
 
 	// Mercado Pago
-	MercadoPagoAccessToken   string
-	MercadoPagoPublicKey     string
-	MercadoPagoWebhookSecret string
-	MercadoPagoEnvironment   string
-	MercadoPagoBaseURL       string
+	MercadoPagoAccessToken      string
+	MercadoPagoPublicKey        string
+	MercadoPagoWebhookSecret    string
+	MercadoPagoSellerTokenKey   string
+	MercadoPagoEnvironment      string
+	MercadoPagoBaseURL          string
+	MercadoPagoTimeout          string
+	MercadoPagoMaxRetries       string
+	MercadoPagoBreakerThreshold string
+	MercadoPagoBreakerCooldown  string
+	PIXExpiration               string
+	BoletoExpiration            string
+	PlatformCommissionRate      string
 
 	// JWT
 	JWTSecret                 string
@@ -40,6 +48,32 @@ type Config struct {
 	RedisPort     string
 	RedisPassword string
 	RedisDB       string
+
+	// Tarefas agendadas
+	PaymentReconcileInterval string
+	PaymentReconcileMinAge   string
+	PIXExpirationInterval    string
+	ImageGCInterval          string
+	ImageGCMaxAge            string
+
+	// Armazenamento de arquivos
+	StorageBackend     string
+	StorageLocalDir    string
+	S3Endpoint         string
+	S3Region           string
+	S3Bucket           string
+	S3AccessKeyID      string
+	S3SecretAccessKey  string
+	S3UsePathStyle     string
+	PublicMediaBaseURL string
+	MediaURLSecret     string
+	MediaURLTTL        string
+
+	// Imagens
+	ImageRenditionWidths string
+	ImageQuotaCompany    string
+	ImageQuotaPlans      string
+	ImageWorkers         string
 }
 
 // Validate - valida os parâmetros da requisição
@@ -69,6 +103,33 @@ func (receiver Config) Validate() (err error) {
 		// Validações Redis
 		validation.Field(&receiver.RedisHost, validation.Required),
 		validation.Field(&receiver.RedisPort, validation.Required),
+
+		// Validações do armazenamento
+		validation.Field(&receiver.StorageBackend, validation.In("local", "s3")),
+	)
+	if err != nil {
+		return
+	}
+
+	// em produção as notificações do Mercado Pago só são aceitas com a assinatura validada
+	if receiver.MercadoPagoEnvironment == "production" {
+		err = validation.ValidateStruct(&receiver,
+			validation.Field(&receiver.MercadoPagoWebhookSecret, validation.Required),
+		)
+		if err != nil {
+			return
+		}
+	}
+
+	if receiver.StorageBackend != "s3" {
+		return
+	}
+
+	err = validation.ValidateStruct(&receiver,
+		validation.Field(&receiver.S3Endpoint, validation.Required),
+		validation.Field(&receiver.S3Bucket, validation.Required),
+		validation.Field(&receiver.S3AccessKeyID, validation.Required),
+		validation.Field(&receiver.S3SecretAccessKey, validation.Required),
 	)
 	return
 }
@@ -90,11 +151,19 @@ func LoadConfig() (config *Config, err error) {
 		DatabaseLog:                       os.Getenv("DATABASE_POSTGRES_LOG"),
 
 		// Mercado Pago
-		MercadoPagoAccessToken:   os.Getenv("MERCADO_PAGO_ACCESS_TOKEN"),
-		MercadoPagoPublicKey:     os.Getenv("MERCADO_PAGO_PUBLIC_KEY"),
-		MercadoPagoWebhookSecret: os.Getenv("MERCADO_PAGO_WEBHOOK_SECRET"),
-		MercadoPagoEnvironment:   os.Getenv("MERCADO_PAGO_ENVIRONMENT"),
-		MercadoPagoBaseURL:       os.Getenv("MERCADO_PAGO_BASE_URL"),
+		MercadoPagoAccessToken:      os.Getenv("MERCADO_PAGO_ACCESS_TOKEN"),
+		MercadoPagoPublicKey:        os.Getenv("MERCADO_PAGO_PUBLIC_KEY"),
+		MercadoPagoWebhookSecret:    os.Getenv("MERCADO_PAGO_WEBHOOK_SECRET"),
+		MercadoPagoSellerTokenKey:   os.Getenv("MERCADO_PAGO_SELLER_TOKEN_KEY"),
+		MercadoPagoEnvironment:      os.Getenv("MERCADO_PAGO_ENVIRONMENT"),
+		MercadoPagoBaseURL:          os.Getenv("MERCADO_PAGO_BASE_URL"),
+		MercadoPagoTimeout:          os.Getenv("MERCADO_PAGO_TIMEOUT"),
+		MercadoPagoMaxRetries:       os.Getenv("MERCADO_PAGO_MAX_RETRIES"),
+		MercadoPagoBreakerThreshold: os.Getenv("MERCADO_PAGO_BREAKER_THRESHOLD"),
+		MercadoPagoBreakerCooldown:  os.Getenv("MERCADO_PAGO_BREAKER_COOLDOWN"),
+		PIXExpiration:               os.Getenv("PIX_EXPIRATION"),
+		BoletoExpiration:            os.Getenv("BOLETO_EXPIRATION"),
+		PlatformCommissionRate:      os.Getenv("PLATFORM_COMMISSION_RATE"),
 
 		// JWT
 		JWTSecret:                 os.Getenv("JWT_SECRET"),
@@ -106,6 +175,32 @@ func LoadConfig() (config *Config, err error) {
 		RedisPort:     os.Getenv("REDIS_PORT"),
 		RedisPassword: os.Getenv("REDIS_PASSWORD"),
 		RedisDB:       os.Getenv("REDIS_DB"),
+
+		// Tarefas agendadas
+		PaymentReconcileInterval: os.Getenv("PAYMENT_RECONCILE_INTERVAL"),
+		PaymentReconcileMinAge:   os.Getenv("PAYMENT_RECONCILE_MIN_AGE"),
+		PIXExpirationInterval:    os.Getenv("PIX_EXPIRATION_INTERVAL"),
+		ImageGCInterval:          os.Getenv("IMAGE_GC_INTERVAL"),
+		ImageGCMaxAge:            os.Getenv("IMAGE_GC_MAX_AGE"),
+
+		// Armazenamento de arquivos
+		StorageBackend:     os.Getenv("STORAGE_BACKEND"),
+		StorageLocalDir:    os.Getenv("STORAGE_LOCAL_DIR"),
+		S3Endpoint:         os.Getenv("S3_ENDPOINT"),
+		S3Region:           os.Getenv("S3_REGION"),
+		S3Bucket:           os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:      os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey:  os.Getenv("S3_SECRET_ACCESS_KEY"),
+		S3UsePathStyle:     os.Getenv("S3_USE_PATH_STYLE"),
+		PublicMediaBaseURL: os.Getenv("PUBLIC_MEDIA_BASE_URL"),
+		MediaURLSecret:     os.Getenv("MEDIA_URL_SECRET"),
+		MediaURLTTL:        os.Getenv("MEDIA_URL_TTL"),
+
+		// Imagens
+		ImageRenditionWidths: os.Getenv("IMAGE_RENDITION_WIDTHS"),
+		ImageQuotaCompany:    os.Getenv("IMAGE_QUOTA_COMPANY"),
+		ImageQuotaPlans:      os.Getenv("IMAGE_QUOTA_PLANS"),
+		ImageWorkers:         os.Getenv("IMAGE_WORKERS"),
 	}
 
 	if err = config.Validate(); err != nil {
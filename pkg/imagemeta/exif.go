@@ -0,0 +1,244 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// Tags EXIF lidas
+const (
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+)
+
+// exifHeader - prefixo do segmento APP1 do JPEG (e de alguns chunks EXIF do WebP) antes do TIFF
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifDateLayout - formato das datas no EXIF
+const exifDateLayout = "2006:01:02 15:04:05"
+
+// Metadata - informações lidas do EXIF da imagem
+type Metadata struct {
+	// Orientation - valor da tag Orientation (1 a 8); 1 quando ausente ou inválida
+	Orientation int
+	// CapturedAt - data da captura (DateTimeOriginal, ou DateTime na falta dela)
+	CapturedAt *time.Time
+}
+
+// Read - lê a orientação e a data de captura do EXIF de imagens JPEG, PNG e WebP.
+// Metadados ausentes ou corrompidos são ignorados e resultam nos valores padrão.
+func Read(data []byte, format string) Metadata {
+
+	meta := Metadata{Orientation: 1}
+
+	tiff := findExif(data, format)
+	if tiff == nil {
+		return meta
+	}
+
+	parseTIFF(tiff, &meta)
+	return meta
+}
+
+// findExif - localiza o bloco TIFF do EXIF no arquivo
+func findExif(data []byte, format string) []byte {
+	switch format {
+	case "jpg", "jpeg":
+		segments, _ := jpegSegments(data)
+		for _, s := range segments {
+			if s.marker == markerAPP1 && bytes.HasPrefix(s.payload, exifHeader) {
+				return s.payload[len(exifHeader):]
+			}
+		}
+	case "png":
+		chunks, _ := pngChunks(data)
+		for _, c := range chunks {
+			if c.kind == "eXIf" {
+				return c.payload
+			}
+		}
+	case "webp":
+		chunks, _ := webpChunks(data)
+		for _, c := range chunks {
+			if c.kind == "EXIF" {
+				return bytes.TrimPrefix(c.payload, exifHeader)
+			}
+		}
+	}
+	return nil
+}
+
+// tiffReader - leitura dos IFDs de um bloco TIFF respeitando a ordem de bytes declarada
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// ifdEntry - entrada de um IFD
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// parseTIFF - preenche os metadados a partir do IFD0 e do sub-IFD EXIF
+func parseTIFF(data []byte, meta *Metadata) {
+
+	if len(data) < 8 {
+		return
+	}
+
+	r := &tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return
+	}
+
+	if r.order.Uint16(data[2:4]) != 42 {
+		return
+	}
+
+	var dateTime, dateTimeOriginal, offsetTimeOriginal string
+	var exifOffset uint32
+
+	for _, entry := range r.entries(r.order.Uint32(data[4:8])) {
+		switch entry.tag {
+		case tagOrientation:
+			if orientation := int(r.uint(entry)); orientation >= 1 && orientation <= 8 {
+				meta.Orientation = orientation
+			}
+		case tagDateTime:
+			dateTime = r.ascii(entry)
+		case tagExifIFD:
+			exifOffset = r.uint(entry)
+		}
+	}
+
+	if exifOffset != 0 {
+		for _, entry := range r.entries(exifOffset) {
+			switch entry.tag {
+			case tagDateTimeOriginal:
+				dateTimeOriginal = r.ascii(entry)
+			case tagOffsetTimeOriginal:
+				offsetTimeOriginal = r.ascii(entry)
+			}
+		}
+	}
+
+	if capturedAt, ok := parseExifDate(dateTimeOriginal, offsetTimeOriginal); ok {
+		meta.CapturedAt = &capturedAt
+	} else if capturedAt, ok := parseExifDate(dateTime, ""); ok {
+		meta.CapturedAt = &capturedAt
+	}
+}
+
+// entries - lê as entradas do IFD no deslocamento informado
+func (r *tiffReader) entries(offset uint32) []ifdEntry {
+
+	if uint64(offset)+2 > uint64(len(r.data)) {
+		return nil
+	}
+
+	count := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(r.data) {
+		return nil
+	}
+
+	entries := make([]ifdEntry, 0, count)
+	for i := 0; i < count; i++ {
+		raw := r.data[start+i*12 : start+(i+1)*12]
+		entry := ifdEntry{
+			tag:   r.order.Uint16(raw[0:2]),
+			typ:   r.order.Uint16(raw[2:4]),
+			count: r.order.Uint32(raw[4:8]),
+		}
+
+		size := uint64(typeSize(entry.typ)) * uint64(entry.count)
+		if size == 0 {
+			continue
+		}
+
+		// valores de até 4 bytes ficam na própria entrada; os maiores, no deslocamento indicado
+		if size <= 4 {
+			entry.value = raw[8 : 8+size]
+		} else {
+			valueOffset := uint64(r.order.Uint32(raw[8:12]))
+			if valueOffset+size > uint64(len(r.data)) {
+				continue
+			}
+			entry.value = r.data[valueOffset : valueOffset+size]
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// uint - valor inteiro de uma entrada SHORT ou LONG
+func (r *tiffReader) uint(entry ifdEntry) uint32 {
+	switch entry.typ {
+	case 3:
+		return uint32(r.order.Uint16(entry.value))
+	case 4:
+		return r.order.Uint32(entry.value)
+	default:
+		return 0
+	}
+}
+
+// ascii - valor textual de uma entrada ASCII, sem o terminador
+func (r *tiffReader) ascii(entry ifdEntry) string {
+	if entry.typ != 2 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(entry.value), "\x00"))
+}
+
+// typeSize - tamanho em bytes de cada valor dos tipos TIFF
+func typeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 6, 7:
+		return 1
+	case 3, 8:
+		return 2
+	case 4, 9, 11:
+		return 4
+	case 5, 10, 12:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// parseExifDate - converte a data do EXIF, usando o fuso de OffsetTimeOriginal quando presente (UTC caso contrário)
+func parseExifDate(value, offset string) (time.Time, bool) {
+
+	if value == "" || strings.HasPrefix(value, "0000") {
+		return time.Time{}, false
+	}
+
+	if offset != "" {
+		if t, err := time.Parse(exifDateLayout+"-07:00", value+offset); err == nil {
+			return t, true
+		}
+	}
+
+	t, err := time.ParseInLocation(exifDateLayout, value, time.UTC)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
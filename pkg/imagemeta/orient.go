@@ -0,0 +1,59 @@
+package imagemeta
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Orient - aplica a orientação do EXIF, devolvendo a imagem na posição em que deve ser exibida.
+// Nas orientações 5 a 8 a largura e a altura são trocadas.
+func Orient(img image.Image, orientation int) image.Image {
+
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := sourcePoint(orientation, x, y, dw, dh)
+			si := src.PixOffset(sx, sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+
+	return dst
+}
+
+// sourcePoint - coordenada da imagem original que ocupa o ponto (x, y) da imagem orientada de dimensões dw x dh
+func sourcePoint(orientation, x, y, dw, dh int) (int, int) {
+	switch orientation {
+	case 2: // espelhada na horizontal
+		return dw - 1 - x, y
+	case 3: // girada 180°
+		return dw - 1 - x, dh - 1 - y
+	case 4: // espelhada na vertical
+		return x, dh - 1 - y
+	case 5: // transposta
+		return y, x
+	case 6: // girar 90° no sentido horário
+		return y, dw - 1 - x
+	case 7: // transversa
+		return dh - 1 - y, dw - 1 - x
+	case 8: // girar 90° no sentido anti-horário
+		return dh - 1 - y, x
+	default:
+		return x, y
+	}
+}
@@ -0,0 +1,288 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidImage - o arquivo não segue a estrutura do formato informado
+var ErrInvalidImage = errors.New("estrutura da imagem inválida")
+
+// Marcadores JPEG
+const (
+	markerSOI   = 0xD8
+	markerEOI   = 0xD9
+	markerSOS   = 0xDA
+	markerAPP0  = 0xE0
+	markerAPP1  = 0xE1
+	markerAPP2  = 0xE2
+	markerAPP14 = 0xEE
+	markerAPP15 = 0xEF
+	markerCOM   = 0xFE
+)
+
+// iccProfileHeader - prefixo dos segmentos APP2 com o perfil de cor
+var iccProfileHeader = []byte("ICC_PROFILE\x00")
+
+// pngSignature - assinatura dos arquivos PNG
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks - chunks PNG com metadados removidos; iCCP, sRGB, gAMA e cHRM são mantidos por afetarem as cores
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// Flags do chunk VP8X do WebP que indicam a presença de metadados
+const (
+	vp8xFlagXMP  = 0x04
+	vp8xFlagEXIF = 0x08
+)
+
+// segment - trecho do arquivo com o conteúdo bruto (incluindo cabeçalho) e a carga útil
+type segment struct {
+	kind    string
+	marker  byte
+	raw     []byte
+	payload []byte
+}
+
+// Strip - remove os metadados EXIF, XMP, IPTC e comentários da imagem, mantendo o perfil de cor.
+// Dados anexados depois do fim da imagem também são descartados. GIFs são devolvidos sem alteração.
+func Strip(data []byte, format string) ([]byte, error) {
+	switch format {
+	case "jpg", "jpeg":
+		segments, ok := jpegSegments(data)
+		if !ok {
+			return nil, ErrInvalidImage
+		}
+		var out bytes.Buffer
+		for _, s := range segments {
+			if keepJPEGSegment(s) {
+				out.Write(s.raw)
+			}
+		}
+		return out.Bytes(), nil
+	case "png":
+		chunks, ok := pngChunks(data)
+		if !ok {
+			return nil, ErrInvalidImage
+		}
+		var out bytes.Buffer
+		out.Write(pngSignature)
+		for _, c := range chunks {
+			if !pngMetadataChunks[c.kind] {
+				out.Write(c.raw)
+			}
+		}
+		return out.Bytes(), nil
+	case "webp":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+// CopyJPEGProfile - copia o perfil de cor do JPEG original para um JPEG recodificado, logo após o SOI
+func CopyJPEGProfile(encoded, original []byte) []byte {
+
+	segments, ok := jpegSegments(original)
+	if !ok || len(encoded) < 2 {
+		return encoded
+	}
+
+	var profile bytes.Buffer
+	for _, s := range segments {
+		if s.marker == markerAPP2 && bytes.HasPrefix(s.payload, iccProfileHeader) {
+			profile.Write(s.raw)
+		}
+	}
+
+	if profile.Len() == 0 {
+		return encoded
+	}
+
+	out := make([]byte, 0, len(encoded)+profile.Len())
+	out = append(out, encoded[:2]...)
+	out = append(out, profile.Bytes()...)
+	return append(out, encoded[2:]...)
+}
+
+// keepJPEGSegment - mantém JFIF, o perfil ICC e o segmento Adobe (necessário para interpretar as cores), descartando os demais APPn e comentários
+func keepJPEGSegment(s segment) bool {
+	switch {
+	case s.marker == markerAPP0:
+		return true
+	case s.marker == markerAPP2:
+		return bytes.HasPrefix(s.payload, iccProfileHeader)
+	case s.marker == markerAPP14:
+		return bytes.HasPrefix(s.payload, []byte("Adobe"))
+	case s.marker >= markerAPP0 && s.marker <= markerAPP15, s.marker == markerCOM:
+		return false
+	default:
+		return true
+	}
+}
+
+// jpegSegments - separa o JPEG em segmentos até o EOI; os dados comprimidos acompanham o segmento SOS
+func jpegSegments(data []byte) ([]segment, bool) {
+
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return nil, false
+	}
+
+	segments := []segment{{marker: markerSOI, raw: data[:2]}}
+	pos := 2
+
+	for pos < len(data) {
+		start := pos
+		if data[pos] != 0xFF {
+			return nil, false
+		}
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, false
+		}
+
+		marker := data[pos]
+		pos++
+
+		if marker == markerEOI {
+			return append(segments, segment{marker: marker, raw: data[start:pos]}), true
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			segments = append(segments, segment{marker: marker, raw: data[start:pos]})
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return nil, false
+		}
+		length := int(binary.BigEndian.Uint16(data[pos:]))
+		if length < 2 || pos+length > len(data) {
+			return nil, false
+		}
+
+		end := pos + length
+		if marker == markerSOS {
+			// os dados comprimidos terminam no primeiro marcador que não seja byte de escape nem RST
+			for end < len(data) {
+				if data[end] == 0xFF && end+1 < len(data) {
+					next := data[end+1]
+					if next == 0x00 || (next >= 0xD0 && next <= 0xD7) {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+		}
+
+		segments = append(segments, segment{marker: marker, raw: data[start:end], payload: data[pos+2 : pos+length]})
+		pos = end
+	}
+
+	// arquivo sem EOI: mantém o que foi lido
+	return segments, true
+}
+
+// pngChunks - separa o PNG em chunks até o IEND
+func pngChunks(data []byte) ([]segment, bool) {
+
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, false
+	}
+
+	var chunks []segment
+	pos := len(pngSignature)
+
+	for pos+8 <= len(data) {
+		length := uint64(binary.BigEndian.Uint32(data[pos:]))
+		end := uint64(pos) + 12 + length
+		if end > uint64(len(data)) {
+			return nil, false
+		}
+
+		kind := string(data[pos+4 : pos+8])
+		chunks = append(chunks, segment{kind: kind, raw: data[pos:end], payload: data[pos+8 : uint64(pos)+8+length]})
+		pos = int(end)
+
+		if kind == "IEND" {
+			return chunks, true
+		}
+	}
+
+	return nil, false
+}
+
+// webpChunks - separa o conteúdo do contêiner RIFF do WebP em chunks
+func webpChunks(data []byte) ([]segment, bool) {
+
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, false
+	}
+
+	riffEnd := uint64(binary.LittleEndian.Uint32(data[4:8])) + 8
+	if riffEnd > uint64(len(data)) {
+		riffEnd = uint64(len(data))
+	}
+
+	var chunks []segment
+	pos := uint64(12)
+
+	for pos+8 <= riffEnd {
+		size := uint64(binary.LittleEndian.Uint32(data[pos+4:]))
+		payloadEnd := pos + 8 + size
+		if payloadEnd > riffEnd {
+			return nil, false
+		}
+
+		end := payloadEnd + size%2
+		if end > riffEnd {
+			end = riffEnd
+		}
+
+		chunks = append(chunks, segment{kind: string(data[pos : pos+4]), raw: data[pos:end], payload: data[pos+8 : payloadEnd]})
+		pos = end
+	}
+
+	return chunks, len(chunks) > 0
+}
+
+// stripWebP - remove os chunks EXIF e XMP e atualiza as flags do VP8X e o tamanho do RIFF
+func stripWebP(data []byte) ([]byte, error) {
+
+	chunks, ok := webpChunks(data)
+	if !ok {
+		return nil, ErrInvalidImage
+	}
+
+	var out bytes.Buffer
+	out.WriteString("RIFF\x00\x00\x00\x00WEBP")
+
+	for _, c := range chunks {
+		switch c.kind {
+		case "EXIF", "XMP ":
+			continue
+		case "VP8X":
+			raw := bytes.Clone(c.raw)
+			if len(raw) > 8 {
+				raw[8] &^= vp8xFlagEXIF | vp8xFlagXMP
+			}
+			out.Write(raw)
+		default:
+			out.Write(c.raw)
+		}
+	}
+
+	result := out.Bytes()
+	binary.LittleEndian.PutUint32(result[4:8], uint32(len(result)-8))
+	return result, nil
+}
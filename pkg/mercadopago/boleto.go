@@ -0,0 +1,83 @@
+package mercadopago
+
+import "context"
+
+// Métodos de pagamento de boleto suportados pelo Mercado Pago
+const (
+	BoletoBradesco = "bolbradesco"
+	BoletoLoterica = "pec"
+)
+
+// BoletoRequest - representa a estrutura para criar um pagamento com boleto
+type BoletoRequest struct {
+	TransactionAmount float64           `json:"transaction_amount"`
+	Description       string            `json:"description"`
+	PaymentMethodID   string            `json:"payment_method_id"`
+	Payer             BoletoPayer       `json:"payer"`
+	DateOfExpiration  string            `json:"date_of_expiration,omitempty"`
+	ApplicationFee    float64           `json:"application_fee,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// BoletoPayer - representa o pagador do boleto; CPF e endereço são obrigatórios
+type BoletoPayer struct {
+	Email          string                   `json:"email"`
+	FirstName      string                   `json:"first_name"`
+	LastName       string                   `json:"last_name"`
+	Identification CreditCardIdentification `json:"identification"`
+	Address        BoletoAddress            `json:"address"`
+}
+
+// BoletoAddress - representa o endereço do pagador exigido na emissão do boleto
+type BoletoAddress struct {
+	ZipCode      string `json:"zip_code"`
+	StreetName   string `json:"street_name"`
+	StreetNumber string `json:"street_number"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	FederalUnit  string `json:"federal_unit"`
+}
+
+// BoletoResponse - representa a resposta da criação de um pagamento com boleto
+type BoletoResponse struct {
+	ID                 int64                    `json:"id"`
+	Status             string                   `json:"status"`
+	StatusDetail       string                   `json:"status_detail"`
+	TransactionAmount  float64                  `json:"transaction_amount"`
+	Description        string                   `json:"description"`
+	PaymentMethodID    string                   `json:"payment_method_id"`
+	DateCreated        string                   `json:"date_created"`
+	DateOfExpiration   string                   `json:"date_of_expiration,omitempty"`
+	Barcode            BoletoBarcode            `json:"barcode"`
+	TransactionDetails BoletoTransactionDetails `json:"transaction_details"`
+}
+
+// BoletoBarcode - representa o código de barras do boleto
+type BoletoBarcode struct {
+	Content string `json:"content"`
+}
+
+// BoletoTransactionDetails - representa os dados do boleto emitido
+type BoletoTransactionDetails struct {
+	ExternalResourceURL string        `json:"external_resource_url"`
+	DigitableLine       string        `json:"digitable_line"`
+	Barcode             BoletoBarcode `json:"barcode"`
+}
+
+// BarcodeContent - retorna o código de barras, que pode vir na raiz ou em transaction_details
+func (r *BoletoResponse) BarcodeContent() string {
+	if r.TransactionDetails.Barcode.Content != "" {
+		return r.TransactionDetails.Barcode.Content
+	}
+	return r.Barcode.Content
+}
+
+// CreateBoletoPayment - cria um pagamento com boleto no Mercado Pago
+func (c *Client) CreateBoletoPayment(ctx context.Context, req *BoletoRequest) (*BoletoResponse, error) {
+	var boleto BoletoResponse
+	if err := c.doJSONRequest(ctx, "POST", "/v1/payments", req, &boleto); err != nil {
+		return nil, err
+	}
+	return &boleto, nil
+}
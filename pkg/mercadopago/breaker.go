@@ -0,0 +1,101 @@
+package mercadopago
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker - interrompe as chamadas ao Mercado Pago após falhas consecutivas de disponibilidade,
+// liberando uma única chamada de teste depois do período de espera
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// breakers - circuitos compartilhados por URL base, já que os services criam um cliente por requisição
+var breakers sync.Map
+
+// NewCircuitBreaker - cria um circuito que abre após threshold falhas consecutivas
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+	}
+}
+
+// breakerFor - retorna o circuito compartilhado da URL base, criando-o no primeiro uso
+func breakerFor(baseURL string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	breaker, _ := breakers.LoadOrStore(baseURL, NewCircuitBreaker(threshold, cooldown))
+	return breaker.(*CircuitBreaker)
+}
+
+// Allow - indica se uma chamada pode ser executada; com o circuito aberto apenas a chamada de teste passa
+func (b *CircuitBreaker) Allow(now time.Time) error {
+
+	if b == nil || b.Threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.Threshold {
+		return nil
+	}
+
+	if now.Before(b.openUntil) || b.probing {
+		return ErrCircuitOpen
+	}
+
+	b.probing = true
+	return nil
+}
+
+// Success - registra uma chamada bem-sucedida, fechando o circuito
+func (b *CircuitBreaker) Success() {
+
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure - registra uma falha de disponibilidade, abrindo o circuito ao atingir o limite
+func (b *CircuitBreaker) Failure(now time.Time) {
+
+	if b == nil || b.Threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.failures >= b.Threshold {
+		b.openUntil = now.Add(b.Cooldown)
+	}
+}
+
+// release - libera a chamada de teste interrompida pelo contexto, sem contabilizar sucesso ou falha
+func (b *CircuitBreaker) release() {
+
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
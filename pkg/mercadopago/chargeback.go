@@ -0,0 +1,108 @@
+package mercadopago
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	"github.com/jampa_trip/pkg/util"
+)
+
+// Status da documentação de um chargeback
+const (
+	DocumentationPending       = "pending"
+	DocumentationReviewPending = "review_pending"
+	DocumentationValid         = "valid"
+	DocumentationInvalid       = "invalid"
+	DocumentationNotSupplied   = "not_supplied"
+)
+
+// ChargebackResponse - representa um chargeback do Mercado Pago
+type ChargebackResponse struct {
+	ID                        string  `json:"id"`
+	Payments                  []int64 `json:"payments"`
+	Currency                  string  `json:"currency"`
+	Amount                    float64 `json:"amount"`
+	CoverageApplied           bool    `json:"coverage_applied"`
+	CoverageEligible          bool    `json:"coverage_elegible"`
+	DocumentationRequired     bool    `json:"documentation_required"`
+	DocumentationStatus       string  `json:"documentation_status"`
+	DateDocumentationDeadline string  `json:"date_documentation_deadline"`
+	DateCreated               string  `json:"date_created"`
+	DateLastUpdated           string  `json:"date_last_updated"`
+	LiveMode                  bool    `json:"live_mode"`
+}
+
+// PaymentID - retorna o pagamento contestado pelo chargeback
+func (c *ChargebackResponse) PaymentID() string {
+	if len(c.Payments) == 0 {
+		return ""
+	}
+	return strconv.FormatInt(c.Payments[0], 10)
+}
+
+// Won - indica se o chargeback foi decidido a favor do vendedor
+func (c *ChargebackResponse) Won() bool {
+	return c.CoverageApplied || c.DocumentationStatus == DocumentationValid
+}
+
+// Lost - indica se o chargeback foi decidido a favor do comprador
+func (c *ChargebackResponse) Lost() bool {
+	return !c.CoverageApplied &&
+		(c.DocumentationStatus == DocumentationInvalid || c.DocumentationStatus == DocumentationNotSupplied)
+}
+
+// ChargebackFile - representa um arquivo de evidência enviado ao Mercado Pago
+type ChargebackFile struct {
+	Name        string
+	ContentType string
+	Content     io.Reader
+}
+
+// GetChargeback - obtém um chargeback pelo ID
+func (c *Client) GetChargeback(ctx context.Context, chargebackID string) (*ChargebackResponse, error) {
+	var chargeback ChargebackResponse
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/chargebacks/%s", chargebackID), nil, &chargeback); err != nil {
+		return nil, err
+	}
+	return &chargeback, nil
+}
+
+// SubmitChargebackDocumentation - envia os arquivos de evidência de um chargeback
+func (c *Client) SubmitChargebackDocumentation(ctx context.Context, chargebackID string, files []ChargebackFile) error {
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, file := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[]"; filename=%q`, file.Name))
+		header.Set("Content-Type", file.ContentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return util.WrapError("erro ao montar documentação do chargeback", err, http.StatusInternalServerError)
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return util.WrapError("erro ao montar documentação do chargeback", err, http.StatusInternalServerError)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return util.WrapError("erro ao montar documentação do chargeback", err, http.StatusInternalServerError)
+	}
+
+	// sem chave de idempotência o envio não é repetido automaticamente, evitando documentação duplicada
+	_, err := c.do(ctx, &apiRequest{
+		method:      http.MethodPost,
+		path:        fmt.Sprintf("/v1/chargebacks/%s/documentation", chargebackID),
+		contentType: writer.FormDataContentType(),
+		body:        body.Bytes(),
+	})
+	return err
+}
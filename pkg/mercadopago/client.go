@@ -1,45 +1,123 @@
 package mercadopago
 
 import (
-	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/jampa_trip/pkg/util"
 )
 
+// DateTimeLayout - formato de data e hora aceito pela API do Mercado Pago
+const DateTimeLayout = "2006-01-02T15:04:05.000-07:00"
+
 // Client - representa o cliente HTTP para comunicação com a API do Mercado Pago
 type Client struct {
 	AccessToken string
 	BaseURL     string
 	HTTPClient  *http.Client
+	Options     Options
+	Breaker     *CircuitBreaker
 }
 
-// NewClient - cria uma nova instância do cliente Mercado Pago
+// NewClient - cria uma nova instância do cliente Mercado Pago com as opções padrão de resiliência
 func NewClient(accessToken, baseURL string) *Client {
+	return NewClientWithOptions(accessToken, baseURL, DefaultOptions())
+}
+
+// NewClientWithOptions - cria uma nova instância do cliente Mercado Pago com timeout, retentativas e circuit breaker configurados
+func NewClientWithOptions(accessToken, baseURL string, options Options) *Client {
+
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
 	return &Client{
 		AccessToken: accessToken,
 		BaseURL:     baseURL,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: options.Timeout,
 		},
+		Options: options,
+		Breaker: breakerFor(baseURL, options.BreakerThreshold, options.BreakerCooldown),
 	}
 }
 
+// WithAccessToken - cria uma cópia do cliente autenticada com outro token, compartilhando as configurações e o circuit breaker
+func (c *Client) WithAccessToken(accessToken string) *Client {
+	client := *c
+	client.AccessToken = accessToken
+	return &client
+}
+
 // OrderRequest - representa a estrutura para criar uma order (v1/orders)
 type OrderRequest struct {
-	ExternalReference string            `json:"external_reference"`
-	TotalAmount       float64           `json:"total_amount"`
-	Items             []OrderItem       `json:"items"`
-	Payer             Payer             `json:"payer"`
-	NotificationURL   string            `json:"notification_url,omitempty"`
-	Description       string            `json:"description,omitempty"`
-	Metadata          map[string]string `json:"metadata,omitempty"`
+	ExternalReference string             `json:"external_reference"`
+	TotalAmount       float64            `json:"total_amount"`
+	Items             []OrderItem        `json:"items"`
+	Payer             Payer              `json:"payer"`
+	NotificationURL   string             `json:"notification_url,omitempty"`
+	Description       string             `json:"description,omitempty"`
+	MarketplaceFee    float64            `json:"marketplace_fee,omitempty"`
+	Metadata          map[string]string  `json:"metadata,omitempty"`
+	Type              string             `json:"type,omitempty"`
+	ProcessingMode    string             `json:"processing_mode,omitempty"`
+	CaptureMode       string             `json:"capture_mode,omitempty"`
+	Transactions      *OrderTransactions `json:"transactions,omitempty"`
+}
+
+// OrderTransactions - representa as transações de uma order
+type OrderTransactions struct {
+	Payments []OrderPayment `json:"payments"`
+}
+
+// OrderPayment - representa um pagamento dentro de uma order
+type OrderPayment struct {
+	ID               string             `json:"id,omitempty"`
+	Amount           float64            `json:"amount"`
+	Status           string             `json:"status,omitempty"`
+	StatusDetail     string             `json:"status_detail,omitempty"`
+	ExpirationTime   string             `json:"expiration_time,omitempty"`
+	DateOfExpiration string             `json:"date_of_expiration,omitempty"`
+	PaymentMethod    OrderPaymentMethod `json:"payment_method"`
+}
+
+// OrderPaymentMethod - representa o meio de pagamento de um pagamento da order; no PIX a resposta traz o QR code
+type OrderPaymentMethod struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Token        string `json:"token,omitempty"`
+	Installments int    `json:"installments,omitempty"`
+	QRCode       string `json:"qr_code,omitempty"`
+	QRCodeBase64 string `json:"qr_code_base64,omitempty"`
+	TicketURL    string `json:"ticket_url,omitempty"`
+}
+
+// ExpirationDuration - formata um prazo como duração ISO 8601 (ex.: PT30M), o formato de expiration_time das orders
+func ExpirationDuration(d time.Duration) string {
+
+	seconds := int64(d.Round(time.Second) / time.Second)
+	if seconds <= 0 {
+		return ""
+	}
+
+	duration := "PT"
+	if hours := seconds / 3600; hours > 0 {
+		duration += fmt.Sprintf("%dH", hours)
+	}
+	if minutes := seconds % 3600 / 60; minutes > 0 {
+		duration += fmt.Sprintf("%dM", minutes)
+	}
+	if rest := seconds % 60; rest > 0 {
+		duration += fmt.Sprintf("%dS", rest)
+	}
+
+	return duration
 }
 
 // OrderItem - representa um item da order
@@ -91,6 +169,33 @@ type OrderResponse struct {
 	DateLastUpdated   string            `json:"date_last_updated"`
 	DateExpiration    string            `json:"date_expiration,omitempty"`
 	PaymentMethods    []PaymentMethod   `json:"payment_methods,omitempty"`
+	CaptureMode       string            `json:"capture_mode,omitempty"`
+	Transactions      OrderTransactions `json:"transactions"`
+}
+
+// PaymentStatus - converte o status da order para o status equivalente de pagamento
+func (o *OrderResponse) PaymentStatus() string {
+	switch o.Status {
+	case "processed":
+		return "approved"
+	case "action_required":
+		if o.StatusDetail == "waiting_capture" {
+			return "authorized"
+		}
+		return "pending"
+	case "processing":
+		return "in_process"
+	case "failed":
+		return "rejected"
+	case "canceled", "expired":
+		return "cancelled"
+	case "refunded":
+		return "refunded"
+	case "charged_back":
+		return "charged_back"
+	default:
+		return "pending"
+	}
 }
 
 // PaymentMethod - representa um método de pagamento disponível
@@ -128,17 +233,21 @@ type PaymentPayer struct {
 
 // PaymentResponse - representa a resposta da criação de um pagamento
 type PaymentResponse struct {
-	ID                int64             `json:"id"`
-	Status            string            `json:"status"`
-	StatusDetail      string            `json:"status_detail"`
-	TransactionAmount float64           `json:"transaction_amount"`
-	Description       string            `json:"description"`
-	PaymentMethodID   string            `json:"payment_method_id"`
-	Payer             PaymentPayer      `json:"payer"`
-	DateCreated       string            `json:"date_created"`
-	DateApproved      string            `json:"date_approved,omitempty"`
-	DateLastUpdated   string            `json:"date_last_updated"`
-	Metadata          map[string]string `json:"metadata,omitempty"`
+	ID                        int64              `json:"id"`
+	Status                    string             `json:"status"`
+	StatusDetail              string             `json:"status_detail"`
+	TransactionAmount         float64            `json:"transaction_amount"`
+	TransactionAmountRefunded float64            `json:"transaction_amount_refunded,omitempty"`
+	Captured                  bool               `json:"captured"`
+	Description               string             `json:"description"`
+	PaymentMethodID           string             `json:"payment_method_id"`
+	Payer                     PaymentPayer       `json:"payer"`
+	ExternalReference         string             `json:"external_reference,omitempty"`
+	DateCreated               string             `json:"date_created"`
+	DateApproved              string             `json:"date_approved,omitempty"`
+	DateLastUpdated           string             `json:"date_last_updated"`
+	PointOfInteraction        PointOfInteraction `json:"point_of_interaction,omitempty"`
+	Metadata                  map[string]string  `json:"metadata,omitempty"`
 }
 
 // PIXRequest - representa a estrutura para criar um pagamento PIX
@@ -147,6 +256,9 @@ type PIXRequest struct {
 	Description       string            `json:"description"`
 	PaymentMethodID   string            `json:"payment_method_id"`
 	Payer             PaymentPayer      `json:"payer"`
+	DateOfExpiration  string            `json:"date_of_expiration,omitempty"`
+	ApplicationFee    float64           `json:"application_fee,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"`
 	Metadata          map[string]string `json:"metadata,omitempty"`
 }
 
@@ -162,6 +274,7 @@ type PIXResponse struct {
 	DateCreated        string             `json:"date_created"`
 	DateApproved       string             `json:"date_approved,omitempty"`
 	DateLastUpdated    string             `json:"date_last_updated"`
+	DateOfExpiration   string             `json:"date_of_expiration,omitempty"`
 	PointOfInteraction PointOfInteraction `json:"point_of_interaction,omitempty"`
 	Metadata           map[string]string  `json:"metadata,omitempty"`
 }
@@ -171,6 +284,15 @@ type PointOfInteraction struct {
 	Type            string          `json:"type"`
 	SubType         string          `json:"sub_type,omitempty"`
 	ApplicationData ApplicationData `json:"application_data,omitempty"`
+	TransactionData TransactionData `json:"transaction_data,omitempty"`
+}
+
+// TransactionData - representa os dados da transação PIX
+type TransactionData struct {
+	QRCode       string `json:"qr_code,omitempty"`
+	QRCodeBase64 string `json:"qr_code_base64,omitempty"`
+	TicketURL    string `json:"ticket_url,omitempty"`
+	E2EID        string `json:"e2e_id,omitempty"`
 }
 
 // ApplicationData - representa dados da aplicação PIX
@@ -179,384 +301,88 @@ type ApplicationData struct {
 	Version string `json:"version,omitempty"`
 }
 
-// ErrorResponse - representa uma resposta de erro da API
-type ErrorResponse struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
-	Status  int    `json:"status"`
-	Cause   []struct {
-		Code        string `json:"code"`
-		Description string `json:"description"`
-	} `json:"cause,omitempty"`
-}
-
 // CreateOrder - cria uma nova order no Mercado Pago
-func (c *Client) CreateOrder(orderReq *OrderRequest) (*OrderResponse, error) {
-	url := fmt.Sprintf("%s/v1/orders", c.BaseURL)
-
-	jsonData, err := json.Marshal(orderReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar order", err, http.StatusInternalServerError)
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) CreateOrder(ctx context.Context, orderReq *OrderRequest) (*OrderResponse, error) {
 	var orderResp OrderResponse
-	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", "/v1/orders", orderReq, &orderResp); err != nil {
+		return nil, err
 	}
-
 	return &orderResp, nil
 }
 
 // GetOrder - obtém informações de uma order específica
-func (c *Client) GetOrder(orderID string) (*OrderResponse, error) {
-	url := fmt.Sprintf("%s/v1/orders/%s", c.BaseURL, orderID)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) GetOrder(ctx context.Context, orderID string) (*OrderResponse, error) {
 	var orderResp OrderResponse
-	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/orders/%s", orderID), nil, &orderResp); err != nil {
+		return nil, err
 	}
-
 	return &orderResp, nil
 }
 
 // CancelOrder - cancela uma order
-func (c *Client) CancelOrder(orderID string) (*OrderResponse, error) {
-	url := fmt.Sprintf("%s/v1/orders/%s/cancel", c.BaseURL, orderID)
-
-	req, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) CancelOrder(ctx context.Context, orderID string) (*OrderResponse, error) {
 	var orderResp OrderResponse
-	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", fmt.Sprintf("/v1/orders/%s/cancel", orderID), nil, &orderResp); err != nil {
+		return nil, err
 	}
-
 	return &orderResp, nil
 }
 
 // CaptureOrder - captura uma order totalmente
-func (c *Client) CaptureOrder(orderID string) (*OrderResponse, error) {
-	url := fmt.Sprintf("%s/v1/orders/%s/capture", c.BaseURL, orderID)
-
-	req, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) CaptureOrder(ctx context.Context, orderID string) (*OrderResponse, error) {
 	var orderResp OrderResponse
-	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", fmt.Sprintf("/v1/orders/%s/capture", orderID), nil, &orderResp); err != nil {
+		return nil, err
 	}
-
 	return &orderResp, nil
 }
 
 // RefundOrder - reembolsa uma order
-func (c *Client) RefundOrder(orderID string) (*OrderResponse, error) {
-	url := fmt.Sprintf("%s/v1/orders/%s/refund", c.BaseURL, orderID)
-
-	req, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) RefundOrder(ctx context.Context, orderID string) (*OrderResponse, error) {
 	var orderResp OrderResponse
-	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", fmt.Sprintf("/v1/orders/%s/refund", orderID), nil, &orderResp); err != nil {
+		return nil, err
 	}
-
 	return &orderResp, nil
 }
 
 // CreatePayment - cria um novo pagamento no Mercado Pago
-func (c *Client) CreatePayment(paymentReq *PaymentRequest) (*PaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments", c.BaseURL)
-
-	jsonData, err := json.Marshal(paymentReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar pagamento", err, http.StatusInternalServerError)
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) CreatePayment(ctx context.Context, paymentReq *PaymentRequest) (*PaymentResponse, error) {
 	var paymentResp PaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", "/v1/payments", paymentReq, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
 // CreatePIXPayment - cria um novo pagamento PIX no Mercado Pago
-func (c *Client) CreatePIXPayment(pixReq *PIXRequest) (*PIXResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments", c.BaseURL)
-
-	jsonData, err := json.Marshal(pixReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar pagamento PIX", err, http.StatusInternalServerError)
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) CreatePIXPayment(ctx context.Context, pixReq *PIXRequest) (*PIXResponse, error) {
 	var pixResp PIXResponse
-	if err := json.Unmarshal(body, &pixResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", "/v1/payments", pixReq, &pixResp); err != nil {
+		return nil, err
 	}
-
 	return &pixResp, nil
 }
 
 // GetPayment - obtém informações de um pagamento específico
-func (c *Client) GetPayment(paymentID string) (*PaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments/%s", c.BaseURL, paymentID)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
+func (c *Client) GetPayment(ctx context.Context, paymentID string) (*PaymentResponse, error) {
 	var paymentResp PaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/payments/%s", paymentID), nil, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
 // CancelPayment - cancela um pagamento
-func (c *Client) CancelPayment(paymentID string) (*PaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments/%s", c.BaseURL, paymentID)
-
+func (c *Client) CancelPayment(ctx context.Context, paymentID string) (*PaymentResponse, error) {
 	cancelData := map[string]string{
 		"status": "cancelled",
 	}
 
-	jsonData, err := json.Marshal(cancelData)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar dados de cancelamento", err, http.StatusInternalServerError)
-	}
-
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
 	var paymentResp PaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "PUT", fmt.Sprintf("/v1/payments/%s", paymentID), cancelData, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
@@ -572,11 +398,14 @@ type CreditCardPaymentRequest struct {
 	Capture           bool              `json:"capture"`
 	Metadata          map[string]string `json:"metadata,omitempty"`
 	ExternalReference string            `json:"external_reference,omitempty"`
+	ApplicationFee    float64           `json:"application_fee,omitempty"`
 }
 
 // CreditCardPayer - representa o pagador para pagamentos com cartão
 type CreditCardPayer struct {
-	Email          string                   `json:"email"`
+	Type           string                   `json:"type,omitempty"`
+	ID             string                   `json:"id,omitempty"`
+	Email          string                   `json:"email,omitempty"`
 	Identification CreditCardIdentification `json:"identification,omitempty"`
 	FirstName      string                   `json:"first_name,omitempty"`
 	LastName       string                   `json:"last_name,omitempty"`
@@ -702,53 +531,20 @@ type FinancialInstitution struct {
 
 // CreateCreditCardPayment - cria um pagamento com cartão de crédito
 func (c *Client) CreateCreditCardPayment(ctx context.Context, req *CreditCardPaymentRequest) (*CreditCardPaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments", c.BaseURL)
-
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar pagamento com cartão", err, http.StatusInternalServerError)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-	httpReq.Header.Set("X-Idempotency-Key", generateIdempotencyKey(req))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s - %s", errorResp.Message, errorResp.Status), nil, resp.StatusCode)
+	// a chave derivada da requisição evita cobranças duplicadas quando o cliente repete o envio
+	if idempotencyKeyFrom(ctx) == "" {
+		ctx = WithIdempotencyKey(ctx, generateIdempotencyKey(req))
 	}
 
 	var paymentResp CreditCardPaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", "/v1/payments", req, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
 // CapturePayment - captura um pagamento autorizado (total ou parcial)
 func (c *Client) CapturePayment(ctx context.Context, paymentID int64, req *CapturePaymentRequest) (*CreditCardPaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments/%d", c.BaseURL, paymentID)
-
 	captureData := map[string]interface{}{
 		"capture": true,
 	}
@@ -761,222 +557,102 @@ func (c *Client) CapturePayment(ctx context.Context, paymentID int64, req *Captu
 		captureData["metadata"] = req.Metadata
 	}
 
-	jsonData, err := json.Marshal(captureData)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar dados de captura", err, http.StatusInternalServerError)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
 	var paymentResp CreditCardPaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "PUT", fmt.Sprintf("/v1/payments/%d", paymentID), captureData, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
 // CancelCreditCardPayment - cancela um pagamento autorizado (void)
 func (c *Client) CancelCreditCardPayment(ctx context.Context, paymentID int64) (*CreditCardPaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments/%d", c.BaseURL, paymentID)
-
 	cancelData := map[string]string{
 		"status": "cancelled",
 	}
 
-	jsonData, err := json.Marshal(cancelData)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar dados de cancelamento", err, http.StatusInternalServerError)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
 	var paymentResp CreditCardPaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "PUT", fmt.Sprintf("/v1/payments/%d", paymentID), cancelData, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
 // RefundCreditCardPayment - reembolsa um pagamento capturado (total ou parcial)
 func (c *Client) RefundCreditCardPayment(ctx context.Context, paymentID int64, req *RefundPaymentRequest) (*RefundResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments/%d/refunds", c.BaseURL, paymentID)
-
-	var jsonData []byte
-	var err error
-
+	var payload interface{}
 	if req != nil {
-		jsonData, err = json.Marshal(req)
-		if err != nil {
-			return nil, util.WrapError("erro ao serializar dados de reembolso", err, http.StatusInternalServerError)
-		}
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
+		payload = req
 	}
 
 	var refundResp RefundResponse
-	if err := json.Unmarshal(body, &refundResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", fmt.Sprintf("/v1/payments/%d/refunds", paymentID), payload, &refundResp); err != nil {
+		return nil, err
 	}
-
 	return &refundResp, nil
 }
 
 // GetCreditCardPayment - obtém informações de um pagamento específico
 func (c *Client) GetCreditCardPayment(ctx context.Context, paymentID int64) (*CreditCardPaymentResponse, error) {
-	url := fmt.Sprintf("%s/v1/payments/%d", c.BaseURL, paymentID)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
 	var paymentResp CreditCardPaymentResponse
-	if err := json.Unmarshal(body, &paymentResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/payments/%d", paymentID), nil, &paymentResp); err != nil {
+		return nil, err
 	}
-
 	return &paymentResp, nil
 }
 
 // GetPaymentMethods - obtém a lista de meios de pagamento disponíveis
 func (c *Client) GetPaymentMethods(ctx context.Context) (*PaymentMethodsResponse, error) {
-	url := fmt.Sprintf("%s/v1/payment_methods", c.BaseURL)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
+	var methodsResp PaymentMethodsResponse
+	if err := c.doJSONRequest(ctx, "GET", "/v1/payment_methods", nil, &methodsResp); err != nil {
+		return nil, err
 	}
+	return &methodsResp, nil
+}
 
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+// InstallmentOption - representa as opções de parcelamento de um meio de pagamento para um BIN
+type InstallmentOption struct {
+	PaymentMethodID string      `json:"payment_method_id"`
+	PaymentTypeID   string      `json:"payment_type_id"`
+	Thumbnail       string      `json:"thumbnail,omitempty"`
+	Issuer          Issuer      `json:"issuer"`
+	PayerCosts      []PayerCost `json:"payer_costs"`
+}
 
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
+// Issuer - representa o banco emissor do cartão
+type Issuer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
+// PayerCost - representa um plano de parcelamento
+type PayerCost struct {
+	Installments       int      `json:"installments"`
+	InstallmentRate    float64  `json:"installment_rate"`
+	DiscountRate       float64  `json:"discount_rate"`
+	MinAllowedAmount   float64  `json:"min_allowed_amount"`
+	MaxAllowedAmount   float64  `json:"max_allowed_amount"`
+	RecommendedMessage string   `json:"recommended_message"`
+	InstallmentAmount  float64  `json:"installment_amount"`
+	TotalAmount        float64  `json:"total_amount"`
+	Labels             []string `json:"labels,omitempty"`
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
+// GetInstallments - consulta os planos de parcelamento disponíveis para um valor e BIN
+func (c *Client) GetInstallments(ctx context.Context, amount float64, bin string) ([]InstallmentOption, error) {
+	query := url.Values{}
+	query.Set("amount", strconv.FormatFloat(amount, 'f', 2, 64))
+	query.Set("bin", bin)
 
-	var methodsResp PaymentMethodsResponse
-	if err := json.Unmarshal(body, &methodsResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	var options []InstallmentOption
+	if err := c.doJSONRequest(ctx, "GET", "/v1/payment_methods/installments?"+query.Encode(), nil, &options); err != nil {
+		return nil, err
 	}
-
-	return &methodsResp, nil
+	return options, nil
 }
 
 // generateIdempotencyKey - gera uma chave de idempotência baseada nos dados da requisição
 func generateIdempotencyKey(req *CreditCardPaymentRequest) string {
-	data := fmt.Sprintf("%s-%f-%d-%s", req.ExternalReference, req.TransactionAmount, req.Installments, req.Token[:8])
+	data := fmt.Sprintf("%s-%f-%d-%s", req.ExternalReference, req.TransactionAmount, req.Installments, req.Token)
 	hash := md5.Sum([]byte(data))
 	return fmt.Sprintf("%x", hash)
 }
@@ -1047,196 +723,81 @@ type CustomerCardUpdateRequest struct {
 
 // CreateCustomerCard - cria um cartão para um cliente
 func (c *Client) CreateCustomerCard(ctx context.Context, customerID string, req *CustomerCardRequest) (*CustomerCardResponse, error) {
-	url := fmt.Sprintf("%s/v1/customers/%s/cards", c.BaseURL, customerID)
-
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar cartão", err, http.StatusInternalServerError)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
 	var cardResp CustomerCardResponse
-	if err := json.Unmarshal(body, &cardResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "POST", fmt.Sprintf("/v1/customers/%s/cards", customerID), req, &cardResp); err != nil {
+		return nil, err
 	}
-
 	return &cardResp, nil
 }
 
 // ListCustomerCards - lista os cartões de um cliente
 func (c *Client) ListCustomerCards(ctx context.Context, customerID string) ([]CustomerCardResponse, error) {
-	url := fmt.Sprintf("%s/v1/customers/%s/cards", c.BaseURL, customerID)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
+	var cards []CustomerCardResponse
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/customers/%s/cards", customerID), nil, &cards); err != nil {
+		return nil, err
 	}
-
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
-	var cardsResp []CustomerCardResponse
-	if err := json.Unmarshal(body, &cardsResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
-	}
-
-	return cardsResp, nil
+	return cards, nil
 }
 
 // GetCustomerCard - obtém um cartão específico de um cliente
 func (c *Client) GetCustomerCard(ctx context.Context, customerID, cardID string) (*CustomerCardResponse, error) {
-	url := fmt.Sprintf("%s/v1/customers/%s/cards/%s", c.BaseURL, customerID, cardID)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
-	}
-
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
-	}
-
 	var cardResp CustomerCardResponse
-	if err := json.Unmarshal(body, &cardResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/customers/%s/cards/%s", customerID, cardID), nil, &cardResp); err != nil {
+		return nil, err
 	}
-
 	return &cardResp, nil
 }
 
 // UpdateCustomerCard - atualiza um cartão de um cliente
 func (c *Client) UpdateCustomerCard(ctx context.Context, customerID, cardID string, req *CustomerCardUpdateRequest) (*CustomerCardResponse, error) {
-	url := fmt.Sprintf("%s/v1/customers/%s/cards/%s", c.BaseURL, customerID, cardID)
-
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, util.WrapError("erro ao serializar dados de atualização", err, http.StatusInternalServerError)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
+	var cardResp CustomerCardResponse
+	if err := c.doJSONRequest(ctx, "PUT", fmt.Sprintf("/v1/customers/%s/cards/%s", customerID, cardID), req, &cardResp); err != nil {
+		return nil, err
 	}
+	return &cardResp, nil
+}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+// DeleteCustomerCard - exclui um cartão de um cliente
+func (c *Client) DeleteCustomerCard(ctx context.Context, customerID, cardID string) error {
+	return c.doJSONRequest(ctx, "DELETE", fmt.Sprintf("/v1/customers/%s/cards/%s", customerID, cardID), nil, nil)
+}
 
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
-	}
-	defer resp.Body.Close()
+// doJSONRequest - executa uma requisição JSON na API do Mercado Pago e deserializa a resposta em out;
+// POSTs recebem uma chave de idempotência e, assim como as demais operações idempotentes, podem ser repetidos
+func (c *Client) doJSONRequest(ctx context.Context, method, path string, payload, out interface{}) error {
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, util.WrapError("erro ao ler resposta", err, http.StatusInternalServerError)
+	req := &apiRequest{
+		method: method,
+		path:   path,
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return util.WrapError("erro ao serializar requisição", err, http.StatusInternalServerError)
 		}
-		return nil, util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
+		req.body = jsonData
+		req.contentType = "application/json"
 	}
 
-	var cardResp CustomerCardResponse
-	if err := json.Unmarshal(body, &cardResp); err != nil {
-		return nil, util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
+	if method == http.MethodPost {
+		req.idempotencyKey = idempotencyKeyFrom(ctx)
+		if req.idempotencyKey == "" {
+			req.idempotencyKey = newIdempotencyKey()
+		}
 	}
 
-	return &cardResp, nil
-}
-
-// DeleteCustomerCard - exclui um cartão de um cliente
-func (c *Client) DeleteCustomerCard(ctx context.Context, customerID, cardID string) error {
-	url := fmt.Sprintf("%s/v1/customers/%s/cards/%s", c.BaseURL, customerID, cardID)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	body, err := c.do(ctx, req)
 	if err != nil {
-		return util.WrapError("erro ao criar requisição", err, http.StatusInternalServerError)
+		return err
 	}
 
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return util.WrapError("erro ao executar requisição", err, http.StatusInternalServerError)
+	if out == nil || len(body) == 0 {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d)", resp.StatusCode), err, resp.StatusCode)
-		}
-
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return util.WrapError(fmt.Sprintf("erro na API do Mercado Pago (status %d): %s", resp.StatusCode, string(body)), err, resp.StatusCode)
-		}
-		return util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", errorResp.Message), nil, resp.StatusCode)
+	if err := json.Unmarshal(body, out); err != nil {
+		return util.WrapError("erro ao deserializar resposta", err, http.StatusInternalServerError)
 	}
 
 	return nil
@@ -0,0 +1,76 @@
+package mercadopago
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// CustomerRequest - representa a estrutura para criar ou atualizar um customer
+type CustomerRequest struct {
+	Email          string                  `json:"email,omitempty"`
+	FirstName      string                  `json:"first_name,omitempty"`
+	LastName       string                  `json:"last_name,omitempty"`
+	Phone          *Phone                  `json:"phone,omitempty"`
+	Identification *CustomerIdentification `json:"identification,omitempty"`
+	DefaultCard    string                  `json:"default_card,omitempty"`
+	Description    string                  `json:"description,omitempty"`
+}
+
+// CustomerResponse - representa um customer do Mercado Pago
+type CustomerResponse struct {
+	ID              string                 `json:"id"`
+	Email           string                 `json:"email"`
+	FirstName       string                 `json:"first_name"`
+	LastName        string                 `json:"last_name"`
+	Identification  CustomerIdentification `json:"identification"`
+	DefaultCard     string                 `json:"default_card"`
+	Description     string                 `json:"description"`
+	Cards           []CustomerCardResponse `json:"cards"`
+	DateCreated     string                 `json:"date_created"`
+	DateLastUpdated string                 `json:"date_last_updated"`
+}
+
+// CustomerSearchResponse - representa o resultado da busca de customers
+type CustomerSearchResponse struct {
+	Results []CustomerResponse `json:"results"`
+}
+
+// CreateCustomer - cria um customer no Mercado Pago
+func (c *Client) CreateCustomer(ctx context.Context, req *CustomerRequest) (*CustomerResponse, error) {
+	var customer CustomerResponse
+	if err := c.doJSONRequest(ctx, "POST", "/v1/customers", req, &customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// GetCustomer - obtém um customer pelo ID
+func (c *Client) GetCustomer(ctx context.Context, customerID string) (*CustomerResponse, error) {
+	var customer CustomerResponse
+	if err := c.doJSONRequest(ctx, "GET", fmt.Sprintf("/v1/customers/%s", customerID), nil, &customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// UpdateCustomer - atualiza um customer, inclusive o cartão padrão
+func (c *Client) UpdateCustomer(ctx context.Context, customerID string, req *CustomerRequest) (*CustomerResponse, error) {
+	var customer CustomerResponse
+	if err := c.doJSONRequest(ctx, "PUT", fmt.Sprintf("/v1/customers/%s", customerID), req, &customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// SearchCustomerByEmail - busca o customer cadastrado com o email informado, retornando nil quando não existe
+func (c *Client) SearchCustomerByEmail(ctx context.Context, email string) (*CustomerResponse, error) {
+	var search CustomerSearchResponse
+	if err := c.doJSONRequest(ctx, "GET", "/v1/customers/search?email="+url.QueryEscape(email), nil, &search); err != nil {
+		return nil, err
+	}
+	if len(search.Results) == 0 {
+		return nil, nil
+	}
+	return &search.Results[0], nil
+}
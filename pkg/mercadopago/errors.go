@@ -0,0 +1,181 @@
+package mercadopago
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorKind - categoria de uma falha na comunicação com o Mercado Pago
+type ErrorKind string
+
+// Categorias de falha retornadas pelo cliente
+const (
+	ErrorKindDeclined     ErrorKind = "declined"
+	ErrorKindInvalid      ErrorKind = "invalid_request"
+	ErrorKindUnauthorized ErrorKind = "unauthorized"
+	ErrorKindNotFound     ErrorKind = "not_found"
+	ErrorKindConflict     ErrorKind = "conflict"
+	ErrorKindRateLimited  ErrorKind = "rate_limited"
+	ErrorKindUnavailable  ErrorKind = "unavailable"
+)
+
+// Erros sentinela para uso com errors.Is
+var (
+	ErrDeclined       = errors.New("mercadopago: pagamento recusado")
+	ErrInvalidRequest = errors.New("mercadopago: requisição inválida")
+	ErrUnauthorized   = errors.New("mercadopago: credenciais inválidas")
+	ErrNotFound       = errors.New("mercadopago: recurso não encontrado")
+	ErrConflict       = errors.New("mercadopago: conflito")
+	ErrRateLimited    = errors.New("mercadopago: limite de requisições excedido")
+	ErrUnavailable    = errors.New("mercadopago: serviço indisponível")
+	ErrCircuitOpen    = errors.New("mercadopago: circuit breaker aberto")
+)
+
+// ErrorCause - representa uma causa detalhada de erro da API
+type ErrorCause struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// ErrorResponse - representa uma resposta de erro da API
+type ErrorResponse struct {
+	Message string       `json:"message"`
+	Error   string       `json:"error"`
+	Status  int          `json:"status"`
+	Cause   []ErrorCause `json:"cause,omitempty"`
+}
+
+// APIError - erro tipado de uma chamada ao Mercado Pago; permite distinguir recusa do emissor de indisponibilidade do gateway
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	Message    string
+	Code       string
+	Causes     []ErrorCause
+	Err        error
+}
+
+// Error - implementa a interface error
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("mercadopago: %s (%s): %v", e.Message, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("mercadopago: %s (%s, status %d)", e.Message, e.Kind, e.StatusCode)
+}
+
+// Unwrap - expõe a falha de transporte original
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is - compara o erro com os erros sentinela da categoria
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrDeclined:
+		return e.Kind == ErrorKindDeclined
+	case ErrInvalidRequest:
+		return e.Kind == ErrorKindInvalid
+	case ErrUnauthorized:
+		return e.Kind == ErrorKindUnauthorized
+	case ErrNotFound:
+		return e.Kind == ErrorKindNotFound
+	case ErrConflict:
+		return e.Kind == ErrorKindConflict
+	case ErrRateLimited:
+		return e.Kind == ErrorKindRateLimited
+	case ErrUnavailable:
+		return e.Kind == ErrorKindUnavailable
+	}
+	return false
+}
+
+// Temporary - indica se a falha é transitória e a requisição pode ser repetida
+func (e *APIError) Temporary() bool {
+	return e.Kind == ErrorKindUnavailable || e.Kind == ErrorKindRateLimited
+}
+
+// HTTPStatus - status HTTP que a API deve devolver ao usuário para esta falha
+func (e *APIError) HTTPStatus() int {
+	switch e.Kind {
+	case ErrorKindDeclined:
+		return http.StatusPaymentRequired
+	case ErrorKindUnavailable:
+		return http.StatusServiceUnavailable
+	case ErrorKindRateLimited:
+		return http.StatusTooManyRequests
+	}
+	if e.StatusCode >= 400 && e.StatusCode < 500 {
+		return e.StatusCode
+	}
+	return http.StatusBadGateway
+}
+
+// newAPIError - monta o erro tipado a partir do status e do corpo da resposta de erro
+func newAPIError(statusCode int, body []byte) *APIError {
+
+	apiErr := &APIError{
+		Kind:       kindForStatus(statusCode),
+		StatusCode: statusCode,
+	}
+
+	var errorResp ErrorResponse
+	if err := json.Unmarshal(body, &errorResp); err != nil || errorResp.Message == "" {
+		apiErr.Message = fmt.Sprintf("status %d: %s", statusCode, strings.TrimSpace(string(body)))
+		return apiErr
+	}
+
+	apiErr.Message = errorResp.Message
+	apiErr.Code = errorResp.Error
+	apiErr.Causes = errorResp.Cause
+
+	if apiErr.Kind == ErrorKindInvalid && isDeclined(&errorResp) {
+		apiErr.Kind = ErrorKindDeclined
+	}
+
+	return apiErr
+}
+
+// newTransportError - monta o erro tipado de uma falha de rede, timeout ou circuito aberto
+func newTransportError(err error) *APIError {
+	return &APIError{
+		Kind:    ErrorKindUnavailable,
+		Message: "falha de comunicação",
+		Err:     err,
+	}
+}
+
+// kindForStatus - classifica o status HTTP da resposta
+func kindForStatus(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusPaymentRequired:
+		return ErrorKindDeclined
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorKindUnauthorized
+	case statusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case statusCode == http.StatusConflict:
+		return ErrorKindConflict
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimited
+	case statusCode >= 500:
+		return ErrorKindUnavailable
+	default:
+		return ErrorKindInvalid
+	}
+}
+
+// isDeclined - identifica recusas do emissor devolvidas como erro de validação
+func isDeclined(errorResp *ErrorResponse) bool {
+	if strings.Contains(errorResp.Error, "rejected") {
+		return true
+	}
+	for _, cause := range errorResp.Cause {
+		if strings.HasPrefix(cause.Code, "cc_rejected") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package mercadopago
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jampa_trip/pkg/util"
+)
+
+// Valores padrão de resiliência do cliente
+const (
+	DefaultTimeout          = 30 * time.Second
+	DefaultMaxRetries       = 2
+	DefaultRetryBaseDelay   = 200 * time.Millisecond
+	DefaultRetryMaxDelay    = 2 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// Options - parâmetros de timeout, retentativas e circuit breaker do cliente
+type Options struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultOptions - retorna as opções padrão de resiliência
+func DefaultOptions() Options {
+	return Options{
+		Timeout:          DefaultTimeout,
+		MaxRetries:       DefaultMaxRetries,
+		RetryBaseDelay:   DefaultRetryBaseDelay,
+		RetryMaxDelay:    DefaultRetryMaxDelay,
+		BreakerThreshold: DefaultBreakerThreshold,
+		BreakerCooldown:  DefaultBreakerCooldown,
+	}
+}
+
+type idempotencyKeyContext struct{}
+
+// WithIdempotencyKey - define a chave de idempotência usada pelos POSTs executados com o contexto
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+// idempotencyKeyFrom - retorna a chave de idempotência do contexto, se houver
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContext{}).(string)
+	return key
+}
+
+// newIdempotencyKey - gera uma chave de idempotência aleatória no formato UUID v4
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+// apiRequest - representa uma requisição à API; o corpo é mantido em memória para ser reenviado nas retentativas
+type apiRequest struct {
+	method         string
+	path           string
+	contentType    string
+	body           []byte
+	idempotencyKey string
+}
+
+// idempotent - indica se a requisição pode ser repetida sem efeitos duplicados
+func (r *apiRequest) idempotent() bool {
+	return r.method != http.MethodPost || r.idempotencyKey != ""
+}
+
+// do - executa a requisição respeitando o circuit breaker e repetindo falhas transitórias com backoff exponencial e jitter
+func (c *Client) do(ctx context.Context, req *apiRequest) ([]byte, error) {
+
+	for attempt := 0; ; attempt++ {
+
+		if err := c.Breaker.Allow(time.Now()); err != nil {
+			return nil, wrapAPIError(newTransportError(err))
+		}
+
+		body, retryAfter, apiErr := c.attempt(ctx, req)
+		if apiErr == nil {
+			c.Breaker.Success()
+			return body, nil
+		}
+
+		if ctx.Err() != nil {
+			c.Breaker.release()
+			return nil, wrapAPIError(apiErr)
+		}
+
+		// respostas 4xx mostram que o gateway está no ar
+		if apiErr.Kind == ErrorKindUnavailable {
+			c.Breaker.Failure(time.Now())
+		} else {
+			c.Breaker.Success()
+		}
+
+		if !req.idempotent() || !apiErr.Temporary() || attempt >= c.Options.MaxRetries {
+			return nil, wrapAPIError(apiErr)
+		}
+
+		timer := time.NewTimer(c.backoff(attempt, retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, wrapAPIError(apiErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt - executa uma única tentativa da requisição
+func (c *Client) attempt(ctx context.Context, req *apiRequest) ([]byte, time.Duration, *APIError) {
+
+	var reqBody io.Reader
+	if req.body != nil {
+		reqBody = bytes.NewReader(req.body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, c.BaseURL+req.path, reqBody)
+	if err != nil {
+		return nil, 0, &APIError{Kind: ErrorKindInvalid, Message: "erro ao criar requisição", Err: err}
+	}
+
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if req.idempotencyKey != "" {
+		httpReq.Header.Set("X-Idempotency-Key", req.idempotencyKey)
+	}
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, newTransportError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, newTransportError(err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), newAPIError(resp.StatusCode, body)
+	}
+
+	return body, 0, nil
+}
+
+// backoff - calcula a espera antes da próxima tentativa: metade fixa e metade aleatória do teto exponencial,
+// respeitando o Retry-After informado pelo Mercado Pago
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+
+	ceiling := c.Options.RetryBaseDelay << attempt
+	if ceiling <= 0 || ceiling > c.Options.RetryMaxDelay {
+		ceiling = c.Options.RetryMaxDelay
+	}
+
+	delay := ceiling / 2
+	if half := int64(ceiling / 2); half > 0 {
+		delay += time.Duration(mathrand.Int64N(half + 1))
+	}
+
+	if retryAfter > delay {
+		delay = min(retryAfter, c.Options.RetryMaxDelay)
+	}
+
+	return delay
+}
+
+// parseRetryAfter - converte o cabeçalho Retry-After em segundos
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// wrapAPIError - converte o erro tipado no erro da aplicação, preservando-o para errors.Is e errors.As
+func wrapAPIError(apiErr *APIError) error {
+	if apiErr.Kind == ErrorKindUnavailable {
+		return util.WrapError("Mercado Pago indisponível no momento, tente novamente em instantes", apiErr, apiErr.HTTPStatus())
+	}
+	return util.WrapError(fmt.Sprintf("erro na API do Mercado Pago: %s", apiErr.Message), apiErr, apiErr.HTTPStatus())
+}
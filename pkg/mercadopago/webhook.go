@@ -0,0 +1,50 @@
+package mercadopago
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jampa_trip/pkg/util"
+)
+
+// ValidateWebhookSignature - valida o cabeçalho x-signature de uma notificação do Mercado Pago
+func ValidateWebhookSignature(secret, xSignature, xRequestID, dataID string) error {
+
+	var ts, v1 string
+	for _, part := range strings.Split(xSignature, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "ts":
+			ts = value
+		case "v1":
+			v1 = value
+		}
+	}
+
+	if ts == "" || v1 == "" {
+		return util.WrapError("assinatura do webhook ausente ou mal formatada", nil, http.StatusUnauthorized)
+	}
+
+	expected := SignWebhook(secret, xRequestID, dataID, ts)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(v1))) {
+		return util.WrapError("assinatura do webhook inválida", nil, http.StatusUnauthorized)
+	}
+
+	return nil
+}
+
+// SignWebhook - calcula a assinatura HMAC-SHA256 do manifesto de uma notificação
+func SignWebhook(secret, xRequestID, dataID, ts string) string {
+	manifest := fmt.Sprintf("id:%s;request-id:%s;ts:%s;", strings.ToLower(dataID), xRequestID, ts)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
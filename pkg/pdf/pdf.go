@@ -0,0 +1,201 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dimensões de uma página A4 em pontos
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font - fonte padrão do PDF usada no texto; as fontes base dispensam embutir arquivos
+type Font string
+
+// Fontes disponíveis
+const (
+	Helvetica     Font = "F1"
+	HelveticaBold Font = "F2"
+)
+
+// Document - representa um documento PDF simples com texto e linhas, gerado sem dependências externas
+type Document struct {
+	Title     string
+	Author    string
+	CreatedAt time.Time
+	pages     []*Page
+}
+
+// Page - representa uma página do documento; as coordenadas partem do canto superior esquerdo
+type Page struct {
+	content bytes.Buffer
+}
+
+// New - cria um novo documento
+func New(title string) *Document {
+	return &Document{
+		Title:     title,
+		CreatedAt: time.Now(),
+	}
+}
+
+// AddPage - adiciona uma página A4 ao documento
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Text - escreve um texto com a linha de base em (x, y)
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, number(size), number(x), number(PageHeight-y), escape(encodeWinAnsi(text)))
+}
+
+// Line - desenha uma linha de (x1, y1) a (x2, y2)
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// FillRect - preenche um retângulo em tons de cinza (0 preto, 1 branco) com o canto superior esquerdo em (x, y)
+func (p *Page) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "q %s g %s %s %s %s re f Q\n",
+		number(gray), number(x), number(PageHeight-y-height), number(width), number(height))
+}
+
+// Bytes - serializa o documento no formato PDF 1.4
+func (d *Document) Bytes() ([]byte, error) {
+
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// objetos fixos: 1 catálogo, 2 árvore de páginas, 3 e 4 fontes, 5 informações
+	firstPage := 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+
+	w.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	w.object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	w.object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	w.object(5, fmt.Sprintf("<< /Title (%s) /Author (%s) /Producer (Jampa Trip) /CreationDate (D:%s) >>",
+		escape(encodeWinAnsi(d.Title)), escape(encodeWinAnsi(d.Author)), d.CreatedAt.UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		pageID := firstPage + i*2
+		contentID := pageID + 1
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		w.object(pageID, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(PageWidth), number(PageHeight), contentID))
+		w.stream(contentID, compressed.Bytes())
+	}
+
+	w.trailer()
+
+	return w.buf.Bytes(), nil
+}
+
+// writer - acumula os objetos do PDF registrando a posição de cada um para a tabela xref
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// object - escreve um objeto indireto
+func (w *writer) object(id int, body string) {
+	w.mark(id)
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// stream - escreve um objeto de conteúdo compactado
+func (w *writer) stream(id int, data []byte) {
+	w.mark(id)
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", id, len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// mark - registra a posição do objeto
+func (w *writer) mark(id int) {
+	for len(w.offsets) < id {
+		w.offsets = append(w.offsets, 0)
+	}
+	w.offsets[id-1] = w.buf.Len()
+}
+
+// trailer - escreve a tabela xref e o trailer do arquivo
+func (w *writer) trailer() {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+}
+
+// number - formata um número sem zeros desnecessários
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// winAnsiExtras - caracteres do Windows-1252 fora do intervalo Latin-1
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeWinAnsi - converte o texto UTF-8 para a codificação das fontes padrão; caracteres sem equivalente viram '?'
+func encodeWinAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			encoded = append(encoded, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			encoded = append(encoded, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				encoded = append(encoded, b)
+			} else {
+				encoded = append(encoded, '?')
+			}
+		}
+	}
+	return encoded
+}
+
+// escape - escapa os caracteres reservados de uma string literal do PDF
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		if c == '\\' || c == '(' || c == ')' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// ErrInvalidComponents - quantidade de componentes fora do intervalo aceito pelo BlurHash (1 a 9 em cada eixo)
+var ErrInvalidComponents = errors.New("quantidade de componentes do BlurHash deve estar entre 1 e 9")
+
+// ErrEmptyImage - imagem sem pixels
+var ErrEmptyImage = errors.New("imagem sem pixels")
+
+// base83Chars - alfabeto da codificação base 83 usada pelo BlurHash
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// BlurHash - codifica a imagem no formato BlurHash (https://blurha.sh) com xComponents x yComponents componentes.
+// O custo é proporcional ao número de pixels vezes o de componentes, por isso a imagem deve ser reduzida antes.
+func BlurHash(img image.Image, xComponents, yComponents int) (string, error) {
+
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", ErrInvalidComponents
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", ErrEmptyImage
+	}
+
+	// componentes de cor de cada pixel já convertidos para o espaço linear
+	linear := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			linear[y*width+x] = [3]float64{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			factors = append(factors, basisFactor(linear, width, height, i, j))
+		}
+	}
+
+	var hash strings.Builder
+	hash.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	dc, ac := factors[0], factors[1:]
+
+	maximum := 1.0
+	if len(ac) > 0 {
+		actualMax := 0.0
+		for _, factor := range ac {
+			for _, value := range factor {
+				actualMax = math.Max(actualMax, math.Abs(value))
+			}
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maximum = float64(quantisedMax+1) / 166
+		hash.WriteString(encode83(quantisedMax, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	hash.WriteString(encode83(encodeDC(dc), 4))
+	for _, factor := range ac {
+		hash.WriteString(encode83(encodeAC(factor, maximum), 2))
+	}
+
+	return hash.String(), nil
+}
+
+// basisFactor - média dos pixels ponderada pela função cosseno do componente (i, j)
+func basisFactor(linear [][3]float64, width, height, i, j int) [3]float64 {
+	normalisation := 2.0
+	if i == 0 && j == 0 {
+		normalisation = 1
+	}
+
+	var factor [3]float64
+	for y := 0; y < height; y++ {
+		cosY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+		for x := 0; x < width; x++ {
+			basis := normalisation * math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) * cosY
+			pixel := linear[y*width+x]
+			factor[0] += basis * pixel[0]
+			factor[1] += basis * pixel[1]
+			factor[2] += basis * pixel[2]
+		}
+	}
+
+	scale := 1 / float64(width*height)
+	return [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale}
+}
+
+// encodeDC - cor média da imagem no formato RGB de 24 bits
+func encodeDC(value [3]float64) int {
+	return linearToSRGB(value[0])<<16 + linearToSRGB(value[1])<<8 + linearToSRGB(value[2])
+}
+
+// encodeAC - componente de detalhe quantizado em 19 níveis por canal, relativo ao maior componente
+func encodeAC(value [3]float64, maximum float64) int {
+	quantise := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximum, 0.5)*9+9.5))))
+	}
+	return quantise(value[0])*19*19 + quantise(value[1])*19 + quantise(value[2])
+}
+
+// encode83 - representa o valor com a quantidade de dígitos informada na base 83
+func encode83(value, length int) string {
+	encoded := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		encoded[i] = base83Chars[value%83]
+		value /= 83
+	}
+	return string(encoded)
+}
+
+// srgbToLinear - converte um canal sRGB de 8 bits para o espaço linear
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB - converte um canal do espaço linear para sRGB de 8 bits
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow - potência que preserva o sinal da base
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,56 @@
+package placeholder
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// dominantColorBits - bits mantidos de cada canal ao agrupar cores parecidas
+const dominantColorBits = 4
+
+// DominantColor - cor predominante da imagem em hexadecimal (#rrggbb). As cores são agrupadas em faixas e o
+// resultado é a média dos pixels da faixa mais frequente, ignorando os pixels quase transparentes. Retorna vazio
+// quando a imagem não tem pixels visíveis. Como a BlurHash, deve ser calculada sobre uma cópia reduzida.
+func DominantColor(img image.Image) string {
+
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+
+	shift := 8 - dominantColorBits
+	buckets := make(map[int]*bucket)
+	var best *bucket
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+
+			key := int(c.R>>shift)<<(2*dominantColorBits) | int(c.G>>shift)<<dominantColorBits | int(c.B>>shift)
+			b := buckets[key]
+			if b == nil {
+				b = &bucket{}
+				buckets[key] = b
+			}
+			b.count++
+			b.r += int(c.R)
+			b.g += int(c.G)
+			b.b += int(c.B)
+
+			if best == nil || b.count > best.count {
+				best = b
+			}
+		}
+	}
+
+	if best == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.count, best.g/best.count, best.b/best.count)
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Job - representa uma tarefa executada periodicamente
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler - objeto de contexto
+type Scheduler struct {
+	Redis *redis.Client
+	jobs  []Job
+}
+
+// SchedulerNew - construtor do objeto
+func SchedulerNew(redisClient *redis.Client) *Scheduler {
+	return &Scheduler{
+		Redis: redisClient,
+	}
+}
+
+// Register - registra uma tarefa no agendador
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start - inicia as tarefas registradas, cada uma em sua goroutine, até o contexto ser cancelado
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			log.Printf("tarefa %s desabilitada (intervalo %s)", job.Name, job.Interval)
+			continue
+		}
+		go s.loop(ctx, job)
+	}
+}
+
+// loop - executa a tarefa a cada intervalo
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.acquireLock(ctx, job) {
+				continue
+			}
+			if err := job.Run(ctx); err != nil {
+				log.Printf("erro ao executar tarefa %s: %s", job.Name, err.Error())
+			}
+		}
+	}
+}
+
+// acquireLock - garante que apenas uma réplica execute a tarefa por intervalo
+func (s *Scheduler) acquireLock(ctx context.Context, job Job) bool {
+	if s.Redis == nil {
+		return true
+	}
+
+	hostname, _ := os.Hostname()
+	key := fmt.Sprintf("lock:job:%s", job.Name)
+
+	// expira um pouco antes do próximo disparo para não bloquear a execução seguinte
+	acquired, err := s.Redis.SetNX(ctx, key, hostname, job.Interval*9/10).Result()
+	if err != nil {
+		log.Printf("erro ao obter lock da tarefa %s: %s", job.Name, err.Error())
+		return false
+	}
+
+	return acquired
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore - armazena os objetos em um diretório do disco local; indicado para desenvolvimento e instância única
+type LocalStore struct {
+	Dir string
+}
+
+// NewLocalStore - cria o armazenamento local no diretório informado
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{
+		Dir: dir,
+	}
+}
+
+// Put - grava o arquivo, criando os diretórios intermediários
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// grava em arquivo temporário e renomeia para que leituras concorrentes nunca vejam o arquivo pela metade
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get - lê o arquivo
+func (s *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return data, err
+}
+
+// Delete - remove o arquivo
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// List - percorre o diretório base e retorna os arquivos cujas chaves começam com o prefixo,
+// ignorando os temporários de gravações em andamento
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]Object, error) {
+
+	// percorre apenas o diretório que contém o prefixo
+	root := s.Dir
+	if i := strings.LastIndex(prefix, "/"); i > 0 {
+		root = filepath.Join(s.Dir, filepath.FromSlash(prefix[:i]))
+	}
+
+	objects := []Object{}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.Dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		objects = append(objects, Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// path - converte a chave no caminho do arquivo dentro do diretório base
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
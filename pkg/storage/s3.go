@@ -0,0 +1,309 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store - armazena os objetos em um bucket compatível com S3 (AWS S3, MinIO), assinando as requisições com AWS Signature V4
+type S3Store struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool
+	HTTPClient      *http.Client
+}
+
+// S3Options - parâmetros de conexão com o bucket
+type S3Options struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool
+	Timeout         time.Duration
+}
+
+// s3ListResult - corpo XML da listagem de objetos (ListObjectsV2)
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// s3Error - corpo XML de erro retornado pelo S3
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// NewS3Store - cria o armazenamento S3
+func NewS3Store(opts S3Options) *S3Store {
+
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	return &S3Store{
+		Endpoint:        strings.TrimSuffix(opts.Endpoint, "/"),
+		Region:          opts.Region,
+		Bucket:          opts.Bucket,
+		AccessKeyID:     opts.AccessKeyID,
+		SecretAccessKey: opts.SecretAccessKey,
+		UsePathStyle:    opts.UsePathStyle,
+		HTTPClient:      &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+// Put - envia o objeto para o bucket
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+
+	headers := http.Header{}
+	if contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
+
+	_, err := s.request(ctx, http.MethodPut, key, data, headers)
+	return err
+}
+
+// Get - baixa o objeto do bucket
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.request(ctx, http.MethodGet, key, nil, nil)
+}
+
+// Delete - remove o objeto do bucket
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.request(ctx, http.MethodDelete, key, nil, nil)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// List - lista os objetos do bucket com o prefixo, seguindo a paginação da API
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+
+	objects := []Object{}
+	token := ""
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		data, err := s.send(ctx, http.MethodGet, s.bucketURL()+"/?"+canonicalQuery(query), nil, nil, "list "+prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		var result s3ListResult
+		if err := xml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("erro ao ler listagem do armazenamento S3: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, Object{Key: content.Key, Size: content.Size, LastModified: content.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// bucketURL - endereço do bucket no estilo de caminho (MinIO) ou de host virtual (AWS)
+func (s *S3Store) bucketURL() string {
+
+	if s.UsePathStyle {
+		return s.Endpoint + "/" + s.Bucket
+	}
+
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return s.Endpoint + "/" + s.Bucket
+	}
+
+	endpoint.Host = s.Bucket + "." + endpoint.Host
+	return strings.TrimSuffix(endpoint.String(), "/")
+}
+
+// request - executa a requisição assinada e converte as respostas de erro
+func (s *S3Store) request(ctx context.Context, method, key string, body []byte, headers http.Header) ([]byte, error) {
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	return s.send(ctx, method, s.bucketURL()+"/"+EscapeKey(key), body, headers, key)
+}
+
+// send - assina e envia a requisição para o endereço informado; target identifica a operação nas mensagens de erro
+func (s *S3Store) send(ctx context.Context, method, rawURL string, body []byte, headers http.Header, target string) ([]byte, error) {
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	if body == nil {
+		req.Body = http.NoBody
+	}
+
+	s.Sign(req, body, time.Now())
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar o armazenamento S3: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler resposta do armazenamento S3: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var s3Err s3Error
+		if xml.Unmarshal(data, &s3Err) == nil && s3Err.Code != "" {
+			return nil, fmt.Errorf("erro no armazenamento S3 (%s %s): %s - %s", method, target, s3Err.Code, s3Err.Message)
+		}
+		return nil, fmt.Errorf("erro no armazenamento S3 (%s %s): status %d", method, target, resp.StatusCode)
+	}
+
+	return data, nil
+}
+
+// Sign - assina a requisição com AWS Signature V4, incluindo todos os cabeçalhos já definidos
+func (s *S3Store) Sign(req *http.Request, body []byte, now time.Time) {
+
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "authorization" || lower == "user-agent" {
+			continue
+		}
+		headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", day, s.Region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), day)
+	signingKey = hmacSHA256(signingKey, s.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery - query string ordenada e codificada conforme a especificação da assinatura
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, value := range vals {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode - codificação RFC 3986 exigida pela assinatura (espaço vira %20, não '+')
+func uriEncode(value string) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrNotFound - o objeto não existe no armazenamento
+var ErrNotFound = errors.New("objeto não encontrado no armazenamento")
+
+// BlobStore - armazenamento de arquivos por chave (ex.: images/1/foto.jpg), independente do backend
+type BlobStore interface {
+	// Put - grava o conteúdo na chave, substituindo o objeto existente
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get - lê o conteúdo da chave; retorna ErrNotFound quando o objeto não existe
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete - remove o objeto; remover uma chave inexistente não é erro
+	Delete(ctx context.Context, key string) error
+	// List - lista os objetos cujas chaves começam com o prefixo informado
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Object - objeto armazenado, retornado pela listagem
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ValidateKey - indica se a chave é aceita pelos armazenamentos
+func ValidateKey(key string) error {
+	return validateKey(key)
+}
+
+// EscapeKey - codifica cada segmento da chave para uso em URLs, preservando as barras
+func EscapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = uriEncode(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// validateKey - rejeita chaves vazias, absolutas ou que escapem do diretório base
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return errors.New("chave de armazenamento inválida")
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return errors.New("chave de armazenamento inválida")
+		}
+	}
+	return nil
+}
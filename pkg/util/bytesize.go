@@ -0,0 +1,64 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// byteUnits - multiplicadores dos sufixos aceitos, em potências de 1024
+var byteUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize - converte tamanhos como "500MB", "1.5GB" ou "1048576" em bytes
+func ParseByteSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			multiplier = unit.multiplier
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("tamanho inválido: %q", value)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
+
+// ParseByteSizeOrDefault - converte um tamanho com ParseByteSize, retornando o valor padrão quando vazio ou inválido
+func ParseByteSizeOrDefault(value string, fallback int64) int64 {
+	if value == "" {
+		return fallback
+	}
+
+	size, err := ParseByteSize(value)
+	if err != nil {
+		return fallback
+	}
+
+	return size
+}
+
+// FormatBytes - formata um tamanho em bytes na maior unidade que resulte em pelo menos 1
+func FormatBytes(size int64) string {
+	for _, unit := range byteUnits[:len(byteUnits)-1] {
+		if size >= unit.multiplier {
+			return fmt.Sprintf("%.1f %s", float64(size)/float64(unit.multiplier), unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", size)
+}
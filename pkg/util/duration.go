@@ -0,0 +1,17 @@
+package util
+
+import "time"
+
+// ParseDurationOrDefault - converte uma string no formato do time.ParseDuration, retornando o valor padrão quando vazia ou inválida
+func ParseDurationOrDefault(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+
+	return duration
+}
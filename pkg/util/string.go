@@ -75,6 +75,16 @@ func ValidaSegurancaSenha(senha string) error {
 	return nil
 }
 
+// SomenteDigitos - remove do valor tudo que não for dígito (ex.: máscara de CPF e CEP)
+func SomenteDigitos(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
 // ValidaCPF - valida se o CPF é válido (formato e dígitos verificadores)
 func ValidaCPF(cpf string) error {
 	cpf = strings.ReplaceAll(cpf, ".", "")
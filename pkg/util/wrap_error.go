@@ -28,6 +28,11 @@ func (e *AppError) Error() string {
 	return e.Msg
 }
 
+// Unwrap - expõe o erro original para errors.Is e errors.As
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // MarshalJSON - serializa o erro em JSON
 func (e *AppError) MarshalJSON() ([]byte, error) {
 	type Alias AppError
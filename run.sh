@@ -31,7 +31,39 @@ export REDIS_DB=0
 export MERCADO_PAGO_ACCESS_TOKEN=your_access_token_here
 export MERCADO_PAGO_PUBLIC_KEY=your_public_key_here
 export MERCADO_PAGO_WEBHOOK_SECRET=your_webhook_secret_here
+export MERCADO_PAGO_SELLER_TOKEN_KEY=jampa_trip_seller_token_key
 export MERCADO_PAGO_ENVIRONMENT=sandbox
 export MERCADO_PAGO_BASE_URL=https://api.mercadopago.com
+export MERCADO_PAGO_TIMEOUT=30s
+export MERCADO_PAGO_MAX_RETRIES=2
+export MERCADO_PAGO_BREAKER_THRESHOLD=5
+export MERCADO_PAGO_BREAKER_COOLDOWN=30s
+export PIX_EXPIRATION=30m
+export BOLETO_EXPIRATION=72h
+export PLATFORM_COMMISSION_RATE=10
+
+# Configurações das tarefas agendadas
+export PAYMENT_RECONCILE_INTERVAL=15m
+export PAYMENT_RECONCILE_MIN_AGE=30m
+export PIX_EXPIRATION_INTERVAL=1m
+export IMAGE_GC_INTERVAL=24h
+export IMAGE_GC_MAX_AGE=168h
+
+# Configurações do armazenamento de arquivos
+export STORAGE_BACKEND=local
+export STORAGE_LOCAL_DIR=uploads
+export S3_ENDPOINT=http://localhost:9000
+export S3_REGION=us-east-1
+export S3_BUCKET=jampa-trip
+export S3_ACCESS_KEY_ID=jampa_trip_minio
+export S3_SECRET_ACCESS_KEY=jampa_trip_minio_password
+export S3_USE_PATH_STYLE=true
+export PUBLIC_MEDIA_BASE_URL=http://localhost:1450/jampa-trip/api/v1/media
+export MEDIA_URL_SECRET=jampa_trip_media_url_secret
+export MEDIA_URL_TTL=1h
+export IMAGE_RENDITION_WIDTHS=320,640,1280,1920
+export IMAGE_QUOTA_COMPANY=1GB
+export IMAGE_QUOTA_PLANS=basico=1GB,profissional=10GB,empresarial=50GB
+export IMAGE_WORKERS=4
 
 go run cmd/main.go
\ No newline at end of file
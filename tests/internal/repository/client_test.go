@@ -520,3 +520,47 @@ func TestClientRepository_EmailExisteParaOutroCliente(t *testing.T) {
 		})
 	}
 }
+
+func TestClientRepository_GetMercadoPagoCustomerID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer mock.ExpectationsWereMet()
+
+	repo := repository.ClientRepositoryNew(db)
+
+	tests := []struct {
+		name     string
+		id       int
+		mockID   string
+		expected string
+	}{
+		{
+			name:     "Client with customer",
+			id:       1,
+			mockID:   "123456789-abcdef",
+			expected: "123456789-abcdef",
+		},
+		{
+			name:     "Client without customer",
+			id:       2,
+			mockID:   "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(`SELECT COALESCE\(mercado_pago_customer_id`).
+				WithArgs(tt.id).
+				WillReturnRows(sqlmock.NewRows([]string{"mercado_pago_customer_id"}).AddRow(tt.mockID))
+
+			result, err := repo.GetMercadoPagoCustomerID(tt.id)
+			if err != nil {
+				t.Fatalf("GetMercadoPagoCustomerID() error = %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("GetMercadoPagoCustomerID() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
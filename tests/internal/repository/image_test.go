@@ -131,11 +131,12 @@ func TestImageRepository_GetByID(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 			},
@@ -194,11 +195,12 @@ func TestImageRepository_GetByIDAndUser(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 			},
@@ -370,11 +372,12 @@ func TestImageRepository_List(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/repository"
+)
+
+func TestLedgerRepository_ExistsForPayment(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer mock.ExpectationsWereMet()
+
+	repo := repository.LedgerRepositoryNew(db)
+
+	tests := []struct {
+		name        string
+		pagamentoID int
+		entryType   string
+		count       int
+		expected    bool
+	}{
+		{
+			name:        "Sale already recorded",
+			pagamentoID: 1,
+			entryType:   "sale",
+			count:       1,
+			expected:    true,
+		},
+		{
+			name:        "Refund not recorded",
+			pagamentoID: 1,
+			entryType:   "refund",
+			count:       0,
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "ledger_entries"`).
+				WithArgs(tt.pagamentoID, tt.entryType).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.count))
+
+			exists, err := repo.ExistsForPayment(tt.pagamentoID, tt.entryType)
+			if err != nil {
+				t.Errorf("ExistsForPayment() unexpected error: %v", err)
+			}
+
+			if exists != tt.expected {
+				t.Errorf("ExistsForPayment() = %v, expected %v", exists, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLedgerRepository_PayableBalance(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer mock.ExpectationsWereMet()
+
+	repo := repository.LedgerRepositoryNew(db)
+
+	tests := []struct {
+		name     string
+		balance  float64
+		expected float64
+	}{
+		{
+			name:     "Company with pending balance",
+			balance:  715.50,
+			expected: 715.50,
+		},
+		{
+			name:     "Company without postings",
+			balance:  0,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(`SELECT COALESCE\(SUM\(CASE WHEN direction = \$1 THEN amount ELSE -amount END\), 0\) FROM "ledger_postings"`).
+				WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(tt.balance))
+
+			balance, err := repo.PayableBalance(1, time.Now())
+			if err != nil {
+				t.Errorf("PayableBalance() unexpected error: %v", err)
+			}
+
+			if balance != tt.expected {
+				t.Errorf("PayableBalance() = %v, expected %v", balance, tt.expected)
+			}
+		})
+	}
+}
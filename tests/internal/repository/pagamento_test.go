@@ -195,3 +195,95 @@ func TestPagamentoRepository_GetByClienteID(t *testing.T) {
 		})
 	}
 }
+
+func TestPagamentoRepository_ListExpiredPIX(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer mock.ExpectationsWereMet()
+
+	repo := repository.PagamentoRepositoryNew(db)
+
+	expiredAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		mockRows *sqlmock.Rows
+		expected int
+		hasError bool
+	}{
+		{
+			name: "Expired PIX payments",
+			mockRows: sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "status", "valor", "metodo_pagamento", "expira_em"}).
+				AddRow(1, 1, 1, "pending", 150.50, "pix", expiredAt).
+				AddRow(2, 1, 1, "in_process", 200.00, "pix", expiredAt),
+			expected: 2,
+			hasError: false,
+		},
+		{
+			name:     "No expired payments",
+			mockRows: sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "status", "valor", "metodo_pagamento", "expira_em"}),
+			expected: 0,
+			hasError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(`SELECT \* FROM "pagamentos" WHERE metodo_pagamento = .* AND expira_em IS NOT NULL`).
+				WillReturnRows(tt.mockRows)
+
+			pagamentos, err := repo.ListExpiredPIX(time.Now(), 100)
+			if (err != nil) != tt.hasError {
+				t.Errorf("ListExpiredPIX() error = %v, hasError = %v", err, tt.hasError)
+			}
+
+			if !tt.hasError && len(pagamentos) != tt.expected {
+				t.Errorf("ListExpiredPIX() returned %d payments, expected %d", len(pagamentos), tt.expected)
+			}
+		})
+	}
+}
+
+func TestPagamentoRepository_GetByMercadoPagoOrderID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer mock.ExpectationsWereMet()
+
+	repo := repository.PagamentoRepositoryNew(db)
+
+	tests := []struct {
+		name     string
+		orderID  string
+		mockRows *sqlmock.Rows
+		hasError bool
+	}{
+		{
+			name:    "Existing order",
+			orderID: "ORD01JQ4S4KY8HWQ6NA5PXB65B3D3",
+			mockRows: sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "mercado_pago_order_id", "status", "valor", "metodo_pagamento"}).
+				AddRow(1, 1, 1, "ORD01JQ4S4KY8HWQ6NA5PXB65B3D3", "authorized", 300.00, "credit_card"),
+			hasError: false,
+		},
+		{
+			name:     "Non-existent order",
+			orderID:  "ORD-UNKNOWN",
+			mockRows: sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "mercado_pago_order_id", "status", "valor", "metodo_pagamento"}),
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(`SELECT \* FROM "pagamentos" WHERE mercado_pago_order_id = \$1`).
+				WithArgs(tt.orderID, 1).
+				WillReturnRows(tt.mockRows)
+
+			result, err := repo.GetByMercadoPagoOrderID(tt.orderID)
+			if (err != nil) != tt.hasError {
+				t.Errorf("GetByMercadoPagoOrderID() error = %v, hasError = %v", err, tt.hasError)
+			}
+
+			if !tt.hasError && result.MercadoPagoOrderID != tt.orderID {
+				t.Errorf("GetByMercadoPagoOrderID() order ID = %s, expected %s", result.MercadoPagoOrderID, tt.orderID)
+			}
+		})
+	}
+}
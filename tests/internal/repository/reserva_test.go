@@ -277,3 +277,41 @@ func TestReservaRepository_GetByEmpresaID(t *testing.T) {
 		})
 	}
 }
+
+func TestReservaRepository_LinkPayment(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer mock.ExpectationsWereMet()
+
+	repo := repository.ReservaRepositoryNew(db)
+
+	tests := []struct {
+		name         string
+		reservaID    int
+		pagamentoID  int
+		rowsAffected int64
+		hasError     bool
+	}{
+		{
+			name:         "Link payment to reservation",
+			reservaID:    1,
+			pagamentoID:  10,
+			rowsAffected: 1,
+			hasError:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "reservas" SET "momento_atualizacao"=\$1,"pagamento_id"=\$2 WHERE id = \$3`).
+				WithArgs(sqlmock.AnyArg(), tt.pagamentoID, tt.reservaID).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			err := repo.LinkPayment(tt.reservaID, tt.pagamentoID)
+			if (err != nil) != tt.hasError {
+				t.Errorf("LinkPayment() error = %v, hasError = %v", err, tt.hasError)
+			}
+		})
+	}
+}
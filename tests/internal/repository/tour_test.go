@@ -42,7 +42,6 @@ func TestTourRepository_Create(t *testing.T) {
 				ArrivalTime:   "18:00",
 				MaxPeople:     20,
 				Description:   "Passeio pela cidade histórica",
-				Images:        pq.StringArray{"image1.jpg"},
 				Price:         150.50,
 			},
 			hasError: false,
@@ -57,7 +56,6 @@ func TestTourRepository_Create(t *testing.T) {
 				ArrivalTime:   "17:00",
 				MaxPeople:     15,
 				Description:   "Passeio cultural",
-				Images:        pq.StringArray{},
 				Price:         120.00,
 			},
 			hasError: false,
@@ -75,7 +73,6 @@ func TestTourRepository_Create(t *testing.T) {
 					tt.tour.ArrivalTime,
 					tt.tour.MaxPeople,
 					tt.tour.Description,
-					tt.tour.Images,
 					tt.tour.Price,
 				).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
@@ -97,3 +94,62 @@ func TestTourRepository_List(t *testing.T) {
 	// Skipping due to sqlmock limitations with PostgreSQL arrays
 	t.Skip("Skipping due to sqlmock limitations with PostgreSQL arrays - requires database integration")
 }
+
+func TestTourRepository_CreateWithImages(t *testing.T) {
+	newTour := func() *model.Tour {
+		return &model.Tour{
+			CompanyID:     3,
+			Name:          "Passeio de Barco",
+			Dates:         pq.StringArray{"2024-01-15"},
+			DepartureTime: "08:00",
+			ArrivalTime:   "12:00",
+			MaxPeople:     10,
+			Price:         90,
+		}
+	}
+
+	t.Run("attaches images in order with the first as primary", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.TourRepositoryNew(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO tours`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectExec(`SET tour_id = NULL`).WithArgs(7, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SET tour_id = \$1, is_primary = FALSE`).
+			WithArgs(7, sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`SET is_primary = TRUE`).WithArgs(12, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tour := newTour()
+		created, err := repo.CreateWithImages(tour, []int{12, 11})
+		if err != nil || !created {
+			t.Fatalf("CreateWithImages() = %v, %v; expected true, nil", created, err)
+		}
+		if tour.ID != 7 {
+			t.Errorf("Expected tour ID 7, got %d", tour.ID)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+
+	t.Run("rolls back when an image is unavailable", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.TourRepositoryNew(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO tours`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectExec(`SET tour_id = NULL`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SET tour_id = \$1, is_primary = FALSE`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		created, err := repo.CreateWithImages(newTour(), []int{12, 11})
+		if err != nil || created {
+			t.Fatalf("CreateWithImages() = %v, %v; expected false, nil", created, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/mercadopago"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// checkoutRequest - checkout por PIX das reservas informadas do cliente 4
+func checkoutRequest(reservaIDs ...int) *contract.CheckoutRequest {
+	request := &contract.CheckoutRequest{
+		ClienteID:   4,
+		ReservaIDs:  reservaIDs,
+		PaymentType: "pix",
+	}
+	request.Payer.Email = "cliente@example.com"
+	return request
+}
+
+func TestPagamentoService_CheckoutRejectsInvalidCarts(t *testing.T) {
+	tests := []struct {
+		name           string
+		reservaIDs     []int
+		expect         func(mock sqlmock.Sqlmock)
+		expectedStatus int
+	}{
+		{
+			name:       "reservations from different companies",
+			reservaIDs: []int{11, 12},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 4, 0)
+				expectTour(mock, 3, 45)
+
+				// a segunda reserva é de outro passeio, da empresa 8
+				mock.ExpectQuery(`FROM "reservas"`).WillReturnRows(sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "tour_id", "pagamento_id", "status", "quantidade_pessoas"}).
+					AddRow(12, 4, 8, 10, 0, "pendente", 1))
+				mock.ExpectQuery(`FROM "clients"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery(`FROM "companies"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				mock.ExpectQuery(`FROM tours t`).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "name", "dates", "departure_time", "arrival_time", "max_people", "description", "price", "created_at", "updated_at", "company_name"}).
+					AddRow(10, 8, "Mergulho", "{2024-01-16}", "09:00", "11:00", 6, "", 120.0, time.Now(), time.Now(), "Outra Empresa"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "repeated reservation",
+			reservaIDs: []int{11, 11},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 4, 0)
+				expectTour(mock, 3, 45)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "reservation of another client",
+			reservaIDs: []int{11},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 5, 0)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			pagamentoService, mock := services.Pagamento, services.Mock
+
+			tt.expect(mock)
+
+			_, err := pagamentoService.Checkout(context.Background(), checkoutRequest(tt.reservaIDs...))
+
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected %d error, got %v", tt.expectedStatus, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPagamentoService_CheckoutPIXReturnsQRCode(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		var order mercadopago.OrderRequest
+		if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+			t.Fatalf("Invalid order body: %v", err)
+		}
+		if expiration := order.Transactions.Payments[0].ExpirationTime; expiration != "PT30M" {
+			t.Errorf("Order expiration_time = %q, expected PT30M", expiration)
+		}
+
+		w.Write([]byte(`{"id": "ORD01", "status": "action_required", "status_detail": "waiting_transfer",
+			"transactions": {"payments": [{"id": "PAY01", "amount": 90, "status": "action_required", "status_detail": "waiting_transfer",
+			"date_of_expiration": "2024-01-10T12:30:00.000-03:00",
+			"payment_method": {"id": "pix", "type": "bank_transfer", "qr_code": "00020126", "qr_code_base64": "iVBORw0KGgo",
+			"ticket_url": "https://www.mercadopago.com.br/payments/1/ticket"}}]}}`))
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+	pagamentoService.PIXExpiration = 30 * time.Minute
+
+	expectReservation(mock, 4, 0)
+	expectTour(mock, 3, 45)
+	expectCommission(mock, "")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "reservas"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	response, err := pagamentoService.Checkout(context.Background(), checkoutRequest(11))
+	if err != nil {
+		t.Fatalf("Checkout() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if response.QRCode != "00020126" || response.QRCodeBase64 != "iVBORw0KGgo" || response.TicketURL == "" {
+		t.Errorf("Unexpected PIX data %q, %q, %q", response.QRCode, response.QRCodeBase64, response.TicketURL)
+	}
+	if response.Pagamento.QRCode != "00020126" {
+		t.Errorf("Payment QR code = %q, expected it persisted", response.Pagamento.QRCode)
+	}
+}
+
+// expectOrderPayment - pagamento pendente da order ORD01 do cliente 4 à empresa 3
+func expectOrderPayment(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`mercado_pago_order_id = \$1`).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "cliente_id", "empresa_id", "mercado_pago_order_id", "status", "metodo_pagamento", "valor",
+	}).AddRow(21, 4, 3, "ORD01", "pending", "pix", 90.0))
+}
+
+func TestPagamentoService_OrderOperationsAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		userID    int
+		userType  string
+	}{
+		{name: "client refunding", operation: "refund", userID: 4, userType: "client"},
+		{name: "client capturing", operation: "capture", userID: 4, userType: "client"},
+		{name: "another client cancelling", operation: "cancel", userID: 5, userType: "client"},
+		{name: "another company cancelling", operation: "cancel", userID: 8, userType: "company"},
+		{name: "client with the company ID", operation: "refund", userID: 3, userType: "client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			pagamentoService, mock := services.Pagamento, services.Mock
+
+			expectOrderPayment(mock)
+
+			request := &contract.OrderOperationRequest{OrderID: "ORD01", UserID: tt.userID, UserType: tt.userType}
+			var err error
+			switch tt.operation {
+			case "refund":
+				_, err = pagamentoService.RefundOrder(context.Background(), request)
+			case "capture":
+				_, err = pagamentoService.CaptureOrder(context.Background(), request)
+			case "cancel":
+				_, err = pagamentoService.CancelOrder(context.Background(), request)
+			}
+
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.StatusCode != http.StatusForbidden {
+				t.Fatalf("Expected 403 error, got %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPagamentoService_CancelOrderByClient(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/orders/ORD01/cancel" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id": "ORD01", "status": "canceled", "status_detail": "canceled"}`))
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	expectOrderPayment(mock)
+	expectPaymentSave(mock)
+	expectNoDispute(mock)
+	mock.ExpectQuery(`FROM "reservas"`).WithArgs(21).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(11, "pendente"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "reservas"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	response, err := pagamentoService.CancelOrder(context.Background(), &contract.OrderOperationRequest{OrderID: "ORD01", UserID: 4, UserType: "client"})
+	if err != nil {
+		t.Fatalf("CancelOrder() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+	if response.Pagamento.Status != "cancelled" {
+		t.Errorf("Status = %s, expected cancelled", response.Pagamento.Status)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"image"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+)
+
+// expectLedgerCount - verificação da existência de um lançamento do tipo informado para o pagamento 7
+func expectLedgerCount(mock sqlmock.Sqlmock, entryType string, count int) {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "ledger_entries"`).WithArgs(7, entryType).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+// expectLedgerEntry - gravação de um lançamento do pagamento 7 com o valor bruto e a comissão informados
+func expectLedgerEntry(mock sqlmock.Sqlmock, entryType string, gross, fee float64) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "ledger_entries"`).
+		WithArgs(7, 3, entryType, sqlmock.AnyArg(), gross, fee, gross-fee, sqlmock.AnyArg(), "BRL", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO "ledger_postings"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+	mock.ExpectCommit()
+}
+
+// expectLedgerRefunds - soma dos estornos já lançados para o pagamento 7
+func expectLedgerRefunds(mock sqlmock.Sqlmock, recorded float64) {
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(gross_amount\), 0\) FROM "ledger_entries"`).WithArgs(7, "refund").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(recorded))
+}
+
+// expectNotification - notificação gravada para a empresa
+func expectNotification(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "notifications"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+}
+
+// disputeRows - disputa 5 do pagamento 7 ainda não decidida
+func disputeRows(disputeType, status string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "pagamento_id", "company_id", "mercado_pago_chargeback_id", "type", "status", "amount", "currency", "documentation_required", "evidence_deadline"}).
+		AddRow(5, 7, 3, nil, disputeType, status, 90.0, "BRL", true, time.Now().Add(24*time.Hour))
+}
+
+func TestDisputeService_SyncFromPaymentOpensDispute(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	disputeService, mock := services.Dispute, services.Mock
+
+	mock.ExpectQuery(`FROM "disputes"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "disputes"`).WithArgs(7, 3, nil, "mediation", "open", "in_mediation_reason", 90.0, "BRL", true, "", sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+	expectNotification(mock)
+
+	payment := paidPayment("in_mediation")
+	payment.StatusDetail = "in_mediation_reason"
+
+	if err := disputeService.SyncFromPayment(payment); err != nil {
+		t.Fatalf("SyncFromPayment() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDisputeService_SyncFromPaymentResolvesChargeback(t *testing.T) {
+	t.Run("won chargeback returns the reversed amount to the company", func(t *testing.T) {
+		services := setupMockDBForServices(t, nil)
+		disputeService, mock := services.Dispute, services.Mock
+
+		mock.ExpectQuery(`FROM "disputes"`).WillReturnRows(disputeRows("chargeback", "evidence_submitted"))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "disputes"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectLedgerCount(mock, "chargeback_won", 0)
+
+		// o chargeback reverteu só o que restava depois de um reembolso parcial de R$ 30,00
+		mock.ExpectQuery(`FROM "ledger_entries"`).WithArgs(7, "chargeback", 1).WillReturnRows(sqlmock.NewRows([]string{
+			"id", "pagamento_id", "company_id", "type", "gross_amount", "fee_amount", "net_amount", "commission_rate", "currency",
+		}).AddRow(2, 7, 3, "chargeback", -60.0, -6.0, -54.0, 10.0, "BRL"))
+		expectLedgerEntry(mock, "chargeback_won", 60, 6)
+		expectNotification(mock)
+
+		if err := disputeService.SyncFromPayment(paidPayment("approved")); err != nil {
+			t.Fatalf("SyncFromPayment() unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+
+	t.Run("lost dispute keeps the reversal", func(t *testing.T) {
+		services := setupMockDBForServices(t, nil)
+		disputeService, mock := services.Dispute, services.Mock
+
+		mock.ExpectQuery(`FROM "disputes"`).WillReturnRows(disputeRows("chargeback", "open"))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "disputes"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectNotification(mock)
+
+		if err := disputeService.SyncFromPayment(paidPayment("refunded")); err != nil {
+			t.Fatalf("SyncFromPayment() unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestDisputeService_ProcessChargebackReversesUnrefundedSale(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/chargebacks/CB1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id": "CB1", "payments": [1001], "currency": "BRL", "amount": 90,
+			"documentation_required": true, "documentation_status": "pending"}`))
+	})
+	disputeService, mock := services.Dispute, services.Mock
+
+	mock.ExpectQuery(`mercado_pago_payment_id = \$1`).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "cliente_id", "empresa_id", "mercado_pago_payment_id", "status", "valor", "taxa_plataforma", "moeda",
+	}).AddRow(7, 4, 3, "1001", "approved", 90.0, 9.0, "BRL"))
+	mock.ExpectQuery(`mercado_pago_chargeback_id = \$1`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`FROM "disputes"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "disputes"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+	expectNotification(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "disputes"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// só a parte ainda não reembolsada da venda é revertida, com a comissão proporcional
+	expectLedgerCount(mock, "chargeback", 0)
+	expectLedgerCount(mock, "sale", 1)
+	expectLedgerRefunds(mock, -30)
+	expectCommission(mock, "")
+	expectLedgerEntry(mock, "chargeback", -60, -6)
+
+	response, err := disputeService.ProcessChargeback(context.Background(), "CB1")
+	if err != nil {
+		t.Fatalf("ProcessChargeback() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+	if response.Message != "Notificação processada com sucesso" {
+		t.Errorf("Unexpected response %q", response.Message)
+	}
+}
+
+func TestDisputeService_UploadEvidenceUsesPrivateStorage(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	disputeService, mock, store := services.Dispute, services.Mock, services.Store
+	ctx := context.Background()
+
+	filePath := &capturedArg{}
+	mock.ExpectQuery(`FROM "disputes"`).WillReturnRows(disputeRows("mediation", "open"))
+	mock.ExpectQuery(`FROM "dispute_evidences"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "dispute_evidences"`).
+		WithArgs(5, "check_in", "", "checkin.png", filePath, "image/png", sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "disputes"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	fileHeader := createMultipartImage(t, "checkin.png", image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	request := &contract.UploadDisputeEvidenceRequest{DisputeID: 5, CompanyID: 3, Kind: "check_in"}
+
+	if _, err := disputeService.UploadEvidence(ctx, request, []*multipart.FileHeader{fileHeader}); err != nil {
+		t.Fatalf("UploadEvidence() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	// a evidência fica fora de images/, que é o único prefixo servido pela rota de mídia
+	if !strings.HasPrefix(filePath.value, "disputes/5/") || !strings.HasSuffix(filePath.value, ".png") {
+		t.Fatalf("Evidence key = %q, expected it under disputes/5/", filePath.value)
+	}
+	if _, err := store.Get(ctx, filePath.value); err != nil {
+		t.Errorf("Evidence not stored: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"image"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+)
+
+var imageColumns = []string{
+	"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
+	"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
+	"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+	"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
+}
+
+func TestImageService_UploadImagesReusesDuplicateContent(t *testing.T) {
+	db, mock := setupMockDBForImageService(t)
+	dir := t.TempDir()
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		Storage:           storage.NewLocalStore(dir),
+		MediaBaseURL:      "/media",
+		RenditionWidths:   []int{320},
+	}
+
+	fileHeader := createMultipartImage(t, "praia-de-novo.png", image.NewRGBA(image.Rect(0, 0, 10, 10)))
+
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	mock.ExpectQuery(`content_hash = \$2`).WithArgs(1, sqlmock.AnyArg(), "public").WillReturnRows(sqlmock.NewRows(append(imageColumns, "thumbnail_size")).AddRow(
+		3, 1, nil, "1728900000_a1b2c3d4_praia.png", "praia.png", "images/1/1728900000_a1b2c3d4_praia.png", "images/1/thumb_1728900000_a1b2c3d4_praia.png",
+		512, 10, 10, "png", "", "", false, 0,
+		time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil, 128,
+	))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(`INSERT INTO images`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uploaded_at", "updated_at"}).AddRow(8, time.Now(), time.Now()),
+	)
+	mock.ExpectExec(`INSERT INTO image_renditions`).WithArgs(8, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
+
+	response, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, &contract.UploadImagesRequest{}, 1)
+	if err != nil {
+		t.Fatalf("UploadImages() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	uploaded := response.Images[0]
+	if uploaded.ID != 8 || uploaded.DuplicateOf == nil || *uploaded.DuplicateOf != 3 {
+		t.Errorf("Expected image 8 reported as duplicate of 3, got ID=%d DuplicateOf=%v", uploaded.ID, uploaded.DuplicateOf)
+	}
+	if response.TotalDuplicates != 1 {
+		t.Errorf("TotalDuplicates = %d, expected 1", response.TotalDuplicates)
+	}
+	if uploaded.URL != "/media/images/1/1728900000_a1b2c3d4_praia.png" || uploaded.OriginalName != "praia-de-novo.png" {
+		t.Errorf("Duplicate should reuse the stored file, got URL=%s OriginalName=%s", uploaded.URL, uploaded.OriginalName)
+	}
+
+	if _, err := storage.NewLocalStore(dir).Get(context.Background(), "images/1/thumb_1728900000_a1b2c3d4_praia.png"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Duplicate upload should not write new files")
+	}
+}
+
+func TestImageService_DeleteImageKeepsSharedFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		references int
+		keepsFile  bool
+	}{
+		{name: "file still referenced", references: 1, keepsFile: true},
+		{name: "last reference", references: 0, keepsFile: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDBForImageService(t)
+			store := storage.NewLocalStore(t.TempDir())
+			ctx := context.Background()
+
+			if err := store.Put(ctx, "images/1/foto.png", []byte("conteudo"), "image/png"); err != nil {
+				t.Fatalf("Put() unexpected error: %v", err)
+			}
+
+			imageService := &service.ImageService{
+				ImageRepository: repository.ImageRepositoryNew(db),
+				Storage:         store,
+				MediaBaseURL:    "/media",
+			}
+
+			mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows(imageColumns).AddRow(
+				5, 1, nil, "foto.png", "foto.png", "images/1/foto.png", "",
+				8, 1, 1, "png", "", "", false, 0,
+				time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
+			))
+			mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
+			mock.ExpectExec(`DELETE FROM images`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(`WHERE url = \$1`).WithArgs("images/1/foto.png").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.references))
+
+			if _, err := imageService.DeleteImage(5, 1); err != nil {
+				t.Fatalf("DeleteImage() unexpected error: %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+
+			_, err := store.Get(ctx, "images/1/foto.png")
+			if tt.keepsFile && err != nil {
+				t.Errorf("Shared file should be kept, got %v", err)
+			}
+			if !tt.keepsFile && !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("Unreferenced file should be removed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// capturedArg - argumento do sqlmock que aceita qualquer texto e guarda o valor recebido
+type capturedArg struct {
+	value string
+}
+
+func (a *capturedArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	a.value = s
+	return ok
+}
+
+// storeEditFiles - grava o original 40x30 da imagem 5 e os arquivos derivados antigos no armazenamento
+func storeEditFiles(t *testing.T, store *storage.LocalStore) {
+	ctx := context.Background()
+
+	original := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			original.Set(x, y, color.RGBA{20, 120, 200, 255})
+		}
+	}
+	var data bytes.Buffer
+	if err := png.Encode(&data, original); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	files := map[string][]byte{
+		"images/1/foto.png":       data.Bytes(),
+		"images/1/thumb_foto.png": []byte("thumbnail"),
+		"images/1/20w_foto.jpg":   []byte("rendition"),
+	}
+	for key, content := range files {
+		if err := store.Put(ctx, key, content, "image/png"); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+	}
+}
+
+func editImageRow(status string) *sqlmock.Rows {
+	return sqlmock.NewRows(imageColumns).AddRow(
+		5, 1, nil, "foto.png", "foto.png", "images/1/foto.png", "images/1/thumb_foto.png",
+		2048, 40, 30, "png", "", "", false, 0,
+		time.Now(), time.Now(), nil, status, "public", "", "", nil, nil, nil, nil, nil, nil,
+	)
+}
+
+func TestImageService_EditImageRegeneratesDerivatives(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	services.Image.RenditionWidths = []int{20}
+	storeEditFiles(t, services.Store)
+	imageService, mock, store := services.Image, services.Mock, services.Store
+	ctx := context.Background()
+	renditionColumns := []string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}
+	thumbnail := &capturedArg{}
+
+	mock.ExpectQuery(`SELECT`).WillReturnRows(editImageRow("ready"))
+	mock.ExpectQuery(`FROM image_renditions`).WillReturnRows(sqlmock.NewRows(renditionColumns).
+		AddRow(1, 5, 20, 15, "jpg", "images/1/20w_foto.jpg", 9, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET thumbnail_url`).WithArgs(
+		5, "images/1/thumb_foto.png", thumbnail, sqlmock.AnyArg(), "#1478c8", 0.75, 0.5, 4, 2, 32, 24, sqlmock.AnyArg(),
+	).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM image_renditions`).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`INSERT INTO image_renditions`).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at"}).AddRow(i+2, time.Now()),
+		)
+	}
+	mock.ExpectCommit()
+
+	// o thumbnail antigo ainda é usado por uma duplicata; a versão antiga não
+	mock.ExpectQuery(`FROM images WHERE url = \$1`).WithArgs("images/1/thumb_foto.png").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`FROM images WHERE url = \$1`).WithArgs("images/1/20w_foto.jpg").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`UPDATE images`).WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	mock.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows(imageColumns).AddRow(
+		5, 1, nil, "foto.png", "foto.png", "images/1/foto.png", "images/1/thumb_e1234567_foto.png",
+		2048, 40, 30, "png", "", "", false, 0,
+		time.Now(), time.Now(), nil, "ready", "public", "", "#1478c8", 0.75, 0.5, 4, 2, 32, 24,
+	))
+	mock.ExpectQuery(`FROM image_renditions`).WillReturnRows(sqlmock.NewRows(renditionColumns))
+
+	request := &contract.UpdateImageRequest{
+		FocalPoint: &contract.ImageFocalPoint{X: 0.75, Y: 0.5},
+		Crop:       &contract.ImageCrop{X: 4, Y: 2, Width: 32, Height: 24},
+	}
+
+	response, err := imageService.UpdateImage(5, request, 1)
+	if err != nil {
+		t.Fatalf("UpdateImage() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if response.Image.FocalPoint == nil || response.Image.FocalPoint.X != 0.75 || response.Image.FocalPoint.Y != 0.5 {
+		t.Errorf("FocalPoint = %+v, expected (0.75, 0.5)", response.Image.FocalPoint)
+	}
+	if response.Image.Crop == nil || *response.Image.Crop != (contract.ImageCrop{X: 4, Y: 2, Width: 32, Height: 24}) {
+		t.Errorf("Crop = %+v, expected 32x24 at (4, 2)", response.Image.Crop)
+	}
+
+	// o thumbnail novo tem chave nova e é o quadrado do recorte em volta do ponto focal
+	if thumbnail.value == "" || thumbnail.value == "images/1/thumb_foto.png" {
+		t.Fatalf("Expected a new thumbnail key, got %q", thumbnail.value)
+	}
+	data, err := store.Get(ctx, thumbnail.value)
+	if err != nil {
+		t.Fatalf("New thumbnail not stored: %v", err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width != 24 || config.Height != 24 {
+		t.Errorf("Thumbnail = %dx%d (%v), expected 24x24", config.Width, config.Height, err)
+	}
+
+	if _, err := store.Get(ctx, "images/1/thumb_foto.png"); err != nil {
+		t.Errorf("Shared thumbnail should be kept, got %v", err)
+	}
+	if _, err := store.Get(ctx, "images/1/20w_foto.jpg"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Replaced rendition should be removed, got %v", err)
+	}
+	if _, err := store.Get(ctx, "images/1/foto.png"); err != nil {
+		t.Errorf("Original should be kept, got %v", err)
+	}
+}
+
+func TestImageService_EditImageRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		crop           *contract.ImageCrop
+		expectedStatus int
+	}{
+		{name: "crop outside the image", status: "ready", crop: &contract.ImageCrop{X: 20, Y: 0, Width: 32, Height: 24}, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "image still processing", status: "processing", crop: &contract.ImageCrop{X: 0, Y: 0, Width: 32, Height: 24}, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := setupMockDBForServices(t, nil)
+			services.Image.RenditionWidths = []int{20}
+			storeEditFiles(t, services.Store)
+			imageService, mock, store := services.Image, services.Mock, services.Store
+
+			mock.ExpectQuery(`SELECT`).WillReturnRows(editImageRow(tt.status))
+
+			_, err := imageService.UpdateImage(5, &contract.UpdateImageRequest{Crop: tt.crop}, 1)
+
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected %d error, got %v", tt.expectedStatus, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+			if _, err := store.Get(context.Background(), "images/1/thumb_foto.png"); err != nil {
+				t.Errorf("Thumbnail should be untouched, got %v", err)
+			}
+		})
+	}
+}
+
+func TestImageService_UpdateImageVisibilityCopiesFiles(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	services.Image.RenditionWidths = []int{20}
+	storeEditFiles(t, services.Store)
+	imageService, mock, store := services.Image, services.Mock, services.Store
+	ctx := context.Background()
+	renditionColumns := []string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}
+	filename, original, thumbnail, rendition := &capturedArg{}, &capturedArg{}, &capturedArg{}, &capturedArg{}
+
+	mock.ExpectQuery(`SELECT`).WillReturnRows(editImageRow("ready"))
+	mock.ExpectQuery(`FROM image_renditions`).WillReturnRows(sqlmock.NewRows(renditionColumns).
+		AddRow(1, 5, 20, 15, "jpg", "images/1/20w_foto.jpg", 9, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET filename`).WithArgs(5, "images/1/foto.png", filename, original, thumbnail, "private").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM image_renditions`).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO image_renditions`).WithArgs(5, 20, 15, "jpg", rendition, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, time.Now()))
+	mock.ExpectCommit()
+
+	// o original continua sendo usado por uma duplicata pública; o thumbnail e a versão não
+	mock.ExpectQuery(`FROM images WHERE url = \$1`).WithArgs("images/1/foto.png").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`FROM images WHERE url = \$1`).WithArgs("images/1/thumb_foto.png").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`FROM images WHERE url = \$1`).WithArgs("images/1/20w_foto.jpg").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`UPDATE images`).WithArgs(5, nil, "", "", false, "private").WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	mock.ExpectQuery(`SELECT`).WillReturnRows(editImageRow("ready"))
+	mock.ExpectQuery(`FROM image_renditions`).WillReturnRows(sqlmock.NewRows(renditionColumns))
+
+	if _, err := imageService.UpdateImage(5, &contract.UpdateImageRequest{Visibility: "private"}, 1); err != nil {
+		t.Fatalf("UpdateImage() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	// a imagem privada passa a ter arquivos próprios, com o mesmo conteúdo
+	if original.value != "images/1/"+filename.value {
+		t.Errorf("URL %q should match the new filename %q", original.value, filename.value)
+	}
+	for key, previous := range map[string]string{original.value: "images/1/foto.png", thumbnail.value: "images/1/thumb_foto.png", rendition.value: "images/1/20w_foto.jpg"} {
+		if key == "" || key == previous {
+			t.Errorf("Key %q should change with the visibility", previous)
+			continue
+		}
+		if _, err := store.Get(ctx, key); err != nil {
+			t.Errorf("Copy %s not stored: %v", key, err)
+		}
+	}
+
+	if _, err := store.Get(ctx, "images/1/foto.png"); err != nil {
+		t.Errorf("Original shared with a public duplicate should be kept, got %v", err)
+	}
+	for _, key := range []string{"images/1/thumb_foto.png", "images/1/20w_foto.jpg"} {
+		if _, err := store.Get(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("Public file %s should be removed, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+)
+
+func TestImageService_CollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+
+	newGC := func(t *testing.T) (*service.ImageService, sqlmock.Sqlmock, storage.BlobStore) {
+		db, mock := setupMockDBForImageService(t)
+		dir := t.TempDir()
+		store := storage.NewLocalStore(dir)
+
+		files := map[string]time.Time{
+			"images/1/sem_passeio.jpg":        old,
+			"images/1/thumb_sem_passeio.jpg":  old,
+			"images/1/no_passeio.jpg":         old,
+			"images/1/pronta.jpg":             old,
+			"images/1/thumb_pronta.jpg":       old,
+			"images/1/orfao.jpg":              old,
+			"images/1/recente.jpg":            time.Now(),
+			"staging/images/1/abandonado.png": old,
+		}
+		for key, modified := range files {
+			if err := store.Put(ctx, key, []byte("conteudo"), "image/jpeg"); err != nil {
+				t.Fatalf("Put() unexpected error: %v", err)
+			}
+			if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(key)), modified, modified); err != nil {
+				t.Fatalf("Chtimes() unexpected error: %v", err)
+			}
+		}
+
+		return &service.ImageService{
+			ImageRepository: repository.ImageRepositoryNew(db),
+			Storage:         store,
+			MediaBaseURL:    "http://localhost:1450/jampa-trip/api/v1/media",
+		}, mock, store
+	}
+
+	expectScan := func(mock sqlmock.Sqlmock, dryRun bool) {
+		// imagens privadas e o tempo desde o upload não entram no critério
+		mock.ExpectQuery(`tour_id IS NULL AND visibility = 'public' AND status <> 'processing' AND unattached_at < \$1`).WillReturnRows(sqlmock.NewRows(imageColumns).
+			AddRow(5, 1, nil, "sem_passeio.jpg", "sem_passeio.jpg", "images/1/sem_passeio.jpg", "images/1/thumb_sem_passeio.jpg",
+				2048, 40, 30, "jpg", "", "", false, 0, old, old, nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil))
+		mock.ExpectQuery(`FROM image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
+
+		references := sqlmock.NewRows([]string{"url"}).
+			AddRow("images/1/pronta.jpg").
+			AddRow("images/1/thumb_pronta.jpg").
+			AddRow("images/1/no_passeio.jpg")
+
+		if dryRun {
+			references.AddRow("images/1/sem_passeio.jpg").AddRow("images/1/thumb_sem_passeio.jpg")
+		} else {
+			mock.ExpectExec(`DELETE FROM images`).WithArgs(5, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(`SELECT COUNT\(\*\) FROM images WHERE url`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		}
+
+		mock.ExpectQuery(`UNION`).WillReturnRows(references)
+		mock.ExpectQuery(`status = 'ready' AND updated_at`).WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).
+			AddRow(7, "images/1/pronta.jpg").
+			AddRow(8, "images/1/sumiu.jpg"))
+		if !dryRun {
+			mock.ExpectExec(`UPDATE images SET status`).WithArgs(8, "failed").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectQuery(`SELECT DISTINCT storage_key`).WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("images/1/640w_pronta.jpg"))
+		if !dryRun {
+			mock.ExpectExec(`DELETE FROM image_renditions`).WithArgs("images/1/640w_pronta.jpg").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+
+	checkReport := func(t *testing.T, report *contract.ImageGCReport) {
+		if report.UnusedImages != 1 || report.UnusedBytes != 2048 {
+			t.Errorf("Expected one unused image, got %d (%d bytes)", report.UnusedImages, report.UnusedBytes)
+		}
+		if report.OrphanedFiles != 2 || report.OrphanedBytes != 16 {
+			t.Errorf("Expected two orphaned files, got %d (%d bytes)", report.OrphanedFiles, report.OrphanedBytes)
+		}
+		if report.MissingOriginals != 1 || report.MissingRenditions != 1 || report.Failed != 0 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+
+		expected := []string{
+			contract.ImageGCDeleteImage + " images/1/sem_passeio.jpg",
+			contract.ImageGCDeleteFile + " images/1/orfao.jpg",
+			contract.ImageGCDeleteFile + " staging/images/1/abandonado.png",
+			contract.ImageGCMarkFailed + " images/1/sumiu.jpg",
+			contract.ImageGCDeleteRendition + " images/1/640w_pronta.jpg",
+		}
+		if len(report.Items) != len(expected) {
+			t.Fatalf("Expected %d items, got %+v", len(expected), report.Items)
+		}
+		for i, item := range report.Items {
+			if got := item.Action + " " + item.Key; got != expected[i] {
+				t.Errorf("Item %d = %s, expected %s", i, got, expected[i])
+			}
+		}
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		imageService, mock, store := newGC(t)
+		expectScan(mock, true)
+
+		report, err := imageService.CollectGarbage(ctx, 7*24*time.Hour, 500, true)
+		if err != nil {
+			t.Fatalf("CollectGarbage() unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+		if !report.DryRun {
+			t.Errorf("Expected dry run report")
+		}
+		checkReport(t, report)
+
+		// nada é removido na simulação
+		for _, key := range []string{"images/1/sem_passeio.jpg", "images/1/orfao.jpg", "staging/images/1/abandonado.png"} {
+			if _, err := store.Get(ctx, key); err != nil {
+				t.Errorf("Dry run should keep %s, got %v", key, err)
+			}
+		}
+	})
+
+	t.Run("deletes orphans", func(t *testing.T) {
+		imageService, mock, store := newGC(t)
+		expectScan(mock, false)
+
+		report, err := imageService.CollectGarbage(ctx, 7*24*time.Hour, 500, false)
+		if err != nil {
+			t.Fatalf("CollectGarbage() unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+		checkReport(t, report)
+
+		for _, key := range []string{"images/1/sem_passeio.jpg", "images/1/thumb_sem_passeio.jpg", "images/1/orfao.jpg", "staging/images/1/abandonado.png"} {
+			if _, err := store.Get(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("Expected %s to be removed, got %v", key, err)
+			}
+		}
+
+		// arquivos referenciados ou gravados há pouco são mantidos
+		for _, key := range []string{"images/1/pronta.jpg", "images/1/thumb_pronta.jpg", "images/1/no_passeio.jpg", "images/1/recente.jpg"} {
+			if _, err := store.Get(ctx, key); err != nil {
+				t.Errorf("Expected %s to be kept, got %v", key, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"image"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// testImageQuotas - 1 MB para as empresas e 10 MB para o plano profissional
+var testImageQuotas = service.ImageQuotas{
+	Company: 1 << 20,
+	Plans:   map[string]int64{"profissional": 10 << 20},
+}
+
+func TestImageService_GetQuota(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	services.Image.Quotas = testImageQuotas
+	imageService, mock := services.Image, services.Mock
+
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("Profissional"))
+	mock.ExpectQuery(`FROM image_stats`).WillReturnRows(sqlmock.NewRows([]string{"storage_used", "total_images"}).AddRow(5<<20, 12))
+
+	response, err := imageService.GetQuota(1)
+	if err != nil {
+		t.Fatalf("GetQuota() unexpected error: %v", err)
+	}
+
+	if response.Plan != "profissional" || response.QuotaBytes != 10<<20 {
+		t.Errorf("Expected the plan quota, got plan=%s quota=%d", response.Plan, response.QuotaBytes)
+	}
+	if response.UsedBytes != 5<<20 || response.AvailableBytes != 5<<20 || response.UsagePercent != 50 || response.TotalImages != 12 {
+		t.Errorf("Unexpected usage: %+v", response)
+	}
+
+	// empresas sem imagens não aparecem na view
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	mock.ExpectQuery(`FROM image_stats`).WillReturnRows(sqlmock.NewRows([]string{"storage_used", "total_images"}))
+
+	response, err = imageService.GetQuota(2)
+	if err != nil {
+		t.Fatalf("GetQuota() unexpected error: %v", err)
+	}
+	if response.QuotaBytes != 1<<20 || response.UsedBytes != 0 || response.AvailableBytes != 1<<20 {
+		t.Errorf("Unexpected company quota: %+v", response)
+	}
+}
+
+func TestImageService_UploadImagesQuotaExceeded(t *testing.T) {
+	t.Run("quota already reached", func(t *testing.T) {
+		services := setupMockDBForServices(t, nil)
+		services.Image.Quotas = testImageQuotas
+		imageService, mock := services.Image, services.Mock
+		fileHeader := createMultipartImage(t, "praia.png", image.NewRGBA(image.Rect(0, 0, 10, 10)))
+
+		mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+		mock.ExpectQuery(`FROM image_stats`).WillReturnRows(sqlmock.NewRows([]string{"storage_used", "total_images"}).AddRow(1<<20, 40))
+
+		_, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, &contract.UploadImagesRequest{}, 1)
+
+		appErr, ok := err.(*util.AppError)
+		if !ok || appErr.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Fatalf("Expected 413, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+
+	t.Run("upload would exceed the quota", func(t *testing.T) {
+		services := setupMockDBForServices(t, nil)
+		services.Image.Quotas = testImageQuotas
+		imageService, mock, dir := services.Image, services.Mock, services.StoreDir
+		fileHeader := createMultipartImage(t, "praia.png", image.NewRGBA(image.Rect(0, 0, 10, 10)))
+
+		mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+		mock.ExpectQuery(`FROM image_stats`).WillReturnRows(sqlmock.NewRows([]string{"storage_used", "total_images"}).AddRow(1<<20-10, 40))
+		mock.ExpectQuery(`content_hash = \$2`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectBegin()
+		mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FROM image_stats`).WillReturnRows(sqlmock.NewRows([]string{"storage_used", "total_images"}).AddRow(1<<20-10, 40))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`FROM image_stats`).WillReturnRows(sqlmock.NewRows([]string{"storage_used", "total_images"}).AddRow(1<<20-10, 40))
+
+		response, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, &contract.UploadImagesRequest{}, 1)
+		if err != nil {
+			t.Fatalf("UploadImages() unexpected error: %v", err)
+		}
+
+		if status := service.UploadStatusCode(response); status != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413, got %d", status)
+		}
+		if response.Success || response.Results[0].Code != service.UploadErrorQuotaExceeded {
+			t.Errorf("Expected quota_exceeded result, got %+v", response.Results)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+
+		// os arquivos gravados antes da verificação final são removidos
+		entries, _ := os.ReadDir(dir + "/images/1")
+		if len(entries) != 0 {
+			t.Errorf("Expected stored files to be cleaned up, found %d", len(entries))
+		}
+		if _, err := storage.NewLocalStore(dir).Get(context.Background(), "images/1/praia.png"); err == nil {
+			t.Errorf("Unexpected file left in storage")
+		}
+	})
+}
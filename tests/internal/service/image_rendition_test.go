@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+)
+
+func createMultipartImage(t *testing.T, filename string, img image.Image) *multipart.FileHeader {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreatePart(map[string][]string{
+		"Content-Disposition": {`form-data; name="images"; filename="` + filename + `"`},
+		"Content-Type":        {"image/png"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create multipart part: %v", err)
+	}
+	part.Write(data.Bytes())
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/upload/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		t.Fatalf("Failed to parse multipart form: %v", err)
+	}
+
+	return req.MultipartForm.File["images"][0]
+}
+
+func TestImageService_UploadImagesGeneratesRenditions(t *testing.T) {
+	db, mock := setupMockDBForImageService(t)
+	store := storage.NewLocalStore(t.TempDir())
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		Storage:           store,
+		MediaBaseURL:      "/media",
+		RenditionWidths:   []int{320, 640, 1280},
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 800; x++ {
+			img.Set(x, y, color.RGBA{20, 120, 200, 255})
+		}
+	}
+	fileHeader := createMultipartImage(t, "praia.png", img)
+
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	mock.ExpectQuery(`content_hash = \$2`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`INSERT INTO images`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uploaded_at", "updated_at"}).AddRow(7, time.Now(), time.Now()),
+	)
+	mock.ExpectBegin()
+	for i := 0; i < 4; i++ {
+		mock.ExpectQuery(`INSERT INTO image_renditions`).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at"}).AddRow(i+1, time.Now()),
+		)
+	}
+	mock.ExpectCommit()
+
+	response, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, &contract.UploadImagesRequest{}, 1)
+	if err != nil {
+		t.Fatalf("UploadImages() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	uploaded := response.Images[0]
+
+	// 1280 is wider than the original and is skipped; each width has a JPEG and a WebP
+	if len(uploaded.Renditions) != 4 {
+		t.Fatalf("Renditions = %d, expected 4", len(uploaded.Renditions))
+	}
+
+	for _, rendition := range uploaded.Renditions {
+		if rendition.Width >= 800 {
+			t.Errorf("Rendition width %d should be smaller than the original", rendition.Width)
+		}
+		if rendition.Height != rendition.Width/2 {
+			t.Errorf("Rendition %dx%d should keep the aspect ratio", rendition.Width, rendition.Height)
+		}
+
+		key := strings.TrimPrefix(rendition.URL, "/media/")
+		if _, err := store.Get(context.Background(), key); err != nil {
+			t.Errorf("Rendition %s not stored: %v", key, err)
+		}
+	}
+
+	jpegSrcSet := uploaded.SrcSet["image/jpeg"]
+	if !strings.Contains(jpegSrcSet, "320w_") || !strings.HasSuffix(jpegSrcSet, " 640w") {
+		t.Errorf("SrcSet[image/jpeg] = %q", jpegSrcSet)
+	}
+
+	if _, ok := uploaded.SrcSet["image/webp"]; !ok {
+		t.Errorf("SrcSet should include the WebP renditions")
+	}
+
+	// placeholders: BlurHash 4x3 para imagem horizontal e a cor de fundo da imagem
+	if len(uploaded.BlurHash) != 28 || uploaded.BlurHash[0] != 'L' {
+		t.Errorf("BlurHash = %q, expected 4x3 components", uploaded.BlurHash)
+	}
+	if uploaded.DominantColor != "#1478c8" {
+		t.Errorf("DominantColor = %q, expected #1478c8", uploaded.DominantColor)
+	}
+}
+
+func TestImageService_UploadImagesKeepsWebPForPhotos(t *testing.T) {
+	db, mock := setupMockDBForImageService(t)
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		Storage:           storage.NewLocalStore(t.TempDir()),
+		MediaBaseURL:      "/media",
+		RenditionWidths:   []int{320},
+	}
+
+	// ruído imita uma foto: o WebP sem perdas fica maior que o JPEG
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	seed := uint32(1)
+	for i := range img.Pix {
+		seed = seed*1664525 + 1013904223
+		img.Pix[i] = byte(seed >> 24)
+		if i%4 == 3 {
+			img.Pix[i] = 255
+		}
+	}
+	fileHeader := createMultipartImage(t, "foto.png", img)
+
+	formats := []*capturedArg{{}, {}}
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	mock.ExpectQuery(`content_hash = \$2`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`INSERT INTO images`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uploaded_at", "updated_at"}).AddRow(7, time.Now(), time.Now()),
+	)
+	mock.ExpectBegin()
+	for i, format := range formats {
+		mock.ExpectQuery(`INSERT INTO image_renditions`).WithArgs(7, 320, 240, format, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at"}).AddRow(i+1, time.Now()),
+		)
+	}
+	mock.ExpectCommit()
+
+	response, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, &contract.UploadImagesRequest{}, 1)
+	if err != nil {
+		t.Fatalf("UploadImages() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if formats[0].value != "jpg" || formats[1].value != "webp" {
+		t.Errorf("Rendition formats = %q, %q, expected jpg and webp", formats[0].value, formats[1].value)
+	}
+	if _, ok := response.Images[0].SrcSet["image/webp"]; !ok {
+		t.Errorf("SrcSet should include the WebP rendition even when it is larger than the JPEG")
+	}
+}
@@ -120,15 +120,17 @@ func TestImageService_ListImages(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 
 				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
 			},
 			expectedError:  false,
 			expectedStatus: http.StatusOK,
@@ -145,14 +147,16 @@ func TestImageService_ListImages(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
 			},
 			expectedError:  false,
 			expectedStatus: http.StatusOK,
@@ -208,14 +212,17 @@ func TestImageService_DeleteImage(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
+				mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
 				mock.ExpectExec(`DELETE FROM images`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 			},
 			expectedError:  false,
 			expectedStatus: http.StatusOK,
@@ -296,11 +303,12 @@ func TestImageService_UpdateImage(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", false, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				mock.ExpectExec(`UPDATE images`).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -308,13 +316,15 @@ func TestImageService_UpdateImage(t *testing.T) {
 				rows2 := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Updated description", "Updated alt text", true, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows2)
+				mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
 			},
 			expectedError:  false,
 			expectedStatus: http.StatusOK,
@@ -384,19 +394,20 @@ func TestImageService_ReorderImages(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test1.jpg", "original1.jpg", "http://localhost/test1.jpg", "http://localhost/thumb_test1.jpg",
 					1024, 100, 100, "jpg", "Test image 1", "Alt text 1", false, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				).AddRow(
 					2, 1, 1, "test2.jpg", "original2.jpg", "http://localhost/test2.jpg", "http://localhost/thumb_test2.jpg",
 					1024, 100, 100, "jpg", "Test image 2", "Alt text 2", false, 1,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				).AddRow(
 					3, 1, 1, "test3.jpg", "original3.jpg", "http://localhost/test3.jpg", "http://localhost/thumb_test3.jpg",
 					1024, 100, 100, "jpg", "Test image 3", "Alt text 3", false, 2,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				mock.ExpectExec(`UPDATE images`).WillReturnResult(sqlmock.NewResult(3, 3))
@@ -415,15 +426,16 @@ func TestImageService_ReorderImages(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test1.jpg", "original1.jpg", "http://localhost/test1.jpg", "http://localhost/thumb_test1.jpg",
 					1024, 100, 100, "jpg", "Test image 1", "Alt text 1", false, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				).AddRow(
 					3, 1, 1, "test3.jpg", "original3.jpg", "http://localhost/test3.jpg", "http://localhost/thumb_test3.jpg",
 					1024, 100, 100, "jpg", "Test image 3", "Alt text 3", false, 2,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 			},
@@ -479,15 +491,17 @@ func TestImageService_GetImageInfo(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at", "tour_name",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height", "tour_name",
 				}).AddRow(
 					1, 1, 1, "test.jpg", "original.jpg", "http://localhost/test.jpg", "http://localhost/thumb_test.jpg",
 					1024, 100, 100, "jpg", "Test image", "Alt text", true, 0,
-					time.Now(), time.Now(), "Test Tour",
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil, "Test Tour",
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				usageRows := sqlmock.NewRows([]string{"tour_name", "is_used"}).AddRow("Test Tour", true)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(usageRows)
+				mock.ExpectQuery(`image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
 			},
 			expectedError:  false,
 			expectedStatus: http.StatusOK,
@@ -559,19 +573,20 @@ func TestImageService_BatchDeleteImages(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test1.jpg", "original1.jpg", "http://localhost/test1.jpg", "http://localhost/thumb_test1.jpg",
 					1024, 100, 100, "jpg", "Test image 1", "Alt text 1", false, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				).AddRow(
 					2, 1, 1, "test2.jpg", "original2.jpg", "http://localhost/test2.jpg", "http://localhost/thumb_test2.jpg",
 					1024, 100, 100, "jpg", "Test image 2", "Alt text 2", false, 1,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				).AddRow(
 					3, 1, 1, "test3.jpg", "original3.jpg", "http://localhost/test3.jpg", "http://localhost/thumb_test3.jpg",
 					1024, 100, 100, "jpg", "Test image 3", "Alt text 3", false, 2,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				mock.ExpectExec(`DELETE FROM images`).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -596,15 +611,16 @@ func TestImageService_BatchDeleteImages(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "user_id", "tour_id", "filename", "original_name", "url", "thumbnail_url",
 					"size", "width", "height", "format", "description", "alt_text", "is_primary", "sort_order",
-					"uploaded_at", "updated_at",
+					"uploaded_at", "updated_at", "captured_at", "status", "visibility", "blurhash", "dominant_color",
+					"focal_x", "focal_y", "crop_x", "crop_y", "crop_width", "crop_height",
 				}).AddRow(
 					1, 1, 1, "test1.jpg", "original1.jpg", "http://localhost/test1.jpg", "http://localhost/thumb_test1.jpg",
 					1024, 100, 100, "jpg", "Test image 1", "Alt text 1", false, 0,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				).AddRow(
 					3, 1, 1, "test3.jpg", "original3.jpg", "http://localhost/test3.jpg", "http://localhost/thumb_test3.jpg",
 					1024, 100, 100, "jpg", "Test image 3", "Alt text 3", false, 2,
-					time.Now(), time.Now(),
+					time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil,
 				)
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 				mock.ExpectExec(`DELETE FROM images`).WillReturnResult(sqlmock.NewResult(1, 1))
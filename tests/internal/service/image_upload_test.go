@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+)
+
+func createMultipartFile(t *testing.T, filename, contentType string, data []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreatePart(map[string][]string{
+		"Content-Disposition": {`form-data; name="images"; filename="` + filename + `"`},
+		"Content-Type":        {contentType},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create multipart part: %v", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/upload/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		t.Fatalf("Failed to parse multipart form: %v", err)
+	}
+
+	return req.MultipartForm.File["images"][0]
+}
+
+func TestImageService_UploadImagesReportsPerFileResults(t *testing.T) {
+	db, mock := setupMockDBForImageService(t)
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		Storage:           storage.NewLocalStore(t.TempDir()),
+		MediaBaseURL:      "/media",
+		ProcessAsync:      true,
+	}
+
+	valid := createMultipartImage(t, "praia.png", image.NewRGBA(image.Rect(0, 0, 40, 30)))
+	tooLarge := createMultipartImage(t, "panorama.png", image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	tooLarge.Size = 11 << 20
+	unsupported := createMultipartFile(t, "roteiro.pdf", "application/pdf", []byte("%PDF-1.4"))
+	corrupted := createMultipartFile(t, "quebrada.png", "image/png", []byte("isto não é uma imagem"))
+
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	mock.ExpectQuery(`content_hash = \$2`).WillReturnRows(sqlmock.NewRows(imageColumns))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO images`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uploaded_at", "updated_at"}).AddRow(21, time.Now(), time.Now()),
+	)
+	mock.ExpectQuery(`INSERT INTO image_jobs`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "status", "attempts", "run_after", "created_at"}).AddRow(1, "pending", 0, time.Now(), time.Now()),
+	)
+	mock.ExpectCommit()
+	mock.ExpectQuery(`content_hash = \$2`).WillReturnRows(sqlmock.NewRows(imageColumns))
+
+	files := []*multipart.FileHeader{valid, tooLarge, unsupported, corrupted}
+	response, err := imageService.UploadImages(files, &contract.UploadImagesRequest{}, 1)
+	if err != nil {
+		t.Fatalf("UploadImages() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if response.TotalUploaded != 1 || response.TotalFailed != 3 || len(response.Results) != 4 {
+		t.Fatalf("Unexpected totals: uploaded=%d failed=%d results=%d", response.TotalUploaded, response.TotalFailed, len(response.Results))
+	}
+
+	expected := []struct {
+		name string
+		code string
+	}{
+		{"praia.png", ""},
+		{"panorama.png", service.UploadErrorFileTooLarge},
+		{"roteiro.pdf", service.UploadErrorUnsupportedType},
+		{"quebrada.png", service.UploadErrorInvalidImage},
+	}
+
+	for i, result := range response.Results {
+		if result.OriginalName != expected[i].name || result.Code != expected[i].code || result.Success != (expected[i].code == "") {
+			t.Errorf("Result %d = %+v, expected %s with code %q", i, result, expected[i].name, expected[i].code)
+		}
+		if !result.Success && result.Message == "" {
+			t.Errorf("Result %d should explain the failure", i)
+		}
+	}
+
+	if id := response.Results[0].ImageID; id == nil || *id != 21 {
+		t.Errorf("Successful result should reference the image, got %v", id)
+	}
+
+	if status := service.UploadStatusCode(response); status != http.StatusMultiStatus {
+		t.Errorf("UploadStatusCode() = %d, expected 207", status)
+	}
+}
+
+func TestUploadStatusCode(t *testing.T) {
+	failed := func(codes ...string) []contract.ImageUploadResult {
+		results := make([]contract.ImageUploadResult, len(codes))
+		for i, code := range codes {
+			results[i] = contract.ImageUploadResult{Code: code}
+		}
+		return results
+	}
+
+	tests := []struct {
+		name     string
+		response contract.UploadImagesResponse
+		expected int
+	}{
+		{"all stored", contract.UploadImagesResponse{TotalUploaded: 2}, http.StatusCreated},
+		{"processing", contract.UploadImagesResponse{TotalUploaded: 2, TotalProcessing: 1}, http.StatusAccepted},
+		{"partial", contract.UploadImagesResponse{TotalUploaded: 1, TotalFailed: 1, Results: failed(service.UploadErrorInvalidImage)}, http.StatusMultiStatus},
+		{"all over quota", contract.UploadImagesResponse{TotalFailed: 2, Results: failed(service.UploadErrorQuotaExceeded, service.UploadErrorQuotaExceeded)}, http.StatusRequestEntityTooLarge},
+		{"all internal", contract.UploadImagesResponse{TotalFailed: 1, Results: failed(service.UploadErrorInternal)}, http.StatusInternalServerError},
+		{"mixed failures", contract.UploadImagesResponse{TotalFailed: 2, Results: failed(service.UploadErrorQuotaExceeded, service.UploadErrorFileTooLarge)}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := service.UploadStatusCode(&tt.response); status != tt.expected {
+				t.Errorf("UploadStatusCode() = %d, expected %d", status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImageService_UploadImagesRejectsToursOfOtherCompanies(t *testing.T) {
+	tests := []struct {
+		name           string
+		expect         func(mock sqlmock.Sqlmock)
+		expectedStatus int
+	}{
+		{
+			name: "tour of another company",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectTour(mock, 8, 45)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "tour not found",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM tours t`).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDBForImageService(t)
+			store := storage.NewLocalStore(t.TempDir())
+
+			imageService := &service.ImageService{
+				ImageRepository:   repository.ImageRepositoryNew(db),
+				CompanyRepository: repository.CompanyRepositoryNew(db),
+				TourRepository:    repository.TourRepositoryNew(db),
+				Storage:           store,
+				MediaBaseURL:      "/media",
+			}
+
+			tt.expect(mock)
+
+			tourID := 9
+			files := []*multipart.FileHeader{createMultipartImage(t, "praia.png", image.NewRGBA(image.Rect(0, 0, 40, 30)))}
+			_, err := imageService.UploadImages(files, &contract.UploadImagesRequest{TourID: &tourID}, 3)
+
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected %d error, got %v", tt.expectedStatus, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+			if keys, _ := store.List(context.Background(), ""); len(keys) != 0 {
+				t.Errorf("No file should be stored, got %v", keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+)
+
+var imageJobColumns = []string{"id", "image_id", "source_key", "status", "attempts", "run_after", "created_at"}
+
+func TestImageService_UploadImagesEnqueuesProcessing(t *testing.T) {
+	db, mock := setupMockDBForImageService(t)
+	store := storage.NewLocalStore(t.TempDir())
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		Storage:           store,
+		MediaBaseURL:      "/media",
+		ProcessAsync:      true,
+	}
+
+	fileHeader := createMultipartImage(t, "praia.png", image.NewRGBA(image.Rect(0, 0, 40, 30)))
+
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	mock.ExpectQuery(`content_hash = \$2`).WillReturnRows(sqlmock.NewRows(imageColumns))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO images`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uploaded_at", "updated_at"}).AddRow(12, time.Now(), time.Now()),
+	)
+	mock.ExpectQuery(`INSERT INTO image_jobs`).WithArgs(12, sqlmock.AnyArg()).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "status", "attempts", "run_after", "created_at"}).AddRow(1, "pending", 0, time.Now(), time.Now()),
+	)
+	mock.ExpectCommit()
+
+	response, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, &contract.UploadImagesRequest{}, 1)
+	if err != nil {
+		t.Fatalf("UploadImages() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	uploaded := response.Images[0]
+	if uploaded.Status != "processing" || response.TotalProcessing != 1 {
+		t.Errorf("Expected image in processing, got status=%s total_processing=%d", uploaded.Status, response.TotalProcessing)
+	}
+	if uploaded.Width != 40 || uploaded.Height != 30 || uploaded.ThumbnailURL != "" {
+		t.Errorf("Unexpected image data while processing: %+v", uploaded)
+	}
+
+	// o original ainda não foi gravado em images/; apenas o arquivo enviado, na área de processamento
+	if _, err := store.Get(context.Background(), "staging/images/1/"+uploaded.Filename); err != nil {
+		t.Errorf("Expected uploaded file in staging area, got %v", err)
+	}
+	if _, err := store.Get(context.Background(), "images/1/"+uploaded.Filename); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Original should only be written by the worker")
+	}
+}
+
+func TestImageWorkerPool_RunNext(t *testing.T) {
+	ctx := context.Background()
+
+	newPool := func(t *testing.T) (*service.ImageWorkerPool, sqlmock.Sqlmock, storage.BlobStore) {
+		services := setupMockDBForServices(t, nil)
+		services.Image.RenditionWidths = []int{16}
+
+		return &service.ImageWorkerPool{
+			ImageService:        services.Image,
+			NotificationService: service.NotificationServiceNew(services.DB),
+			Workers:             1,
+		}, services.Mock, services.Store
+	}
+
+	processingImage := func() *sqlmock.Rows {
+		return sqlmock.NewRows(imageColumns).AddRow(
+			12, 1, nil, "1728900000_a1b2c3d4_praia.png", "praia.png", "images/1/1728900000_a1b2c3d4_praia.png", "",
+			512, 40, 30, "png", "", "", false, 0,
+			time.Now(), time.Now(), nil, "processing", "public", "", "", nil, nil, nil, nil, nil, nil,
+		)
+	}
+
+	t.Run("empty queue", func(t *testing.T) {
+		pool, mock, _ := newPool(t)
+
+		mock.ExpectQuery(`UPDATE image_jobs`).WillReturnRows(sqlmock.NewRows(imageJobColumns))
+
+		processed, err := pool.RunNext(ctx)
+		if err != nil || processed {
+			t.Errorf("RunNext() = %v, %v; expected no job", processed, err)
+		}
+	})
+
+	t.Run("processes the uploaded file", func(t *testing.T) {
+		pool, mock, store := newPool(t)
+		sourceKey := "staging/images/1/1728900000_a1b2c3d4_praia.png"
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+			t.Fatalf("png.Encode() unexpected error: %v", err)
+		}
+		if err := store.Put(ctx, sourceKey, buf.Bytes(), "image/png"); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+
+		mock.ExpectQuery(`UPDATE image_jobs`).WillReturnRows(sqlmock.NewRows(imageJobColumns).AddRow(3, 12, sourceKey, "running", 1, time.Now(), time.Now()))
+		mock.ExpectQuery(`SELECT`).WithArgs(12).WillReturnRows(processingImage())
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE images`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO image_renditions`).WithArgs(12, 16, 12, "png", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+		mock.ExpectQuery(`INSERT INTO image_renditions`).WithArgs(12, 16, 12, "webp", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, time.Now()))
+		mock.ExpectExec(`DELETE FROM image_jobs`).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "notifications"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		processed, err := pool.RunNext(ctx)
+		if err != nil || !processed {
+			t.Fatalf("RunNext() = %v, %v; expected a processed job", processed, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+
+		for _, key := range []string{"images/1/1728900000_a1b2c3d4_praia.png", "images/1/thumb_1728900000_a1b2c3d4_praia.png", "images/1/16w_1728900000_a1b2c3d4_praia.png"} {
+			if _, err := store.Get(ctx, key); err != nil {
+				t.Errorf("Expected %s to be stored, got %v", key, err)
+			}
+		}
+		if _, err := store.Get(ctx, sourceKey); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("Uploaded file should be removed after processing")
+		}
+	})
+
+	t.Run("retries and then fails", func(t *testing.T) {
+		pool, mock, _ := newPool(t)
+		sourceKey := "staging/images/1/perdido.png"
+
+		// tentativa intermediária: o job volta para a fila
+		mock.ExpectQuery(`UPDATE image_jobs`).WillReturnRows(sqlmock.NewRows(imageJobColumns).AddRow(3, 12, sourceKey, "running", 1, time.Now(), time.Now()))
+		mock.ExpectQuery(`SELECT`).WithArgs(12).WillReturnRows(processingImage())
+		mock.ExpectExec(`SET status = 'pending'`).WithArgs(3, sqlmock.AnyArg(), float64(30)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if _, err := pool.RunNext(ctx); err != nil {
+			t.Fatalf("RunNext() unexpected error: %v", err)
+		}
+
+		// última tentativa: job e imagem ficam como falhos e a empresa é avisada
+		mock.ExpectQuery(`UPDATE image_jobs`).WillReturnRows(sqlmock.NewRows(imageJobColumns).AddRow(3, 12, sourceKey, "running", 3, time.Now(), time.Now()))
+		mock.ExpectQuery(`SELECT`).WithArgs(12).WillReturnRows(processingImage())
+		mock.ExpectBegin()
+		mock.ExpectExec(`SET status = 'failed'`).WithArgs(3, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE images SET status`).WithArgs(12, "failed").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "notifications"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		if _, err := pool.RunNext(ctx); err != nil {
+			t.Fatalf("RunNext() unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+}
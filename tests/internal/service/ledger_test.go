@@ -0,0 +1,93 @@
+package service
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/model"
+)
+
+// postingRecorder - guarda os valores das partidas gravadas, na ordem das colunas do INSERT em ledger_postings
+type postingRecorder struct {
+	values []driver.Value
+}
+
+// Match - aceita qualquer valor, guardando-o
+func (r *postingRecorder) Match(value driver.Value) bool {
+	r.values = append(r.values, value)
+	return true
+}
+
+// expect - gravação de um lançamento do pagamento 7 com a quantidade de partidas informada
+func (r *postingRecorder) expect(mock sqlmock.Sqlmock, entryType string, postings int) {
+	args := make([]driver.Value, postings*6)
+	for i := range args {
+		args[i] = r
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "ledger_entries"`).WithArgs(7, 3, entryType, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "BRL", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO "ledger_postings"`).WithArgs(args...).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+}
+
+// payableBalance - saldo devido à empresa pelas partidas gravadas, calculado como em LedgerRepository.PayableBalance
+func (r *postingRecorder) payableBalance() float64 {
+	var balance float64
+	for i := 0; i+5 < len(r.values); i += 6 {
+		if r.values[i+2] != model.AccountCompanyPayable {
+			continue
+		}
+		amount := r.values[i+4].(float64)
+		if r.values[i+3] == model.PostingCredit {
+			balance += amount
+		} else {
+			balance -= amount
+		}
+	}
+	return model.RoundCents(balance)
+}
+
+func TestLedgerService_SplitRefundKeepsPayableSettled(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	ledgerService, mock := services.Ledger, services.Mock
+	recorder := &postingRecorder{}
+
+	payment := paidPayment("approved")
+	payment.SplitMarketplace = true
+
+	// venda: caixa, saldo da empresa e comissão; repasse via split: caixa e saldo da empresa
+	expectLedgerCount(mock, "sale", 0)
+	expectCommission(mock, "seller-token")
+	recorder.expect(mock, "sale", 3)
+	recorder.expect(mock, "split_settlement", 2)
+
+	// estorno parcial: só a comissão devolvida passa pela plataforma
+	expectLedgerCount(mock, "sale", 1)
+	expectLedgerRefunds(mock, 0)
+	expectCommission(mock, "seller-token")
+	recorder.expect(mock, "refund", 2)
+
+	if err := ledgerService.RecordSale(payment); err != nil {
+		t.Fatalf("RecordSale() unexpected error: %v", err)
+	}
+	payment.TransactionAmountRefunded = 30
+	if err := ledgerService.RecordRefund(payment); err != nil {
+		t.Fatalf("RecordRefund() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if balance := recorder.payableBalance(); balance != 0 {
+		t.Errorf("PayableBalance = %.2f, expected 0.00: the seller's share of a split refund is taken by Mercado Pago", balance)
+	}
+
+	refund := recorder.values[5*6:]
+	if refund[2] != model.AccountCash || refund[3] != model.PostingCredit || refund[4] != 3.0 ||
+		refund[8] != model.AccountPlatformRevenue || refund[9] != model.PostingDebit || refund[10] != 3.0 {
+		t.Errorf("Unexpected refund postings %v", refund)
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"image"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/storage"
+	"github.com/jampa_trip/pkg/util"
+)
+
+func TestMediaService_Get(t *testing.T) {
+	store := storage.NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	png := []byte("\x89PNG\r\n\x1a\n conteudo")
+	if err := store.Put(ctx, "images/1/foto.png", png, "image/png"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if err := store.Put(ctx, "disputes/1/manifesto.pdf", []byte("%PDF"), "application/pdf"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	db, mock := setupMockDBForImageService(t)
+	mediaService := &service.MediaService{Storage: store, ImageRepository: repository.ImageRepositoryNew(db)}
+
+	mock.ExpectQuery(`BOOL_AND`).WithArgs("images/1/foto.png").WillReturnRows(sqlmock.NewRows([]string{"private"}).AddRow(false))
+
+	media, err := mediaService.Get(ctx, "images/1/foto.png", "", "")
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	if media.ContentType != "image/png" {
+		t.Errorf("ContentType = %s, expected image/png", media.ContentType)
+	}
+
+	if len(media.ETag) < 3 || media.ETag[0] != '"' {
+		t.Errorf("ETag should be a quoted strong validator, got %s", media.ETag)
+	}
+
+	if !media.NotModified(`"outro", ` + media.ETag) {
+		t.Errorf("NotModified() should match the current ETag")
+	}
+
+	if media.NotModified(`"outro"`) {
+		t.Errorf("NotModified() should not match a different ETag")
+	}
+
+	if media.CacheControl(time.Now()) != service.MediaCacheControl {
+		t.Errorf("Public media should be cached as immutable, got %s", media.CacheControl(time.Now()))
+	}
+
+	mock.ExpectQuery(`BOOL_AND`).WithArgs("images/1/inexistente.png").WillReturnRows(sqlmock.NewRows([]string{"private"}).AddRow(false))
+
+	for _, key := range []string{"images/1/inexistente.png", "disputes/1/manifesto.pdf", "images/../disputes/1/manifesto.pdf"} {
+		_, err := mediaService.Get(ctx, key, "", "")
+		appErr, ok := err.(*util.AppError)
+		if !ok || appErr.StatusCode != http.StatusNotFound {
+			t.Errorf("Get(%q) error = %v, expected 404", key, err)
+		}
+	}
+}
+
+func TestMediaService_GetPrivate(t *testing.T) {
+	store := storage.NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "images/1/contrato.png", []byte("\x89PNG\r\n\x1a\n conteudo"), "image/png"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	db, mock := setupMockDBForImageService(t)
+	signer := &service.MediaSigner{Secret: []byte("segredo"), TTL: time.Hour}
+	mediaService := &service.MediaService{Storage: store, ImageRepository: repository.ImageRepositoryNew(db), Signer: signer}
+
+	// sem assinatura, o arquivo privado não é revelado
+	mock.ExpectQuery(`BOOL_AND`).WithArgs("images/1/contrato.png").WillReturnRows(sqlmock.NewRows([]string{"private"}).AddRow(true))
+
+	_, err := mediaService.Get(ctx, "images/1/contrato.png", "", "")
+	if appErr, ok := err.(*util.AppError); !ok || appErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get() without signature error = %v, expected 404", err)
+	}
+
+	now := time.Now()
+	expiresAt, signature := signer.Sign("images/1/contrato.png", now)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	if !expiresAt.After(now.Add(time.Hour-time.Second)) || expiresAt.After(now.Add(2*time.Hour)) {
+		t.Errorf("Expiration %v should be between one and two TTLs from now", expiresAt)
+	}
+	windowStart := now.Truncate(time.Hour)
+	first, firstSignature := signer.Sign("images/1/contrato.png", windowStart)
+	later, laterSignature := signer.Sign("images/1/contrato.png", windowStart.Add(30*time.Minute))
+	if !first.Equal(later) || firstSignature != laterSignature {
+		t.Errorf("URLs signed in the same window should be identical")
+	}
+
+	media, err := mediaService.Get(ctx, "images/1/contrato.png", expires, signature)
+	if err != nil {
+		t.Fatalf("Get() with signature unexpected error: %v", err)
+	}
+	if cacheControl := media.CacheControl(now); !strings.HasPrefix(cacheControl, "private, max-age=") {
+		t.Errorf("Signed media should only be cached privately, got %s", cacheControl)
+	}
+
+	invalid := []struct {
+		name      string
+		key       string
+		expires   string
+		signature string
+	}{
+		{"other key", "images/1/outra.png", expires, signature},
+		{"tampered expiration", "images/1/contrato.png", strconv.FormatInt(expiresAt.Unix()+3600, 10), signature},
+		{"bad signature", "images/1/contrato.png", expires, strings.Repeat("0", 64)},
+		{"missing signature", "images/1/contrato.png", expires, ""},
+	}
+
+	for _, tt := range invalid {
+		_, err := mediaService.Get(ctx, tt.key, tt.expires, tt.signature)
+		if appErr, ok := err.(*util.AppError); !ok || appErr.StatusCode != http.StatusForbidden {
+			t.Errorf("%s: Get() error = %v, expected 403", tt.name, err)
+		}
+	}
+
+	// URL assinada que já expirou
+	oldExpires, oldSignature := signer.Sign("images/1/contrato.png", now.Add(-3*time.Hour))
+	if _, ok := signer.Verify("images/1/contrato.png", strconv.FormatInt(oldExpires.Unix(), 10), oldSignature, now); ok {
+		t.Errorf("Verify() should reject expired URLs")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestImageService_PrivateImageURLsAreSigned(t *testing.T) {
+	db, mock := setupMockDBForImageService(t)
+	signer := &service.MediaSigner{Secret: []byte("segredo"), TTL: time.Hour}
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		Storage:           storage.NewLocalStore(t.TempDir()),
+		MediaBaseURL:      "/media",
+		ProcessAsync:      true,
+		Signer:            signer,
+	}
+
+	fileHeader := createMultipartImage(t, "contrato.png", image.NewRGBA(image.Rect(0, 0, 40, 30)))
+
+	mock.ExpectQuery(`FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("basico"))
+	// a mesma foto já enviada como pública não é reaproveitada: os arquivos dela são servidos sem assinatura
+	mock.ExpectQuery(`content_hash = \$2`).WithArgs(1, sqlmock.AnyArg(), "private").WillReturnRows(sqlmock.NewRows(imageColumns))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO images`).WithArgs(
+		1, nil, sqlmock.AnyArg(), "contrato.png", sqlmock.AnyArg(), "", sqlmock.AnyArg(), 40, 30, "png",
+		"", "", false, 0, nil, sqlmock.AnyArg(), "processing", "private", "", "", nil, nil, nil, nil, nil, nil, 0,
+	).WillReturnRows(sqlmock.NewRows([]string{"id", "uploaded_at", "updated_at"}).AddRow(30, time.Now(), time.Now()))
+	mock.ExpectQuery(`INSERT INTO image_jobs`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "status", "attempts", "run_after", "created_at"}).AddRow(1, "pending", 0, time.Now(), time.Now()),
+	)
+	mock.ExpectCommit()
+
+	request := &contract.UploadImagesRequest{Visibility: "private"}
+	response, err := imageService.UploadImages([]*multipart.FileHeader{fileHeader}, request, 1)
+	if err != nil {
+		t.Fatalf("UploadImages() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	uploaded := response.Images[0]
+	if uploaded.Visibility != "private" || uploaded.URLExpiresAt == nil {
+		t.Fatalf("Expected private image with URL expiration, got %+v", uploaded)
+	}
+
+	parsed, err := url.Parse(uploaded.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() unexpected error: %v", err)
+	}
+
+	key := strings.TrimPrefix(parsed.Path, "/media/")
+	query := parsed.Query()
+	if _, ok := signer.Verify(key, query.Get("expires"), query.Get("signature"), time.Now()); !ok {
+		t.Errorf("URL %s should carry a valid signature", uploaded.URL)
+	}
+	if query.Get("expires") != strconv.FormatInt(uploaded.URLExpiresAt.Unix(), 10) {
+		t.Errorf("url_expires_at should match the signed expiration")
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// creditCardRequest - pagamento com os dados do cartão da reserva 11, sem valor: o valor é sempre calculado no servidor
+func creditCardRequest(clienteID int) *contract.CreateCreditCardPaymentRequest {
+	request := &contract.CreateCreditCardPaymentRequest{
+		ClienteID:       clienteID,
+		ReservaID:       11,
+		Token:           "card-token",
+		Installments:    1,
+		PaymentMethodID: "visa",
+	}
+	request.Payer.Email = "cliente@example.com"
+	return request
+}
+
+func TestPagamentoService_ReservationChargeRejections(t *testing.T) {
+	tests := []struct {
+		name           string
+		clienteID      int
+		expect         func(mock sqlmock.Sqlmock)
+		expectedStatus int
+	}{
+		{
+			name:           "unauthenticated client",
+			clienteID:      0,
+			expect:         func(mock sqlmock.Sqlmock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "reservation not found",
+			clienteID: 4,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM "reservas"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "reservation of another client",
+			clienteID: 4,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 5, 0)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "reservation with a payment in progress",
+			clienteID: 4,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 4, 30)
+				mock.ExpectQuery(`FROM "pagamentos"`).WithArgs(30, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(30, "in_process"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "reservation already paid",
+			clienteID: 4,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 4, 30)
+				mock.ExpectQuery(`FROM "pagamentos"`).WithArgs(30, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(30, "approved"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "tour of another company",
+			clienteID: 4,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 4, 0)
+				expectTour(mock, 8, 45)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "tour without price",
+			clienteID: 4,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectReservation(mock, 4, 0)
+				expectTour(mock, 3, 0)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			pagamentoService, mock := services.Pagamento, services.Mock
+
+			tt.expect(mock)
+
+			_, err := pagamentoService.CreateCreditCardPayment(context.Background(), creditCardRequest(tt.clienteID))
+
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected %d error, got %v", tt.expectedStatus, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPagamentoService_ReservationChargeUsesTourPrice(t *testing.T) {
+	var charged float64
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			TransactionAmount float64 `json:"transaction_amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid payment request: %v", err)
+		}
+		charged = body.TransactionAmount
+
+		w.Write([]byte(`{"id": 1001, "status": "in_process", "status_detail": "pending_contingency", "transaction_amount": 90}`))
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	// a reserva tem um pagamento anterior recusado, que não impede uma nova tentativa
+	expectReservation(mock, 4, 30)
+	mock.ExpectQuery(`FROM "pagamentos"`).WithArgs(30, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(30, "rejected"))
+	expectTour(mock, 3, 45)
+	expectCommission(mock, "")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(31))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "reservas"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	response, err := pagamentoService.CreateCreditCardPayment(context.Background(), creditCardRequest(4))
+	if err != nil {
+		t.Fatalf("CreateCreditCardPayment() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	// 2 pessoas a R$ 45,00
+	if charged != 90 {
+		t.Errorf("Charged %.2f, expected 90.00 from the tour price", charged)
+	}
+	if response.Pagamento.ID != 31 || response.Pagamento.TaxaPlataforma != 9 {
+		t.Errorf("Unexpected payment %+v", response.Pagamento)
+	}
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/model"
+)
+
+// pendingPixPayment - PIX pendente de R$ 90,00 do cliente 4 à empresa 3
+func pendingPixPayment() *model.Pagamento {
+	return &model.Pagamento{
+		ID:                   7,
+		ClienteID:            4,
+		EmpresaID:            3,
+		MercadoPagoPaymentID: "1001",
+		Status:               "pending",
+		StatusDetail:         "pending_waiting_transfer",
+		MetodoPagamento:      "pix",
+		Valor:                90,
+		Moeda:                "BRL",
+	}
+}
+
+// paidPayment - pagamento de R$ 90,00 com R$ 9,00 de comissão, do cliente 4 à empresa 3, no status informado
+func paidPayment(status string) *model.Pagamento {
+	return &model.Pagamento{
+		ID:                   7,
+		ClienteID:            4,
+		EmpresaID:            3,
+		MercadoPagoPaymentID: "1001",
+		Status:               status,
+		Valor:                90,
+		TaxaPlataforma:       9,
+		Moeda:                "BRL",
+	}
+}
+
+// expectPaymentSave - gravação do pagamento alterado
+func expectPaymentSave(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "pagamentos"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+// expectNoDispute - o pagamento não tem disputa em aberto
+func expectNoDispute(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM "disputes"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func TestPagamentoService_SyncWithMercadoPago(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unchanged payment is not saved", func(t *testing.T) {
+		services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id": 1001, "status": "pending", "status_detail": "pending_waiting_transfer"}`))
+		})
+		pagamentoService, mock := services.Pagamento, services.Mock
+
+		payment := pendingPixPayment()
+		_, updated, err := pagamentoService.SyncWithMercadoPago(ctx, payment)
+		if err != nil || updated {
+			t.Fatalf("SyncWithMercadoPago() = %v, %v; expected no update", updated, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+
+	t.Run("approval records the sale and the PIX end-to-end ID", func(t *testing.T) {
+		services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/v1/payments/1001" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			w.Write([]byte(`{"id": 1001, "status": "approved", "status_detail": "accredited", "captured": true,
+				"point_of_interaction": {"transaction_data": {"e2e_id": "E12345678202401151200abcdef"}}}`))
+		})
+		pagamentoService, mock := services.Pagamento, services.Mock
+
+		expectPaymentSave(mock)
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "ledger_entries"`).WithArgs(7, "sale").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		expectCommission(mock, "")
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "ledger_entries"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO "ledger_postings"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+		mock.ExpectCommit()
+		expectNoDispute(mock)
+
+		payment := pendingPixPayment()
+		remote, updated, err := pagamentoService.SyncWithMercadoPago(ctx, payment)
+		if err != nil || !updated || remote == nil {
+			t.Fatalf("SyncWithMercadoPago() = %v, %v; expected an update", updated, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+
+		if payment.Status != "approved" || payment.StatusDetail != "accredited" || !payment.Captured {
+			t.Errorf("Remote status not applied: %s/%s captured=%v", payment.Status, payment.StatusDetail, payment.Captured)
+		}
+		if payment.MomentoAprovacao == nil || payment.MomentoCaptura == nil {
+			t.Errorf("Approval and capture times should be set")
+		}
+		if payment.PixEndToEndID != "E12345678202401151200abcdef" {
+			t.Errorf("PixEndToEndID = %q, expected the remote e2e_id", payment.PixEndToEndID)
+		}
+	})
+
+	t.Run("rejection releases the reservation", func(t *testing.T) {
+		services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id": 1001, "status": "rejected", "status_detail": "cc_rejected_other_reason"}`))
+		})
+		pagamentoService, mock := services.Pagamento, services.Mock
+
+		expectPaymentSave(mock)
+		expectNoDispute(mock)
+		mock.ExpectQuery(`FROM "reservas"`).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+			AddRow(11, "pendente").
+			AddRow(12, "cancelada"))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "reservas"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		payment := pendingPixPayment()
+		_, updated, err := pagamentoService.SyncWithMercadoPago(ctx, payment)
+		if err != nil || !updated {
+			t.Fatalf("SyncWithMercadoPago() = %v, %v; expected an update", updated, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+		if payment.MomentoAprovacao != nil {
+			t.Errorf("Rejected payment should not have an approval time")
+		}
+	})
+}
+
+func TestPagamentoService_SyncRecordsRefunds(t *testing.T) {
+	tests := []struct {
+		name          string
+		remote        string
+		refunded      float64
+		recorded      float64
+		expectedGross float64
+		expectedFee   float64
+		statusChanges bool
+	}{
+		{
+			name:          "partial refund of an approved payment",
+			remote:        `{"id": 1001, "status": "approved", "status_detail": "partially_refunded", "captured": true, "transaction_amount_refunded": 30}`,
+			expectedGross: -30,
+			expectedFee:   -3,
+		},
+		{
+			name:          "second partial refund records only the increase",
+			remote:        `{"id": 1001, "status": "approved", "status_detail": "partially_refunded", "captured": true, "transaction_amount_refunded": 50}`,
+			refunded:      30,
+			recorded:      -30,
+			expectedGross: -20,
+			expectedFee:   -2,
+		},
+		{
+			name:          "full refund after a partial one",
+			remote:        `{"id": 1001, "status": "refunded", "status_detail": "refunded", "captured": true, "transaction_amount_refunded": 90}`,
+			refunded:      30,
+			recorded:      -30,
+			expectedGross: -60,
+			expectedFee:   -6,
+			statusChanges: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.remote))
+			})
+			pagamentoService, mock := services.Pagamento, services.Mock
+
+			expectPaymentSave(mock)
+			expectLedgerCount(mock, "sale", 1)
+			expectLedgerRefunds(mock, tt.recorded)
+			expectCommission(mock, "")
+			expectLedgerEntry(mock, "refund", tt.expectedGross, tt.expectedFee)
+			if tt.statusChanges {
+				expectNoDispute(mock)
+			}
+
+			payment := paidPayment("approved")
+			payment.Captured = true
+			payment.TransactionAmountRefunded = tt.refunded
+
+			_, updated, err := pagamentoService.SyncWithMercadoPago(context.Background(), payment)
+			if err != nil || !updated {
+				t.Fatalf("SyncWithMercadoPago() = %v, %v; expected an update", updated, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPagamentoService_ReconcilePayments(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/payments/1001":
+			w.Write([]byte(`{"id": 1001, "status": "pending", "status_detail": "pending_waiting_transfer"}`))
+		case "/v1/payments/1002":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/v1/payments/1003":
+			w.Write([]byte(`{"id": 1003, "status": "rejected", "status_detail": "cc_rejected_other_reason"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	// páginas de dois pagamentos: a segunda começa depois do último ID da primeira
+	paymentColumns := []string{"id", "empresa_id", "mercado_pago_payment_id", "status", "status_detail"}
+	mock.ExpectQuery(`FROM "pagamentos" WHERE .* AND id > \$\d+ ORDER BY id ASC LIMIT \$\d+`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 2).
+		WillReturnRows(sqlmock.NewRows(paymentColumns).
+			AddRow(1, 3, "1001", "pending", "pending_waiting_transfer").
+			AddRow(2, 3, "1002", "pending", "pending_waiting_transfer"))
+	mock.ExpectQuery(`FROM "pagamentos"`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 2, 2).
+		WillReturnRows(sqlmock.NewRows(paymentColumns).
+			AddRow(3, 3, "1003", "in_process", "pending_review_manual"))
+	expectPaymentSave(mock)
+	expectNoDispute(mock)
+	mock.ExpectQuery(`FROM "reservas"`).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+
+	report, err := pagamentoService.ReconcilePayments(context.Background(), time.Hour, 2)
+	if err != nil {
+		t.Fatalf("ReconcilePayments() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if report.Scanned != 3 || report.Updated != 1 || report.Failed != 1 {
+		t.Errorf("Report = scanned %d, updated %d, failed %d; expected 3, 1, 1", report.Scanned, report.Updated, report.Failed)
+	}
+	if len(report.Discrepancies) != 2 {
+		t.Fatalf("Expected 2 discrepancies, got %+v", report.Discrepancies)
+	}
+
+	failed, updated := report.Discrepancies[0], report.Discrepancies[1]
+	if failed.PagamentoID != 2 || failed.Error == "" {
+		t.Errorf("Expected the failed sync of payment 2, got %+v", failed)
+	}
+	if updated.PagamentoID != 3 || updated.LocalStatus != "in_process" || updated.RemoteStatus != "rejected" {
+		t.Errorf("Expected payment 3 moved from in_process to rejected, got %+v", updated)
+	}
+}
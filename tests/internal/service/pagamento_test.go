@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/util"
+)
+
+func TestPagamentoService_ExpirePIXPaymentsKeepsPaymentsOnRemoteFailure(t *testing.T) {
+	var cancelled []string
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/payments/1001":
+			// a consulta falha: o PIX pode ter sido pago
+			w.WriteHeader(http.StatusBadGateway)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/payments/1002":
+			w.Write([]byte(`{"id": 1002, "status": "pending", "status_detail": "pending_waiting_transfer"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/payments/1002":
+			// o cancelamento falha
+			cancelled = append(cancelled, "1002")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "payment cannot be cancelled"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	mock.ExpectQuery(`FROM "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{"id", "mercado_pago_payment_id", "status", "status_detail", "metodo_pagamento"}).
+		AddRow(1, "1001", "pending", "pending_waiting_transfer", "pix").
+		AddRow(2, "1002", "pending", "pending_waiting_transfer", "pix"))
+
+	// nenhum pagamento pode ser atualizado nem ter a reserva liberada
+	expired, err := pagamentoService.ExpirePIXPayments(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ExpirePIXPayments() unexpected error: %v", err)
+	}
+	if expired != 0 {
+		t.Errorf("ExpirePIXPayments() = %d, expected 0", expired)
+	}
+	if len(cancelled) != 1 {
+		t.Errorf("Expected one remote cancellation attempt, got %v", cancelled)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+// expectReservation - reserva pendente de 2 pessoas do cliente 4 no passeio 9 da empresa 3, sem pagamento
+func expectReservation(mock sqlmock.Sqlmock, clienteID, pagamentoID int) {
+	mock.ExpectQuery(`FROM "reservas"`).WillReturnRows(sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "tour_id", "pagamento_id", "status", "quantidade_pessoas"}).
+		AddRow(11, clienteID, 3, 9, pagamentoID, "pendente", 2))
+	mock.ExpectQuery(`FROM "clients"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(clienteID))
+	mock.ExpectQuery(`FROM "companies"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	if pagamentoID > 0 {
+		mock.ExpectQuery(`FROM "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(pagamentoID))
+	}
+}
+
+// expectTour - passeio 9 da empresa informada com o preço por pessoa informado
+func expectTour(mock sqlmock.Sqlmock, companyID int, price float64) {
+	mock.ExpectQuery(`FROM tours t`).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "name", "dates", "departure_time", "arrival_time", "max_people", "description", "price", "created_at", "updated_at", "company_name"}).
+		AddRow(9, companyID, "Passeio de Barco", "{2024-01-15}", "08:00", "12:00", 10, "", price, time.Now(), time.Now(), "Empresa"))
+}
+
+// expectCommission - comissão de 10% da empresa 3, vinculada ao marketplace quando sellerToken é informado
+func expectCommission(mock sqlmock.Sqlmock, sellerToken string) {
+	mock.ExpectQuery(`FROM "company_commissions"`).WillReturnRows(sqlmock.NewRows([]string{"company_id", "rate", "mercado_pago_seller_token"}).
+		AddRow(3, 10.0, sellerToken))
+}
+
+func TestPagamentoService_CreateCreditCardPaymentRejectsSavedCardWithSplit(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	expectReservation(mock, 4, 0)
+	expectTour(mock, 3, 45)
+	expectCommission(mock, "seller-token")
+
+	_, err := pagamentoService.CreateCreditCardPayment(context.Background(), &contract.CreateCreditCardPaymentRequest{
+		ClienteID:    4,
+		ReservaID:    11,
+		Token:        "cvv-token",
+		CardID:       "card-1",
+		Installments: 1,
+	})
+
+	appErr, ok := err.(*util.AppError)
+	if !ok || appErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422 error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/util"
+)
+
+// expectReceiptPayment - pagamento aprovado do carrinho, com o ID alfanumérico de uma order, do cliente 4 à empresa 3
+func expectReceiptPayment(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`mercado_pago_payment_id = \$1`).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "cliente_id", "empresa_id", "mercado_pago_payment_id", "status", "metodo_pagamento", "valor", "momento_aprovacao",
+	}).AddRow(21, 4, 3, "PAY01JABC", "approved", "pix", 3600.0, time.Now()))
+}
+
+func TestPagamentoService_ReceiptAcceptsOrderPaymentIDs(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	expectReceiptPayment(mock)
+	mock.ExpectQuery(`FROM companies`).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "name", "email", "password", "cnpj", "phone", "address", "created_at", "updated_at",
+	}).AddRow(3, "Empresa", "empresa@example.com", "", "12345678000195", "", "Rua A", time.Now(), time.Now()))
+	mock.ExpectQuery(`FROM clients`).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "name", "email", "password", "cpf", "phone", "birth_date", "created_at", "updated_at",
+	}).AddRow(4, "Cliente", "cliente@example.com", "", "12345678909", "", time.Now(), time.Now(), time.Now()))
+
+	// carrinho com passeios demais para uma única página
+	reservas := sqlmock.NewRows([]string{"id", "cliente_id", "empresa_id", "tour_id", "pagamento_id", "status", "data_passeio", "quantidade_pessoas", "valor_total"})
+	for i := 1; i <= 40; i++ {
+		reservas.AddRow(i, 4, 3, 9, 21, "confirmada", time.Now(), 2, 90.0)
+	}
+	mock.ExpectQuery(`FROM "reservas"`).WithArgs(21).WillReturnRows(reservas)
+	for i := 1; i <= 40; i++ {
+		expectTour(mock, 3, 45)
+	}
+
+	data, filename, err := pagamentoService.Receipt(context.Background(), "PAY01JABC", 4, "client")
+	if err != nil {
+		t.Fatalf("Receipt() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if filename != "recibo-REC-000021.pdf" {
+		t.Errorf("filename = %s, expected recibo-REC-000021.pdf", filename)
+	}
+
+	match := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(data)
+	if match == nil {
+		t.Fatalf("Page tree not found in the PDF")
+	}
+	if pages, _ := strconv.Atoi(string(match[1])); pages < 2 {
+		t.Errorf("Expected the receipt to continue on a new page, got %d page(s)", pages)
+	}
+}
+
+func TestPagamentoService_ReceiptRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name           string
+		paymentID      string
+		userID         int
+		userType       string
+		expectPayment  bool
+		expectedStatus int
+	}{
+		{name: "empty payment ID", paymentID: "", userID: 4, userType: "client", expectedStatus: http.StatusBadRequest},
+		{name: "another client", paymentID: "PAY01JABC", userID: 5, userType: "client", expectPayment: true, expectedStatus: http.StatusForbidden},
+		{name: "client with the company ID", paymentID: "PAY01JABC", userID: 3, userType: "client", expectPayment: true, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			pagamentoService, mock := services.Pagamento, services.Mock
+
+			if tt.expectPayment {
+				expectReceiptPayment(mock)
+			}
+
+			_, _, err := pagamentoService.Receipt(context.Background(), tt.paymentID, tt.userID, tt.userType)
+
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected %d error, got %v", tt.expectedStatus, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPagamentoService_ListInvoices(t *testing.T) {
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`FROM "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "cliente_id", "empresa_id", "mercado_pago_payment_id", "status", "metodo_pagamento", "valor",
+		"taxa_plataforma", "transaction_amount_refunded", "momento_aprovacao",
+	}).AddRow(21, 4, 3, "PAY01JABC", "approved", "pix", 100.0, 10.0, 20.0, time.Now()))
+	mock.ExpectQuery(`FROM "clients"`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "Cliente"))
+
+	response, err := pagamentoService.ListInvoices(&contract.ListInvoicesRequest{CompanyID: 3, Period: "2024-01", Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListInvoices() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if len(response.Invoices) != 1 {
+		t.Fatalf("Expected 1 invoice, got %d", len(response.Invoices))
+	}
+	invoice := response.Invoices[0]
+	if invoice.ReceiptURL != "/payments/PAY01JABC/receipt.pdf" {
+		t.Errorf("ReceiptURL = %s, expected /payments/PAY01JABC/receipt.pdf", invoice.ReceiptURL)
+	}
+	if invoice.ClienteNome != "Cliente" || invoice.ValorLiquido != 70 {
+		t.Errorf("Unexpected invoice %+v", invoice)
+	}
+
+	// o período fora do formato AAAA-MM é recusado antes de consultar o banco
+	_, err = pagamentoService.ListInvoices(&contract.ListInvoicesRequest{CompanyID: 3, Period: "2024-13", Page: 1, Limit: 10})
+	appErr, ok := err.(*util.AppError)
+	if !ok || appErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 error, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/service"
+)
+
+// testSellerTokenCipher - cifra dos access tokens com uma chave fixa de 32 bytes
+func testSellerTokenCipher() *service.SellerTokenCipher {
+	return &service.SellerTokenCipher{Key: bytes.Repeat([]byte{7}, 32)}
+}
+
+func TestSellerTokenCipher(t *testing.T) {
+	cipher := testSellerTokenCipher()
+
+	first, err := cipher.Encrypt("APP_USR-seller")
+	if err != nil {
+		t.Fatalf("Encrypt() unexpected error: %v", err)
+	}
+	second, _ := cipher.Encrypt("APP_USR-seller")
+
+	if !strings.HasPrefix(first, "enc:v1:") || strings.Contains(first, "APP_USR") {
+		t.Errorf("Encrypted token %q should be prefixed and not contain the plaintext", first)
+	}
+	if first == second {
+		t.Errorf("Each encryption should use a new nonce")
+	}
+
+	if token, err := cipher.Decrypt(first); err != nil || token != "APP_USR-seller" {
+		t.Errorf("Decrypt() = %q, %v; expected the original token", token, err)
+	}
+
+	// tokens gravados antes da cifragem continuam válidos
+	if token, err := cipher.Decrypt("APP_USR-legacy"); err != nil || token != "APP_USR-legacy" {
+		t.Errorf("Decrypt() of a legacy token = %q, %v", token, err)
+	}
+
+	other := &service.SellerTokenCipher{Key: bytes.Repeat([]byte{8}, 32)}
+	if _, err := other.Decrypt(first); err == nil {
+		t.Errorf("Decrypt() with another key should fail")
+	}
+	if _, err := cipher.Decrypt(first[:len(first)-4]); err == nil {
+		t.Errorf("Decrypt() of a truncated token should fail")
+	}
+}
+
+func TestLedgerService_SetCommissionEncryptsSellerToken(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	ledgerService, mock := services.Ledger, services.Mock
+
+	stored := &capturedArg{}
+	mock.ExpectQuery(`FROM "company_commissions"`).WillReturnRows(sqlmock.NewRows([]string{"company_id", "rate", "mercado_pago_seller_token", "created_at", "updated_at"}).
+		AddRow(3, 10.0, nil, time.Now(), time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "company_commissions"`).WithArgs(12.5, stored, sqlmock.AnyArg(), sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	response, err := ledgerService.SetCommission(3, 12.5, "APP_USR-seller")
+	if err != nil {
+		t.Fatalf("SetCommission() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if !response.HasSplit {
+		t.Errorf("Company should have the split enabled")
+	}
+	if strings.Contains(stored.value, "APP_USR") {
+		t.Fatalf("Seller token stored in plaintext: %q", stored.value)
+	}
+	if token, err := testSellerTokenCipher().Decrypt(stored.value); err != nil || token != "APP_USR-seller" {
+		t.Errorf("Stored token decrypts to %q, %v", token, err)
+	}
+}
+
+func TestPagamentoService_SplitPaymentUsesDecryptedSellerToken(t *testing.T) {
+	var authorization string
+	services := setupMockDBForServices(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"id": 1001, "status": "in_process", "status_detail": "pending_contingency", "transaction_amount": 90}`))
+	})
+	pagamentoService, mock := services.Pagamento, services.Mock
+
+	encrypted, err := testSellerTokenCipher().Encrypt("APP_USR-seller")
+	if err != nil {
+		t.Fatalf("Encrypt() unexpected error: %v", err)
+	}
+
+	expectReservation(mock, 4, 0)
+	expectTour(mock, 3, 45)
+	expectCommission(mock, encrypted)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "pagamentos"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(31))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "reservas"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if _, err := pagamentoService.CreateCreditCardPayment(context.Background(), creditCardRequest(4)); err != nil {
+		t.Fatalf("CreateCreditCardPayment() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	if authorization != "Bearer APP_USR-seller" {
+		t.Errorf("Authorization = %q, expected the decrypted seller token", authorization)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/repository"
+	"github.com/jampa_trip/internal/service"
+	"github.com/jampa_trip/pkg/mercadopago"
+	"github.com/jampa_trip/pkg/storage"
+	"gorm.io/gorm"
+)
+
+// testServices - services ligados ao mesmo banco simulado, a um armazenamento local temporário e ao Mercado Pago de teste
+type testServices struct {
+	DB        *gorm.DB
+	Mock      sqlmock.Sqlmock
+	Store     *storage.LocalStore
+	StoreDir  string
+	Ledger    *service.LedgerService
+	Dispute   *service.DisputeService
+	Pagamento *service.PagamentoService
+	Image     *service.ImageService
+	Tour      *service.TourService
+}
+
+// setupMockDBForServices - cria os services com o banco simulado; handler responde às chamadas ao Mercado Pago,
+// feitas sem retentativas nem circuit breaker
+func setupMockDBForServices(t *testing.T, handler http.HandlerFunc) *testServices {
+	db, mock := setupMockDBForService(t)
+	dir := t.TempDir()
+	store := storage.NewLocalStore(dir)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	mpClient := mercadopago.NewClientWithOptions("platform-token", server.URL, mercadopago.Options{})
+
+	ledgerService := &service.LedgerService{
+		LedgerRepository:            repository.LedgerRepositoryNew(db),
+		CompanyCommissionRepository: repository.CompanyCommissionRepositoryNew(db),
+		SellerTokenCipher:           testSellerTokenCipher(),
+		DefaultRate:                 service.DefaultCommissionRate,
+	}
+
+	disputeService := &service.DisputeService{
+		DisputeRepository:   repository.DisputeRepositoryNew(db),
+		PagamentoRepository: repository.PagamentoRepositoryNew(db),
+		LedgerService:       ledgerService,
+		NotificationService: service.NotificationServiceNew(db),
+		MPClient:            mpClient,
+		Storage:             store,
+	}
+
+	imageService := &service.ImageService{
+		ImageRepository:   repository.ImageRepositoryNew(db),
+		CompanyRepository: repository.CompanyRepositoryNew(db),
+		TourRepository:    repository.TourRepositoryNew(db),
+		Storage:           store,
+		MediaBaseURL:      "/media",
+	}
+
+	return &testServices{
+		DB:       db,
+		Mock:     mock,
+		Store:    store,
+		StoreDir: dir,
+		Ledger:   ledgerService,
+		Dispute:  disputeService,
+		Pagamento: &service.PagamentoService{
+			PagamentoRepository: repository.PagamentoRepositoryNew(db),
+			ReservaRepository:   repository.ReservaRepositoryNew(db),
+			TourRepository:      repository.TourRepositoryNew(db),
+			CompanyRepository:   repository.CompanyRepositoryNew(db),
+			ClientRepository:    repository.ClientRepositoryNew(db),
+			LedgerService:       ledgerService,
+			DisputeService:      disputeService,
+			MPClient:            mpClient,
+		},
+		Image: imageService,
+		Tour: &service.TourService{
+			TourRepository: repository.TourRepositoryNew(db),
+			ImageService:   imageService,
+		},
+	}
+}
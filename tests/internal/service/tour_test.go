@@ -0,0 +1,81 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jampa_trip/internal/contract"
+	"github.com/jampa_trip/pkg/util"
+)
+
+func TestTourService_CreateRejectsImagesFromAnotherCompany(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	tourService, mock := services.Tour, services.Mock
+
+	// a imagem 9 não pertence à empresa 3
+	mock.ExpectQuery(`FROM images`).WithArgs(sqlmock.AnyArg(), 3).WillReturnRows(sqlmock.NewRows(imageColumns).
+		AddRow(8, 3, nil, "a.jpg", "a.jpg", "images/3/a.jpg", "", 1024, 40, 30, "jpg", "", "", false, 0, time.Now(), time.Now(), nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil))
+
+	request := &contract.CreateTourRequest{
+		Name:          "Passeio de Barco",
+		Dates:         []string{"2024-01-15"},
+		DepartureTime: "08:00",
+		ArrivalTime:   "12:00",
+		MaxPeople:     10,
+		ImageIDs:      []int{8, 9},
+		Price:         90,
+	}
+
+	_, err := tourService.Create(request, 3)
+
+	appErr, ok := err.(*util.AppError)
+	if !ok || appErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected 403 error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestTourService_ListReturnsTourImagesPrimaryFirst(t *testing.T) {
+	services := setupMockDBForServices(t, nil)
+	tourService, mock := services.Tour, services.Mock
+	now := time.Now()
+
+	tourColumns := []string{"id", "company_id", "name", "dates", "departure_time", "arrival_time", "max_people", "description", "price", "created_at", "updated_at", "company_name"}
+	mock.ExpectQuery(`FROM tours t`).WillReturnRows(sqlmock.NewRows(tourColumns).
+		AddRow(7, 3, "Passeio de Barco", "{2024-01-15}", "08:00", "12:00", 10, "", 90.0, now, now, "Empresa").
+		AddRow(8, 3, "Passeio a Pé", "{2024-01-16}", "09:00", "11:00", 10, "", 50.0, now, now, "Empresa"))
+	mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	// a listagem pública não inclui imagens privadas, em processamento ou enviadas por outra empresa
+	mock.ExpectQuery(`i\.user_id = t\.company_id WHERE i\.tour_id = ANY`).WithArgs(sqlmock.AnyArg(), false).WillReturnRows(sqlmock.NewRows(imageColumns).
+		AddRow(12, 3, 7, "capa.jpg", "capa.jpg", "images/3/capa.jpg", "images/3/thumb_capa.jpg", 1024, 40, 30, "jpg", "", "", true, 1, now, now, nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil).
+		AddRow(11, 3, 7, "praia.jpg", "praia.jpg", "images/3/praia.jpg", "", 1024, 40, 30, "jpg", "", "", false, 0, now, now, nil, "ready", "public", "", "", nil, nil, nil, nil, nil, nil))
+	mock.ExpectQuery(`FROM image_renditions`).WillReturnRows(sqlmock.NewRows([]string{"id", "image_id", "width", "height", "format", "storage_key", "size", "created_at"}))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`FROM tours t`).WillReturnRows(sqlmock.NewRows(tourColumns).
+			AddRow(7, 3, "Passeio de Barco", "{2024-01-15}", "08:00", "12:00", 10, "", 90.0, now, now, "Empresa"))
+	}
+
+	response, err := tourService.List(&contract.ListToursRequest{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	images := response.Tours[0].Images
+	if len(images) != 2 || images[0].ID != 12 || !images[0].IsPrimary || images[1].ID != 11 {
+		t.Fatalf("Expected images 12 (primary) and 11, got %+v", images)
+	}
+	if images[0].URL != "/media/images/3/capa.jpg" {
+		t.Errorf("Unexpected image URL %s", images[0].URL)
+	}
+
+	if response.Tours[1].Images == nil || len(response.Tours[1].Images) != 0 {
+		t.Errorf("Expected empty image list for tour without images, got %+v", response.Tours[1].Images)
+	}
+}
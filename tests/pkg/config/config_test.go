@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jampa_trip/pkg/config"
+)
+
+// validConfig - configuração mínima aceita, no ambiente informado do Mercado Pago
+func validConfig(environment string) config.Config {
+	return config.Config{
+		HTTPServerReadTimeout:     "10",
+		HTTPServerWriteTimeout:    "10",
+		HTTPServerIdleTimeout:     "60",
+		HTTPServerPort:            "8080",
+		DatabaseHost:              "localhost",
+		DatabasePort:              "5432",
+		DatabaseName:              "jampa_trip",
+		DatabaseUser:              "postgres",
+		DatabasePassword:          "postgres",
+		MercadoPagoAccessToken:    "access-token",
+		MercadoPagoPublicKey:      "public-key",
+		MercadoPagoEnvironment:    environment,
+		MercadoPagoBaseURL:        "https://api.mercadopago.com",
+		JWTSecret:                 "jwt-secret",
+		JWTAccessTokenExpiration:  "15m",
+		JWTRefreshTokenExpiration: "168h",
+		RedisHost:                 "localhost",
+		RedisPort:                 "6379",
+	}
+}
+
+func TestConfig_ValidateWebhookSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		secret      string
+		expectErr   bool
+	}{
+		{name: "Sandbox without secret", environment: "sandbox", secret: "", expectErr: false},
+		{name: "Production with secret", environment: "production", secret: "webhook-secret", expectErr: false},
+		{name: "Production without secret", environment: "production", secret: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(tt.environment)
+			cfg.MercadoPagoWebhookSecret = tt.secret
+
+			err := cfg.Validate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Validate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/jampa_trip/pkg/imagemeta"
+	"golang.org/x/image/webp"
+)
+
+// buildTIFF - monta um bloco EXIF little-endian com Orientation, DateTimeOriginal e OffsetTimeOriginal
+func buildTIFF(orientation uint16) []byte {
+	le := binary.LittleEndian
+	buf := make([]byte, 68)
+
+	copy(buf, "II")
+	le.PutUint16(buf[2:], 42)
+	le.PutUint32(buf[4:], 8)
+
+	entry := func(pos int, tag, typ uint16, count, value uint32) {
+		le.PutUint16(buf[pos:], tag)
+		le.PutUint16(buf[pos+2:], typ)
+		le.PutUint32(buf[pos+4:], count)
+		le.PutUint32(buf[pos+8:], value)
+	}
+
+	le.PutUint16(buf[8:], 2)
+	entry(10, 0x0112, 3, 1, uint32(orientation))
+	entry(22, 0x8769, 4, 1, 38)
+
+	le.PutUint16(buf[38:], 2)
+	entry(40, 0x9003, 2, 20, 68)
+	entry(52, 0x9011, 2, 7, 88)
+
+	buf = append(buf, "2024:01:15 14:30:22\x00"...)
+	return append(buf, "-03:00\x00"...)
+}
+
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// createJPEGWithMetadata - JPEG 4x2 com EXIF, XMP, perfil ICC, comentário e dados após o EOI
+func createJPEGWithMetadata(t *testing.T, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 60), uint8(y * 120), 0, 255})
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.Write(encoded.Bytes()[:2])
+	out.Write(jpegSegment(0xE1, append([]byte("Exif\x00\x00"), buildTIFF(orientation)...)))
+	out.Write(jpegSegment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>GPS</x:xmpmeta>")))
+	out.Write(jpegSegment(0xE2, []byte("ICC_PROFILE\x00\x01\x01perfil")))
+	out.Write(jpegSegment(0xFE, []byte("Canon EOS serial 123456")))
+	out.Write(encoded.Bytes()[2:])
+	out.WriteString("motion-photo-trailer")
+
+	return out.Bytes()
+}
+
+func TestRead_JPEG(t *testing.T) {
+	meta := imagemeta.Read(createJPEGWithMetadata(t, 6), "jpg")
+
+	if meta.Orientation != 6 {
+		t.Errorf("Orientation = %d, expected 6", meta.Orientation)
+	}
+
+	expected := time.Date(2024, 1, 15, 17, 30, 22, 0, time.UTC)
+	if meta.CapturedAt == nil || !meta.CapturedAt.Equal(expected) {
+		t.Errorf("CapturedAt = %v, expected %v", meta.CapturedAt, expected)
+	}
+}
+
+func TestRead_WithoutMetadata(t *testing.T) {
+	var encoded bytes.Buffer
+	jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 2, 2)), nil)
+
+	meta := imagemeta.Read(encoded.Bytes(), "jpg")
+	if meta.Orientation != 1 || meta.CapturedAt != nil {
+		t.Errorf("Read() = %+v, expected defaults", meta)
+	}
+
+	if meta := imagemeta.Read([]byte("\xFF\xD8\xFF\xE1\x00"), "jpg"); meta.Orientation != 1 {
+		t.Errorf("Read() of truncated file = %+v, expected defaults", meta)
+	}
+}
+
+func TestStrip_JPEG(t *testing.T) {
+	original := createJPEGWithMetadata(t, 6)
+
+	stripped, err := imagemeta.Strip(original, "jpg")
+	if err != nil {
+		t.Fatalf("Strip() unexpected error: %v", err)
+	}
+
+	for _, leaked := range []string{"Exif", "xmpmeta", "Canon", "motion-photo-trailer"} {
+		if bytes.Contains(stripped, []byte(leaked)) {
+			t.Errorf("Stripped JPEG still contains %q", leaked)
+		}
+	}
+
+	if !bytes.Contains(stripped, []byte("ICC_PROFILE")) {
+		t.Errorf("Stripped JPEG should keep the ICC profile")
+	}
+
+	if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("Stripped JPEG should still decode: %v", err)
+	}
+
+	if meta := imagemeta.Read(stripped, "jpg"); meta.Orientation != 1 || meta.CapturedAt != nil {
+		t.Errorf("Read() after Strip() = %+v, expected no metadata", meta)
+	}
+
+	if _, err := imagemeta.Strip([]byte("not a jpeg"), "jpg"); err == nil {
+		t.Errorf("Strip() of invalid JPEG should fail")
+	}
+}
+
+func TestCopyJPEGProfile(t *testing.T) {
+	var encoded bytes.Buffer
+	jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 2, 2)), nil)
+
+	withProfile := imagemeta.CopyJPEGProfile(encoded.Bytes(), createJPEGWithMetadata(t, 1))
+
+	if !bytes.Contains(withProfile, []byte("ICC_PROFILE")) {
+		t.Errorf("CopyJPEGProfile() should copy the ICC profile")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(withProfile)); err != nil {
+		t.Errorf("JPEG with copied profile should decode: %v", err)
+	}
+}
+
+func pngChunk(kind string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], kind)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestStrip_PNG(t *testing.T) {
+	var encoded bytes.Buffer
+	png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+	// chunks de metadados inseridos logo após o IHDR (assinatura de 8 bytes + IHDR de 25 bytes)
+	data := encoded.Bytes()
+	var original bytes.Buffer
+	original.Write(data[:33])
+	original.Write(pngChunk("eXIf", buildTIFF(3)))
+	original.Write(pngChunk("tEXt", []byte("Author\x00Fulano")))
+	original.Write(pngChunk("sRGB", []byte{0}))
+	original.Write(data[33:])
+
+	if meta := imagemeta.Read(original.Bytes(), "png"); meta.Orientation != 3 {
+		t.Errorf("Orientation = %d, expected 3", meta.Orientation)
+	}
+
+	stripped, err := imagemeta.Strip(original.Bytes(), "png")
+	if err != nil {
+		t.Fatalf("Strip() unexpected error: %v", err)
+	}
+
+	if bytes.Contains(stripped, []byte("eXIf")) || bytes.Contains(stripped, []byte("Fulano")) {
+		t.Errorf("Stripped PNG still contains metadata")
+	}
+	if !bytes.Contains(stripped, []byte("sRGB")) {
+		t.Errorf("Stripped PNG should keep the sRGB chunk")
+	}
+	if _, err := png.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("Stripped PNG should still decode: %v", err)
+	}
+}
+
+func webpChunk(fourCC string, data []byte) []byte {
+	chunk := []byte(fourCC)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(data)))
+	chunk = append(chunk, data...)
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func TestStrip_WebP(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := nativewebp.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 3, 2)), nil); err != nil {
+		t.Fatalf("Failed to encode WebP: %v", err)
+	}
+
+	// converte o WebP simples para o formato estendido (VP8X) com EXIF
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x08
+	vp8x[4] = 2
+	vp8x[7] = 1
+
+	var chunks []byte
+	chunks = append(chunks, webpChunk("VP8X", vp8x)...)
+	chunks = append(chunks, encoded.Bytes()[12:]...)
+	chunks = append(chunks, webpChunk("EXIF", buildTIFF(8))...)
+
+	original := []byte("RIFF")
+	original = binary.LittleEndian.AppendUint32(original, uint32(len(chunks)+4))
+	original = append(original, "WEBP"...)
+	original = append(original, chunks...)
+
+	if meta := imagemeta.Read(original, "webp"); meta.Orientation != 8 {
+		t.Errorf("Orientation = %d, expected 8", meta.Orientation)
+	}
+
+	stripped, err := imagemeta.Strip(original, "webp")
+	if err != nil {
+		t.Fatalf("Strip() unexpected error: %v", err)
+	}
+
+	if bytes.Contains(stripped, []byte("EXIF")) {
+		t.Errorf("Stripped WebP still contains the EXIF chunk")
+	}
+	if stripped[20]&0x08 != 0 {
+		t.Errorf("VP8X EXIF flag should be cleared")
+	}
+	if size := binary.LittleEndian.Uint32(stripped[4:8]); int(size) != len(stripped)-8 {
+		t.Errorf("RIFF size = %d, expected %d", size, len(stripped)-8)
+	}
+	if _, err := webp.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("Stripped WebP should still decode: %v", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// 3x2 com um pixel marcado no canto superior esquerdo
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	marker := color.RGBA{255, 0, 0, 255}
+	img.Set(0, 0, marker)
+
+	tests := []struct {
+		orientation int
+		width       int
+		height      int
+		markerX     int
+		markerY     int
+	}{
+		{1, 3, 2, 0, 0},
+		{2, 3, 2, 2, 0},
+		{3, 3, 2, 2, 1},
+		{4, 3, 2, 0, 1},
+		{5, 2, 3, 0, 0},
+		{6, 2, 3, 1, 0},
+		{7, 2, 3, 1, 2},
+		{8, 2, 3, 0, 2},
+	}
+
+	for _, tt := range tests {
+		oriented := imagemeta.Orient(img, tt.orientation)
+
+		if oriented.Bounds().Dx() != tt.width || oriented.Bounds().Dy() != tt.height {
+			t.Errorf("Orient(%d) size = %v, expected %dx%d", tt.orientation, oriented.Bounds().Size(), tt.width, tt.height)
+			continue
+		}
+
+		if oriented.At(tt.markerX, tt.markerY) != color.Color(marker) {
+			t.Errorf("Orient(%d) marker not at (%d, %d)", tt.orientation, tt.markerX, tt.markerY)
+		}
+	}
+}
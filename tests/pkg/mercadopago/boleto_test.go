@@ -0,0 +1,123 @@
+package mercadopago
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jampa_trip/pkg/mercadopago"
+)
+
+func TestClient_CreateBoletoPayment(t *testing.T) {
+	tests := []struct {
+		name                  string
+		mockResponse          string
+		mockStatus            int
+		expectedError         bool
+		expectedBarcode       string
+		expectedDigitableLine string
+		expectedURL           string
+	}{
+		{
+			name: "Boleto with transaction details",
+			mockResponse: `{
+				"id": 123456789,
+				"status": "pending",
+				"status_detail": "pending_waiting_payment",
+				"transaction_amount": 150.00,
+				"payment_method_id": "bolbradesco",
+				"date_of_expiration": "2024-01-04T23:59:59.000-03:00",
+				"transaction_details": {
+					"external_resource_url": "https://www.mercadopago.com.br/payments/123456789/ticket",
+					"digitable_line": "23790000090000000000000000000000100000000015000",
+					"barcode": {"content": "23791000000000150000000000000000000000000000"}
+				}
+			}`,
+			mockStatus:            http.StatusCreated,
+			expectedBarcode:       "23791000000000150000000000000000000000000000",
+			expectedDigitableLine: "23790000090000000000000000000000100000000015000",
+			expectedURL:           "https://www.mercadopago.com.br/payments/123456789/ticket",
+		},
+		{
+			name: "Boleto with root barcode",
+			mockResponse: `{
+				"id": 987654321,
+				"status": "pending",
+				"status_detail": "pending_waiting_payment",
+				"transaction_amount": 150.00,
+				"payment_method_id": "pec",
+				"barcode": {"content": "00000000000000000000000000000000000000000000"},
+				"transaction_details": {
+					"external_resource_url": "https://www.mercadopago.com.br/payments/987654321/ticket"
+				}
+			}`,
+			mockStatus:      http.StatusCreated,
+			expectedBarcode: "00000000000000000000000000000000000000000000",
+			expectedURL:     "https://www.mercadopago.com.br/payments/987654321/ticket",
+		},
+		{
+			name: "Boleto rejected without address",
+			mockResponse: `{
+				"message": "payer.address.zip_code is required",
+				"error": "bad_request",
+				"status": 400
+			}`,
+			mockStatus:    http.StatusBadRequest,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/payments" {
+					t.Errorf("Expected POST /v1/payments, got %s %s", r.Method, r.URL.Path)
+				}
+
+				var body mercadopago.BoletoRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				if body.Payer.Identification.Type != "CPF" {
+					t.Errorf("Expected payer identification type CPF, got %s", body.Payer.Identification.Type)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.mockStatus)
+				w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			client := mercadopago.NewClient("test-token", server.URL)
+
+			result, err := client.CreateBoletoPayment(context.Background(), &mercadopago.BoletoRequest{
+				TransactionAmount: 150.00,
+				PaymentMethodID:   mercadopago.BoletoBradesco,
+				Payer: mercadopago.BoletoPayer{
+					Email:          "cliente@example.com",
+					Identification: mercadopago.CreditCardIdentification{Type: "CPF", Number: "12345678909"},
+				},
+			})
+
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("CreateBoletoPayment() error = %v, expectedError = %v", err, tt.expectedError)
+			}
+
+			if tt.expectedError {
+				return
+			}
+
+			if result.BarcodeContent() != tt.expectedBarcode {
+				t.Errorf("BarcodeContent() = %s, expected %s", result.BarcodeContent(), tt.expectedBarcode)
+			}
+			if result.TransactionDetails.DigitableLine != tt.expectedDigitableLine {
+				t.Errorf("DigitableLine = %s, expected %s", result.TransactionDetails.DigitableLine, tt.expectedDigitableLine)
+			}
+			if result.TransactionDetails.ExternalResourceURL != tt.expectedURL {
+				t.Errorf("ExternalResourceURL = %s, expected %s", result.TransactionDetails.ExternalResourceURL, tt.expectedURL)
+			}
+		})
+	}
+}
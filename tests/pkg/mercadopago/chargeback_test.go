@@ -0,0 +1,107 @@
+package mercadopago
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jampa_trip/pkg/mercadopago"
+)
+
+func TestClient_GetChargeback(t *testing.T) {
+	tests := []struct {
+		name              string
+		mockResponse      string
+		mockStatus        int
+		expectedError     bool
+		expectedPaymentID string
+		expectedWon       bool
+		expectedLost      bool
+	}{
+		{
+			name: "Chargeback awaiting documentation",
+			mockResponse: `{
+				"id": "211000000001",
+				"payments": [123456789],
+				"currency": "BRL",
+				"amount": 300.00,
+				"coverage_applied": false,
+				"documentation_required": true,
+				"documentation_status": "pending",
+				"date_documentation_deadline": "2024-01-10T23:59:59.000-03:00"
+			}`,
+			mockStatus:        http.StatusOK,
+			expectedPaymentID: "123456789",
+		},
+		{
+			name: "Chargeback covered by Mercado Pago",
+			mockResponse: `{
+				"id": "211000000002",
+				"payments": [987654321],
+				"amount": 150.00,
+				"coverage_applied": true,
+				"documentation_status": "not_supplied"
+			}`,
+			mockStatus:        http.StatusOK,
+			expectedPaymentID: "987654321",
+			expectedWon:       true,
+		},
+		{
+			name: "Chargeback with invalid documentation",
+			mockResponse: `{
+				"id": "211000000003",
+				"payments": [555],
+				"amount": 80.00,
+				"coverage_applied": false,
+				"documentation_status": "invalid"
+			}`,
+			mockStatus:        http.StatusOK,
+			expectedPaymentID: "555",
+			expectedLost:      true,
+		},
+		{
+			name:          "Chargeback not found",
+			mockResponse:  `{"message": "chargeback not found", "error": "not_found", "status": 404}`,
+			mockStatus:    http.StatusNotFound,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v1/chargebacks/211000000001" {
+					t.Errorf("Expected GET /v1/chargebacks/211000000001, got %s %s", r.Method, r.URL.Path)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.mockStatus)
+				w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			client := mercadopago.NewClient("test-token", server.URL)
+
+			result, err := client.GetChargeback(context.Background(), "211000000001")
+
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("GetChargeback() error = %v, expectedError = %v", err, tt.expectedError)
+			}
+
+			if tt.expectedError {
+				return
+			}
+
+			if result.PaymentID() != tt.expectedPaymentID {
+				t.Errorf("PaymentID() = %s, expected %s", result.PaymentID(), tt.expectedPaymentID)
+			}
+			if result.Won() != tt.expectedWon {
+				t.Errorf("Won() = %v, expected %v", result.Won(), tt.expectedWon)
+			}
+			if result.Lost() != tt.expectedLost {
+				t.Errorf("Lost() = %v, expected %v", result.Lost(), tt.expectedLost)
+			}
+		})
+	}
+}
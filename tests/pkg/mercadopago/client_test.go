@@ -177,7 +177,7 @@ func TestClient_CreateOrder(t *testing.T) {
 
 			client := mercadopago.NewClient("test-token", server.URL)
 
-			result, err := client.CreateOrder(tt.orderReq)
+			result, err := client.CreateOrder(context.Background(), tt.orderReq)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("CreateOrder() error = %v, expectedError = %v", err, tt.expectedError)
@@ -256,7 +256,7 @@ func TestClient_GetOrder(t *testing.T) {
 
 			client := mercadopago.NewClient("test-token", server.URL)
 
-			result, err := client.GetOrder(tt.orderID)
+			result, err := client.GetOrder(context.Background(), tt.orderID)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("GetOrder() error = %v, expectedError = %v", err, tt.expectedError)
@@ -348,7 +348,7 @@ func TestClient_CreatePayment(t *testing.T) {
 
 			client := mercadopago.NewClient("test-token", server.URL)
 
-			result, err := client.CreatePayment(tt.paymentReq)
+			result, err := client.CreatePayment(context.Background(), tt.paymentReq)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("CreatePayment() error = %v, expectedError = %v", err, tt.expectedError)
@@ -450,7 +450,7 @@ func TestClient_CreatePIXPayment(t *testing.T) {
 
 			client := mercadopago.NewClient("test-token", server.URL)
 
-			result, err := client.CreatePIXPayment(tt.pixReq)
+			result, err := client.CreatePIXPayment(context.Background(), tt.pixReq)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("CreatePIXPayment() error = %v, expectedError = %v", err, tt.expectedError)
@@ -534,7 +534,7 @@ func TestClient_GetPayment(t *testing.T) {
 
 			client := mercadopago.NewClient("test-token", server.URL)
 
-			result, err := client.GetPayment(tt.paymentID)
+			result, err := client.GetPayment(context.Background(), tt.paymentID)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("GetPayment() error = %v, expectedError = %v", err, tt.expectedError)
@@ -549,6 +549,85 @@ func TestClient_GetPayment(t *testing.T) {
 	}
 }
 
+func TestClient_GetInstallments(t *testing.T) {
+	tests := []struct {
+		name          string
+		amount        float64
+		bin           string
+		mockResponse  string
+		mockStatus    int
+		expectedPlans int
+		expectedError bool
+	}{
+		{
+			name:   "Valid installments retrieval",
+			amount: 540,
+			bin:    "503143",
+			mockResponse: `[{
+				"payment_method_id": "master",
+				"payment_type_id": "credit_card",
+				"issuer": {"id": "24", "name": "Mastercard"},
+				"payer_costs": [
+					{"installments": 1, "installment_rate": 0, "installment_amount": 540, "total_amount": 540, "recommended_message": "1 parcela de R$ 540,00"},
+					{"installments": 12, "installment_rate": 0, "installment_amount": 45, "total_amount": 540, "recommended_message": "12 parcelas de R$ 45,00"}
+				]
+			}]`,
+			mockStatus:    http.StatusOK,
+			expectedPlans: 2,
+			expectedError: false,
+		},
+		{
+			name:   "Invalid bin",
+			amount: 540,
+			bin:    "000000",
+			mockResponse: `{
+				"message": "invalid bin",
+				"error": "bad_request",
+				"status": 400
+			}`,
+			mockStatus:    http.StatusBadRequest,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/payment_methods/installments" {
+					t.Errorf("Expected /v1/payment_methods/installments path, got %s", r.URL.Path)
+				}
+
+				if r.URL.Query().Get("bin") != tt.bin {
+					t.Errorf("Expected bin %s, got %s", tt.bin, r.URL.Query().Get("bin"))
+				}
+
+				if r.URL.Query().Get("amount") != "540.00" {
+					t.Errorf("Expected amount 540.00, got %s", r.URL.Query().Get("amount"))
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.mockStatus)
+				w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			client := mercadopago.NewClient("test-token", server.URL)
+
+			result, err := client.GetInstallments(context.Background(), tt.amount, tt.bin)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("GetInstallments() error = %v, expectedError = %v", err, tt.expectedError)
+			}
+
+			if !tt.expectedError {
+				if len(result) != 1 || len(result[0].PayerCosts) != tt.expectedPlans {
+					t.Errorf("GetInstallments() returned %+v, expected %d plans", result, tt.expectedPlans)
+				}
+			}
+		})
+	}
+}
+
 func TestClient_HTTPErrorHandling(t *testing.T) {
 	t.Run("Network error", func(t *testing.T) {
 		client := mercadopago.NewClient("test-token", "http://invalid-url:9999")
@@ -558,7 +637,7 @@ func TestClient_HTTPErrorHandling(t *testing.T) {
 			TotalAmount:       100.50,
 		}
 
-		_, err := client.CreateOrder(orderReq)
+		_, err := client.CreateOrder(context.Background(), orderReq)
 		if err == nil {
 			t.Errorf("CreateOrder() should have failed with network error")
 		}
@@ -579,7 +658,7 @@ func TestClient_HTTPErrorHandling(t *testing.T) {
 			TotalAmount:       100.50,
 		}
 
-		_, err := client.CreateOrder(orderReq)
+		_, err := client.CreateOrder(context.Background(), orderReq)
 		if err == nil {
 			t.Errorf("CreateOrder() should have failed with timeout error")
 		}
@@ -617,3 +696,29 @@ func TestClient_ContextHandling(t *testing.T) {
 		}
 	})
 }
+
+func TestOrderResponse_PaymentStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		statusDetail string
+		expected     string
+	}{
+		{name: "Processed order", status: "processed", statusDetail: "accredited", expected: "approved"},
+		{name: "Waiting capture", status: "action_required", statusDetail: "waiting_capture", expected: "authorized"},
+		{name: "Waiting payment", status: "action_required", statusDetail: "waiting_payment", expected: "pending"},
+		{name: "Failed order", status: "failed", statusDetail: "rejected_by_issuer", expected: "rejected"},
+		{name: "Canceled order", status: "canceled", statusDetail: "canceled_by_api", expected: "cancelled"},
+		{name: "Expired order", status: "expired", statusDetail: "expired", expected: "cancelled"},
+		{name: "Refunded order", status: "refunded", statusDetail: "refunded", expected: "refunded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &mercadopago.OrderResponse{Status: tt.status, StatusDetail: tt.statusDetail}
+			if got := order.PaymentStatus(); got != tt.expected {
+				t.Errorf("PaymentStatus() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
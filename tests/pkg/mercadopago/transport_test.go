@@ -0,0 +1,184 @@
+package mercadopago
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jampa_trip/pkg/mercadopago"
+)
+
+func testOptions() mercadopago.Options {
+	return mercadopago.Options{
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		RetryMaxDelay:    5 * time.Millisecond,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	keys := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys[r.Header.Get("X-Idempotency-Key")] = true
+
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 123456789, "status": "pending"}`))
+	}))
+	defer server.Close()
+
+	client := mercadopago.NewClientWithOptions("test-token", server.URL, testOptions())
+
+	result, err := client.CreatePIXPayment(context.Background(), &mercadopago.PIXRequest{TransactionAmount: 100})
+	if err != nil {
+		t.Fatalf("CreatePIXPayment() unexpected error: %v", err)
+	}
+
+	if result.ID != 123456789 {
+		t.Errorf("CreatePIXPayment() ID = %d, expected 123456789", result.ID)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+
+	if len(keys) != 1 || keys[""] {
+		t.Errorf("Expected the same idempotency key on every attempt, got %v", keys)
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockStatus    int
+		mockResponse  string
+		expectedErr   error
+		expectedCalls int32
+	}{
+		{
+			name:          "Invalid request is not retried",
+			mockStatus:    http.StatusBadRequest,
+			mockResponse:  `{"message": "invalid transaction_amount", "error": "bad_request", "status": 400}`,
+			expectedErr:   mercadopago.ErrInvalidRequest,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Declined by issuer",
+			mockStatus:    http.StatusBadRequest,
+			mockResponse:  `{"message": "card rejected", "error": "bad_request", "status": 400, "cause": [{"code": "cc_rejected_insufficient_amount", "description": "insufficient amount"}]}`,
+			expectedErr:   mercadopago.ErrDeclined,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Gateway outage after retries",
+			mockStatus:    http.StatusBadGateway,
+			mockResponse:  `bad gateway`,
+			expectedErr:   mercadopago.ErrUnavailable,
+			expectedCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.mockStatus)
+				w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			options := testOptions()
+			options.BreakerThreshold = 0
+			client := mercadopago.NewClientWithOptions("test-token", server.URL, options)
+
+			_, err := client.GetPayment(context.Background(), "123456789")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("GetPayment() error = %v, expected %v", err, tt.expectedErr)
+			}
+
+			var apiErr *mercadopago.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetPayment() error should wrap *mercadopago.APIError, got %T", err)
+			}
+
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	options := testOptions()
+	options.MaxRetries = 0
+	client := mercadopago.NewClientWithOptions("test-token", server.URL, options)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPayment(context.Background(), "123456789"); err == nil {
+			t.Fatalf("GetPayment() should have failed")
+		}
+	}
+
+	// novos clientes da mesma URL compartilham o circuito
+	other := mercadopago.NewClientWithOptions("other-token", server.URL, options)
+
+	_, err := other.GetPayment(context.Background(), "123456789")
+	if !errors.Is(err, mercadopago.ErrCircuitOpen) || !errors.Is(err, mercadopago.ErrUnavailable) {
+		t.Errorf("GetPayment() error = %v, expected open circuit", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected the open circuit to block the request, got %d calls", calls)
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	breaker := mercadopago.NewCircuitBreaker(2, time.Minute)
+	now := time.Now()
+
+	breaker.Failure(now)
+	breaker.Failure(now)
+
+	if err := breaker.Allow(now); !errors.Is(err, mercadopago.ErrCircuitOpen) {
+		t.Errorf("Allow() = %v, expected open circuit", err)
+	}
+
+	later := now.Add(2 * time.Minute)
+	if err := breaker.Allow(later); err != nil {
+		t.Errorf("Allow() after cooldown = %v, expected probe to pass", err)
+	}
+
+	if err := breaker.Allow(later); !errors.Is(err, mercadopago.ErrCircuitOpen) {
+		t.Errorf("Allow() during probe = %v, expected open circuit", err)
+	}
+
+	breaker.Success()
+
+	if err := breaker.Allow(later); err != nil {
+		t.Errorf("Allow() after successful probe = %v, expected closed circuit", err)
+	}
+}
@@ -0,0 +1,78 @@
+package mercadopago
+
+import (
+	"testing"
+
+	"github.com/jampa_trip/pkg/mercadopago"
+)
+
+func TestValidateWebhookSignature(t *testing.T) {
+	secret := "webhook-secret"
+	requestID := "bb56a2f1-6aae-46ac-982e-9dcd3581d08e"
+	dataID := "123456"
+	ts := "1704908010"
+	validSignature := mercadopago.SignWebhook(secret, requestID, dataID, ts)
+
+	tests := []struct {
+		name       string
+		xSignature string
+		requestID  string
+		dataID     string
+		expectErr  bool
+	}{
+		{
+			name:       "Valid signature",
+			xSignature: "ts=" + ts + ",v1=" + validSignature,
+			requestID:  requestID,
+			dataID:     dataID,
+			expectErr:  false,
+		},
+		{
+			name:       "Valid signature with spaces",
+			xSignature: "ts=" + ts + ", v1=" + validSignature,
+			requestID:  requestID,
+			dataID:     dataID,
+			expectErr:  false,
+		},
+		{
+			name:       "Tampered data id",
+			xSignature: "ts=" + ts + ",v1=" + validSignature,
+			requestID:  requestID,
+			dataID:     "654321",
+			expectErr:  true,
+		},
+		{
+			name:       "Different request id",
+			xSignature: "ts=" + ts + ",v1=" + validSignature,
+			requestID:  "other-request",
+			dataID:     dataID,
+			expectErr:  true,
+		},
+		{
+			name:       "Missing v1",
+			xSignature: "ts=" + ts,
+			requestID:  requestID,
+			dataID:     dataID,
+			expectErr:  true,
+		},
+		{
+			name:       "Empty header",
+			xSignature: "",
+			requestID:  requestID,
+			dataID:     dataID,
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mercadopago.ValidateWebhookSignature(secret, tt.xSignature, tt.requestID, tt.dataID)
+			if tt.expectErr && err == nil {
+				t.Error("ValidateWebhookSignature() expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("ValidateWebhookSignature() unexpected error: %v", err)
+			}
+		})
+	}
+}
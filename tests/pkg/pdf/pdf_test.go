@@ -0,0 +1,84 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/jampa_trip/pkg/pdf"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	doc := pdf.New("Recibo REC-000001")
+	page := doc.AddPage()
+	page.Text(50, 50, pdf.HelveticaBold, 12, "Passeio (Praia) à tarde")
+	page.Line(50, 60, 500, 60, 0.5)
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() unexpected error: %v", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+		t.Errorf("Expected PDF header, got %q", data[:9])
+	}
+
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Errorf("Expected %%%%EOF trailer")
+	}
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if match == nil {
+		t.Fatalf("startxref not found")
+	}
+
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref should point to the xref table")
+	}
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	if len(offsets) != 7 {
+		t.Fatalf("Expected 7 objects in xref, got %d", len(offsets))
+	}
+
+	for i, offset := range offsets {
+		position, _ := strconv.Atoi(string(offset[1]))
+		expected := fmt.Sprintf("%d 0 obj", i+1)
+		if !bytes.HasPrefix(data[position:], []byte(expected)) {
+			t.Errorf("xref entry %d should point to %q", i+1, expected)
+		}
+	}
+
+	stream := regexp.MustCompile(`(?s)stream\n(.*)\nendstream`).FindSubmatch(data)
+	if stream == nil {
+		t.Fatalf("content stream not found")
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(stream[1]))
+	if err != nil {
+		t.Fatalf("content stream should be zlib compressed: %v", err)
+	}
+	content, _ := io.ReadAll(reader)
+
+	// parênteses escapados e acento em WinAnsi (à = 0xE0)
+	expected := []byte("(Passeio \\(Praia\\) \xe0 tarde) Tj")
+	if !bytes.Contains(content, expected) {
+		t.Errorf("Expected escaped WinAnsi text in content, got %q", content)
+	}
+}
+
+func TestDocument_BytesWithoutPages(t *testing.T) {
+	data, err := pdf.New("Vazio").Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() unexpected error: %v", err)
+	}
+
+	if !bytes.Contains(data, []byte("/Count 1")) {
+		t.Errorf("Expected a blank page to be added")
+	}
+}
@@ -0,0 +1,79 @@
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/jampa_trip/pkg/placeholder"
+)
+
+// filled - imagem com a cor informada em todos os pixels
+func filled(width, height int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestBlurHash(t *testing.T) {
+	t.Run("encodes the average color", func(t *testing.T) {
+		hash, err := placeholder.BlurHash(filled(16, 12, color.NRGBA{R: 255, A: 255}), 4, 3)
+		if err != nil {
+			t.Fatalf("BlurHash() unexpected error: %v", err)
+		}
+
+		// "L" indica 4x3 componentes e "TI:j" é a média #ff0000 na base 83
+		if len(hash) != 28 || hash[0] != 'L' || hash[2:6] != "TI:j" {
+			t.Errorf("BlurHash() = %s, expected 4x3 components with average #ff0000", hash)
+		}
+	})
+
+	t.Run("encodes the component count and details", func(t *testing.T) {
+		img := filled(16, 12, color.NRGBA{A: 255})
+		for y := 0; y < 12; y++ {
+			for x := 8; x < 16; x++ {
+				img.Set(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+			}
+		}
+
+		hash, err := placeholder.BlurHash(img, 3, 4)
+		if err != nil {
+			t.Fatalf("BlurHash() unexpected error: %v", err)
+		}
+		if len(hash) != 28 || hash[0] != 'T' {
+			t.Errorf("BlurHash() = %s, expected 28 characters for 3x4 components", hash)
+		}
+
+		solid, _ := placeholder.BlurHash(filled(16, 12, color.NRGBA{R: 128, G: 128, B: 128, A: 255}), 3, 4)
+		if hash[6:] == solid[6:] {
+			t.Errorf("BlurHash() = %s, expected details different from a solid image (%s)", hash, solid)
+		}
+	})
+
+	t.Run("rejects invalid components", func(t *testing.T) {
+		if _, err := placeholder.BlurHash(filled(4, 4, color.White), 0, 10); !errors.Is(err, placeholder.ErrInvalidComponents) {
+			t.Errorf("BlurHash() error = %v, expected ErrInvalidComponents", err)
+		}
+	})
+}
+
+func TestDominantColor(t *testing.T) {
+	img := filled(10, 10, color.NRGBA{B: 200, A: 255})
+	for x := 0; x < 10; x++ {
+		img.Set(x, 0, color.NRGBA{R: 250, A: 255})
+		img.Set(x, 1, color.NRGBA{G: 250, A: 10})
+	}
+
+	if got := placeholder.DominantColor(img); got != "#0000c8" {
+		t.Errorf("DominantColor() = %s, expected #0000c8", got)
+	}
+
+	if got := placeholder.DominantColor(filled(4, 4, color.Transparent)); got != "" {
+		t.Errorf("DominantColor() = %s, expected empty for transparent image", got)
+	}
+}
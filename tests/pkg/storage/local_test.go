@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jampa_trip/pkg/storage"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocalStore(dir)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "images/1/foto.jpg", []byte("conteudo"), "image/jpeg"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "images", "1", "foto.jpg")); err != nil {
+		t.Fatalf("Expected file on disk: %v", err)
+	}
+
+	data, err := store.Get(ctx, "images/1/foto.jpg")
+	if err != nil || string(data) != "conteudo" {
+		t.Errorf("Get() = %q, %v; expected stored content", data, err)
+	}
+
+	if err := store.Delete(ctx, "images/1/foto.jpg"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+
+	if _, err := store.Get(ctx, "images/1/foto.jpg"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Get() after delete = %v, expected ErrNotFound", err)
+	}
+
+	if err := store.Delete(ctx, "images/1/foto.jpg"); err != nil {
+		t.Errorf("Delete() of missing key should not fail, got %v", err)
+	}
+}
+
+func TestLocalStore_List(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocalStore(dir)
+	ctx := context.Background()
+
+	for _, key := range []string{"images/1/a.jpg", "images/2/b.jpg", "staging/images/1/c.jpg"} {
+		if err := store.Put(ctx, key, []byte("conteudo"), "image/jpeg"); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+	}
+	// temporário de uma gravação em andamento
+	if err := os.WriteFile(filepath.Join(dir, "images", "1", ".tmp-123"), []byte("x"), 0644); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	objects, err := store.List(ctx, "images/")
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+
+	if len(objects) != 2 || objects[0].Key != "images/1/a.jpg" || objects[1].Key != "images/2/b.jpg" {
+		t.Fatalf("List() = %+v, expected only the stored images", objects)
+	}
+	if objects[0].Size != 8 || objects[0].LastModified.IsZero() {
+		t.Errorf("Expected size and modification time, got %+v", objects[0])
+	}
+
+	objects, err = store.List(ctx, "videos/")
+	if err != nil || len(objects) != 0 {
+		t.Errorf("List() of missing prefix = %v, %v; expected empty", objects, err)
+	}
+}
+
+func TestLocalStore_RejectsInvalidKeys(t *testing.T) {
+	store := storage.NewLocalStore(t.TempDir())
+
+	for _, key := range []string{"", "/etc/passwd", "images/../../segredo", "images//foto.jpg"} {
+		if err := store.Put(context.Background(), key, []byte("x"), ""); err == nil {
+			t.Errorf("Put(%q) should have failed", key)
+		}
+	}
+}
+
+func TestEscapeKey(t *testing.T) {
+	if escaped := storage.EscapeKey("images/1/foto praia (1).jpg"); escaped != "images/1/foto%20praia%20%281%29.jpg" {
+		t.Errorf("EscapeKey() = %s", escaped)
+	}
+}
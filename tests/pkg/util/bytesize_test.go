@@ -0,0 +1,54 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/jampa_trip/pkg/util"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+		wantErr  bool
+	}{
+		{"1048576", 1048576, false},
+		{"500MB", 500 << 20, false},
+		{"1.5GB", 3 << 29, false},
+		{" 2 kb ", 2048, false},
+		{"10B", 10, false},
+		{"muito", 0, true},
+		{"-1GB", 0, true},
+	}
+
+	for _, tt := range tests {
+		size, err := util.ParseByteSize(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseByteSize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if size != tt.expected {
+			t.Errorf("ParseByteSize(%q) = %d, expected %d", tt.value, size, tt.expected)
+		}
+	}
+
+	if size := util.ParseByteSizeOrDefault("", 42); size != 42 {
+		t.Errorf("ParseByteSizeOrDefault() = %d, expected fallback", size)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:           "512 B",
+		1536:          "1.5 KB",
+		980 << 20:     "980.0 MB",
+		1 << 30:       "1.0 GB",
+		(5 << 40) / 2: "2.5 TB",
+	}
+
+	for size, expected := range tests {
+		if formatted := util.FormatBytes(size); formatted != expected {
+			t.Errorf("FormatBytes(%d) = %s, expected %s", size, formatted, expected)
+		}
+	}
+}
@@ -64,7 +64,6 @@ func (f *Fixtures) Tour() *model.Tour {
 		DepartureTime: "08:00",
 		ArrivalTime:   "18:00",
 		MaxPeople:     20,
-		Images:        pq.StringArray{"https://example.com/image1.jpg"},
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}